@@ -2,15 +2,17 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/adexcell/delayed-notifier/config"
-	"github.com/adexcell/delayed-notifier/internal/adapter/postgres"
-	"github.com/adexcell/delayed-notifier/internal/adapter/rabbit"
-	"github.com/adexcell/delayed-notifier/internal/adapter/redis"
+	"github.com/adexcell/delayed-notifier/internal/adapter/metrics"
 	"github.com/adexcell/delayed-notifier/internal/adapter/sender"
 	"github.com/adexcell/delayed-notifier/internal/controller"
 	"github.com/adexcell/delayed-notifier/internal/domain"
@@ -19,6 +21,7 @@ import (
 	"github.com/adexcell/delayed-notifier/pkg/httpserver"
 	"github.com/adexcell/delayed-notifier/pkg/log"
 	"github.com/adexcell/delayed-notifier/pkg/router"
+	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -26,19 +29,34 @@ import (
 type App struct {
 	cfg       *config.Config
 	log       log.Log
-	rabbit    domain.QueueProvider
+	queue     domain.QueueProvider
 	router    *router.Router
 	server    *http.Server
 	scheduler domain.Scheduler
 	worker    *worker.NotifyConsumer
+	health    *controller.HealthHandler
 	closers   []func() error
+
+	// memory - режим --memory: хранилища в памяти и очередь внутри процесса
+	memory bool
+
+	startedAt time.Time
+	consuming atomic.Bool
+}
+
+type Options struct {
+	// Memory запускает сервис без Postgres, Redis и брокера
+	Memory bool
 }
 
-func New() (*App, error) {
+func New(opts Options) (*App, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	if opts.Memory {
+		cfg.Queue.Backend = config.QueuePostgres
+	}
 
 	log := log.New()
 
@@ -46,6 +64,7 @@ func New() (*App, error) {
 		cfg:    cfg,
 		log:    log,
 		router: router.New(cfg.Router),
+		memory: opts.Memory,
 	}, nil
 }
 
@@ -63,8 +82,11 @@ func (a *App) Run() error {
 
 	go a.scheduler.Run(ctx)
 	go func() {
-		if err := a.rabbit.Consume(ctx, a.worker.Handle); err != nil {
-			a.log.Error().Err(err).Msg("RabbitMQ consumer stopped")
+		a.consuming.Store(true)
+		defer a.consuming.Store(false)
+
+		if err := a.queue.Consume(ctx, a.worker.Handle); err != nil {
+			a.log.Error().Err(err).Msg("Queue consumer stopped")
 		}
 	}()
 
@@ -76,58 +98,120 @@ func (a *App) Run() error {
 }
 
 func (a *App) initDependencies() error {
-	// Postgres init
-	postgres, err := postgres.New(a.cfg.Postgres)
+	a.startedAt = time.Now()
+
+	// Storage init: Postgres и Redis или память в режиме --memory
+	storage, err := a.newStorage()
 	if err != nil {
-		return fmt.Errorf("failed to init Postgres: %w", err)
+		return err
 	}
-	a.addCloser(postgres.Close)
-
-	// Redis init
-	redis := redis.New(a.cfg.Redis)
-	a.addCloser(redis.Close)
+	postgres, schedules, templates, redis := storage.notifies, storage.schedules, storage.templates, storage.cache
+	recipients, suppressions := storage.recipients, storage.suppressions
 
-	// Rabbit init, declare Queue
-	rabbit, err := rabbit.NewRabbitQueueAdapter(a.cfg.Rabbit)
+	// Queue init: rabbitmq, kafka, nats или postgres без брокера
+	queue, err := a.newQueue()
 	if err != nil {
-		return fmt.Errorf("failed to connect Rabbit: %w", err)
+		return fmt.Errorf("failed to connect %s queue: %w", a.cfg.Queue.Backend, err)
 	}
-	if err := rabbit.Init(); err != nil {
-		return fmt.Errorf("failed to init Rabbit: %w", err)
+	if err := queue.Init(); err != nil {
+		return fmt.Errorf("failed to init %s queue: %w", a.cfg.Queue.Backend, err)
 	}
-	a.rabbit = rabbit
-	a.addCloser(rabbit.Close)
+	a.queue = queue
+	a.addCloser(queue.Close)
+
+	// Metrics init, отдаются на /metrics
+	metrics := metrics.NewPrometheus()
 
 	// Init Scheduler - producer for notifies
-	a.scheduler = usecase.NewScheduler(postgres, rabbit, a.cfg.Notifier, a.log)
+	a.scheduler = usecase.NewScheduler(postgres, redis, schedules, queue, a.cfg.Notifier, metrics, a.log)
 
 	// Init Worker - consumer for notifies
 	senders := map[string]domain.Sender{
-		"email":    sender.NewEmailSender(a.cfg.Email, a.log),
+		"email":    sender.NewEmailSender(a.cfg.Email, a.cfg.Unsubscribe, a.log),
 		"telegram": sender.NewTelegramSender(a.cfg.Telegram.Token, a.log),
+		"webhook":  sender.NewWebhookSender(a.cfg.Webhook, a.log),
 	}
-	a.worker = worker.NewNotifyConsumer(a.cfg.Notifier, postgres, rabbit, redis, senders, a.log)
+	// email держит открытые SMTP-сессии
+	for _, s := range senders {
+		if closer, ok := s.(io.Closer); ok {
+			a.addCloser(closer.Close)
+		}
+	}
+	a.worker = worker.NewNotifyConsumer(a.cfg.Notifier, postgres, queue, redis, templates, suppressions, senders, storage.limiter, metrics, a.log)
 
 	// Inject dependencies
-	notifyUsecase := usecase.New(postgres, redis, rabbit, a.log)
+	notifyUsecase := usecase.New(postgres, recipients, redis, queue, metrics, a.cfg.Notifier.CacheMode, a.log)
 	notifyHandler := controller.NewNotifyHandler(notifyUsecase, a.log)
+	scheduleUsecase := usecase.NewScheduleUsecase(schedules, redis, a.log)
+	scheduleHandler := controller.NewScheduleHandler(scheduleUsecase, a.log)
+	templateUsecase := usecase.NewTemplateUsecase(templates, a.log)
+	templateHandler := controller.NewTemplateHandler(templateUsecase, a.log)
+	recipientUsecase := usecase.NewRecipientUsecase(recipients, a.log)
+	recipientHandler := controller.NewRecipientHandler(recipientUsecase, a.log)
+	suppressionUsecase := usecase.NewSuppressionUsecase(suppressions, a.cfg.Unsubscribe, a.log)
+	suppressionHandler := controller.NewSuppressionHandler(suppressionUsecase, a.log)
+	a.health = controller.NewHealthHandler(a.livenessChecks(), domain.HealthChecks{
+		"postgres": postgres.Ping,
+		"redis":    redis.Ping,
+		"queue":    queue.Ping,
+	}, a.log)
 
 	// Add static to router, register routers and swagger
 	a.router.Static("/static", "./static")
 	a.router.StaticFile("/", "./static/index.html")
 
-	notifyHandler.Register(a.router)
-
+	a.health.Register(a.router)
+	a.router.GET("/metrics", gin.WrapH(metrics.Handler()))
 	a.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// ссылку отписки открывает получатель письма, доступ к ней дает подпись токена
+	controller.NewUnsubscribeHandler(suppressionUsecase, a.log).Register(a.router)
+
+	// Middleware применяется только к маршрутам, зарегистрированным после Use,
+	// поэтому статика, health, метрики и swagger остаются без аутентификации
+	a.router.Use(controller.NewAuthMiddleware(a.cfg.Auth, a.log))
+
+	notifyHandler.Register(a.router)
+	scheduleHandler.Register(a.router)
+	templateHandler.Register(a.router)
+	recipientHandler.Register(a.router)
+	suppressionHandler.Register(a.router)
 
 	return nil
 }
 
+// livenessChecks проверяют, что планировщик тикает, а консьюмер не остановился
+func (a *App) livenessChecks() domain.HealthChecks {
+	// допускаем пропуск пары тиков, например из-за медленной пачки
+	maxTickAge := 3 * a.cfg.Notifier.Interval
+
+	return domain.HealthChecks{
+		"scheduler": func(context.Context) error {
+			last := a.scheduler.LastTick()
+			if last.IsZero() {
+				last = a.startedAt
+			}
+			if age := time.Since(last); age > maxTickAge {
+				return fmt.Errorf("last tick was %s ago", age.Round(time.Second))
+			}
+			return nil
+		},
+		"consumer": func(context.Context) error {
+			if !a.consuming.Load() {
+				return errors.New("consumer is not running")
+			}
+			return nil
+		},
+	}
+}
+
 func (a *App) addCloser(closer func() error) {
 	a.closers = append(a.closers, closer)
 }
 
 func (a *App) shutdown() {
+	// readiness падает первым, пока сервер еще отвечает
+	a.health.Shutdown()
+
 	for i := len(a.closers) - 1; i >= 0; i-- {
 		if err := a.closers[i](); err != nil {
 			a.log.Error().Err(err).Msg("failed to close resource")
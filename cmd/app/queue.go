@@ -0,0 +1,27 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/config"
+	"github.com/adexcell/delayed-notifier/internal/adapter/nats"
+	"github.com/adexcell/delayed-notifier/internal/adapter/pgqueue"
+	"github.com/adexcell/delayed-notifier/internal/adapter/rabbit"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// newQueue создает провайдер очереди, выбранный в queue.backend
+func (a *App) newQueue() (domain.QueueProvider, error) {
+	switch a.cfg.Queue.Backend {
+	case config.QueuePostgres:
+		return pgqueue.New(a.cfg.Queue.Postgres, a.log), nil
+	case config.QueueKafka:
+		return newKafkaQueue(a.cfg.Kafka, a.log)
+	case config.QueueNATS:
+		return nats.NewNatsQueueAdapter(a.cfg.NATS, a.log)
+	case config.QueueRabbit:
+		return rabbit.NewRabbitQueueAdapter(a.cfg.Rabbit)
+	default:
+		return nil, fmt.Errorf("unknown queue backend %q", a.cfg.Queue.Backend)
+	}
+}
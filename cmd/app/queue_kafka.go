@@ -0,0 +1,14 @@
+//go:build kafka
+
+package app
+
+import (
+	"github.com/adexcell/delayed-notifier/internal/adapter/kafka"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	kafkacfg "github.com/adexcell/delayed-notifier/pkg/kafka"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+func newKafkaQueue(cfg kafkacfg.Config, log log.Log) (domain.QueueProvider, error) {
+	return kafka.NewKafkaQueueAdapter(cfg, log)
+}
@@ -0,0 +1,16 @@
+//go:build !kafka
+
+package app
+
+import (
+	"errors"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/kafka"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+// Клиент Kafka тянет kafka-go и кодеки сжатия, поэтому адаптер собирается только с тегом kafka
+func newKafkaQueue(kafka.Config, log.Log) (domain.QueueProvider, error) {
+	return nil, errors.New("kafka queue backend is not compiled in, build with -tags kafka")
+}
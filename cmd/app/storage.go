@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/memory"
+	"github.com/adexcell/delayed-notifier/internal/adapter/postgres"
+	"github.com/adexcell/delayed-notifier/internal/adapter/redis"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	pgdb "github.com/adexcell/delayed-notifier/pkg/postgres"
+)
+
+type storage struct {
+	notifies     domain.NotifyPostgres
+	schedules    domain.SchedulePostgres
+	templates    domain.TemplatePostgres
+	recipients   domain.RecipientPostgres
+	suppressions domain.SuppressionPostgres
+	cache        domain.NotifyRedis
+	limiter      domain.RateLimiter
+}
+
+// newStorage подключает Postgres и Redis, а в режиме --memory создает хранилища в памяти
+func (a *App) newStorage() (*storage, error) {
+	if a.memory {
+		a.log.Warn().Msg("Memory mode: notifies, schedules, templates, recipients and suppressions are lost on restart")
+
+		db := memory.NewDB()
+		return &storage{
+			notifies:     memory.NewNotifyPostgres(db),
+			schedules:    memory.NewSchedulePostgres(db),
+			templates:    memory.NewTemplatePostgres(db),
+			recipients:   memory.NewRecipientPostgres(db),
+			suppressions: memory.NewSuppressionPostgres(db),
+			cache:        memory.NewRedis(a.cfg.Redis.TTL),
+			limiter:      memory.NewRateLimiter(),
+		}, nil
+	}
+
+	// Postgres init, один пул на все репозитории
+	db, err := pgdb.New(a.cfg.Postgres)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init Postgres: %w", err)
+	}
+	notifies := postgres.NewWithDB(db)
+	a.addCloser(notifies.Close)
+
+	// Redis init
+	cache := redis.New(a.cfg.Redis)
+	a.addCloser(cache.Close)
+	limiter := redis.NewRateLimiter(a.cfg.Redis)
+	a.addCloser(limiter.Close)
+
+	return &storage{
+		notifies:     notifies,
+		schedules:    postgres.NewSchedulePostgres(db),
+		templates:    postgres.NewTemplatePostgres(db),
+		recipients:   postgres.NewRecipientPostgres(db),
+		suppressions: postgres.NewSuppressionPostgres(db),
+		cache:        cache,
+		limiter:      limiter,
+	}, nil
+}
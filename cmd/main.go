@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"github.com/adexcell/delayed-notifier/cmd/app"
@@ -13,7 +14,10 @@ import (
 // @BasePath       /
 
 func main() {
-	app, err := app.New()
+	memory := flag.Bool("memory", false, "run without Postgres, Redis and broker: in-memory storage and in-process queue")
+	flag.Parse()
+
+	app, err := app.New(app.Options{Memory: *memory})
 	if err != nil {
 		log.Fatalf("error: %v", err)
 	}
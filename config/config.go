@@ -1,10 +1,17 @@
 package config
 
 import (
+	"fmt"
+	"os"
 	"time"
 
+	"github.com/adexcell/delayed-notifier/internal/adapter/pgqueue"
 	"github.com/adexcell/delayed-notifier/internal/adapter/sender"
+	"github.com/adexcell/delayed-notifier/internal/controller"
+	"github.com/adexcell/delayed-notifier/internal/domain"
 	"github.com/adexcell/delayed-notifier/pkg/httpserver"
+	"github.com/adexcell/delayed-notifier/pkg/kafka"
+	"github.com/adexcell/delayed-notifier/pkg/nats"
 	"github.com/adexcell/delayed-notifier/pkg/postgres"
 	"github.com/adexcell/delayed-notifier/pkg/rabbit"
 	"github.com/adexcell/delayed-notifier/pkg/redis"
@@ -16,12 +23,19 @@ type Config struct {
 	App        App                   `mapstructure:"app"`
 	HTTPServer httpserver.Config     `mapstructure:"httpserver"`
 	Router     router.Config         `mapstructure:"router"`
+	Auth       controller.AuthConfig `mapstructure:"auth"`
 	Postgres   postgres.Config       `mapstructure:"postgres"`
 	Redis      redis.Config          `mapstructure:"redis"`
+	Queue      QueueConfig           `mapstructure:"queue"`
 	Rabbit     rabbit.Config         `mapstructure:"rabbit"`
+	Kafka      kafka.Config          `mapstructure:"kafka"`
+	NATS       nats.Config           `mapstructure:"nats"`
 	Notifier   NotifierConfig        `mapstructure:"notifier"`
 	Telegram   sender.TelegramConfig `mapstructure:"telegram"`
-	Email sender.EmailConfig    `mapstructure:"email"`
+	Email      sender.EmailConfig    `mapstructure:"email"`
+	Webhook    sender.WebhookConfig  `mapstructure:"webhook"`
+	// Unsubscribe - ссылки отписки в письмах, ведут на публичный /unsubscribe/:token
+	Unsubscribe domain.UnsubscribeConfig `mapstructure:"unsubscribe"`
 }
 
 type App struct {
@@ -29,11 +43,88 @@ type App struct {
 	AppVersion string `mapstructure:"app_version"`
 }
 
+// Бэкенды очереди между планировщиком и воркерами
+const (
+	QueueRabbit = "rabbitmq"
+	// QueuePostgres - без брокера: notify из LockAndFetchReady уходят воркерам этого же процесса
+	QueuePostgres = "postgres"
+	QueueKafka    = "kafka"
+	QueueNATS     = "nats"
+)
+
+type QueueConfig struct {
+	Backend  string         `mapstructure:"backend"`
+	Postgres pgqueue.Config `mapstructure:"postgres"`
+}
+
+func (c QueueConfig) Validate() error {
+	switch c.Backend {
+	case QueueRabbit, QueuePostgres, QueueNATS:
+		return nil
+	case QueueKafka:
+		// без тега адаптера нет в бинарнике: падаем при загрузке конфига, а не при первом подключении
+		if !kafkaCompiled {
+			return fmt.Errorf("backend %q is not compiled in, build with -tags kafka", c.Backend)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown backend %q", c.Backend)
+	}
+}
+
 type NotifierConfig struct {
 	MaxRetries        int           `mapstructure:"max_retries"`
 	VisibilityTimeout time.Duration `mapstructure:"visibility_timeout"`
 	Interval          time.Duration `mapstructure:"interval"`
 	BatchSize         int           `mapstructure:"batch_size"`
+	// WorkerID попадает в историю попыток. По умолчанию hostname-pid
+	WorkerID string `mapstructure:"worker_id"`
+	// CacheMode - как кеш Redis узнает о смене статуса: write_through (по умолчанию) или invalidate
+	CacheMode domain.CacheMode `mapstructure:"cache_mode"`
+
+	// Retry - политика повторов по умолчанию, ChannelRetry - переопределения по каналам.
+	// Если max_attempts не задан, используется MaxRetries (так же и для retry_policy notify).
+	Retry        domain.RetryPolicy            `mapstructure:"retry"`
+	ChannelRetry map[string]domain.RetryPolicy `mapstructure:"channel_retry"`
+
+	// RateLimit - лимиты доставки по каналам, получателям и арендаторам.
+	// Отправка сверх лимита откладывается, а не считается неудачной
+	RateLimit domain.RateLimits `mapstructure:"rate_limit"`
+}
+
+// InstanceID возвращает идентификатор экземпляра для истории попыток
+func (c NotifierConfig) InstanceID() string {
+	if c.WorkerID != "" {
+		return c.WorkerID
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// RetryPolicies собирает политики повторов из конфига
+func (c NotifierConfig) RetryPolicies() domain.RetryPolicies {
+	withDefaults := func(p domain.RetryPolicy) domain.RetryPolicy {
+		if p.Kind == "" {
+			p = domain.DefaultRetryPolicy(p.MaxAttempts)
+		}
+		if p.MaxAttempts == 0 && p.Kind != domain.RetryCustom {
+			p.MaxAttempts = c.MaxRetries
+		}
+		return p
+	}
+
+	policies := domain.RetryPolicies{
+		Default:     withDefaults(c.Retry),
+		Channels:    make(map[string]domain.RetryPolicy, len(c.ChannelRetry)),
+		MaxAttempts: c.MaxRetries,
+	}
+	for channel, p := range c.ChannelRetry {
+		policies.Channels[channel] = withDefaults(p)
+	}
+	return policies
 }
 
 func Load() (*Config, error) {
@@ -52,5 +143,47 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	if err := validateRetryPolicies(res.Notifier.RetryPolicies()); err != nil {
+		return nil, err
+	}
+
+	if err := res.Notifier.RateLimit.Validate(); err != nil {
+		return nil, fmt.Errorf("notifier.rate_limit: %w", err)
+	}
+
+	if res.Notifier.CacheMode == "" {
+		res.Notifier.CacheMode = domain.CacheWriteThrough
+	}
+	if err := res.Notifier.CacheMode.Validate(); err != nil {
+		return nil, fmt.Errorf("notifier.cache_mode: %w", err)
+	}
+
+	if err := res.Unsubscribe.Validate(); err != nil {
+		return nil, fmt.Errorf("unsubscribe: %w", err)
+	}
+
+	if err := res.Auth.Validate(); err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	if res.Queue.Backend == "" {
+		res.Queue.Backend = QueueRabbit
+	}
+	if err := res.Queue.Validate(); err != nil {
+		return nil, fmt.Errorf("queue: %w", err)
+	}
+
 	return &res, nil
 }
+
+func validateRetryPolicies(p domain.RetryPolicies) error {
+	if err := p.Default.Validate(); err != nil {
+		return fmt.Errorf("notifier.retry: %w", err)
+	}
+	for channel, policy := range p.Channels {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("notifier.channel_retry.%s: %w", channel, err)
+		}
+	}
+	return nil
+}
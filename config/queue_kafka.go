@@ -0,0 +1,6 @@
+//go:build kafka
+
+package config
+
+// kafkaCompiled - адаптер Kafka собран в бинарник (тег kafka)
+const kafkaCompiled = true
@@ -0,0 +1,5 @@
+//go:build !kafka
+
+package config
+
+const kafkaCompiled = false
@@ -0,0 +1,39 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+type NotifyKafkaDTO struct {
+	ID          string        `json:"id"`
+	Payload     []byte        `json:"payload"`
+	Target      string        `json:"target"`
+	Channel     string        `json:"channel"`
+	Status      domain.Status `json:"status"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	RetryCount  int           `json:"retry_count"`
+	TenantID    string        `json:"tenant_id,omitempty"`
+	LastError   *string       `json:"last_error"`
+}
+
+func toKafkaDTO(n *domain.Notify) *NotifyKafkaDTO {
+	return &NotifyKafkaDTO{
+		ID:          n.ID,
+		Payload:     n.Payload,
+		Target:      n.Target,
+		Channel:     n.Channel,
+		Status:      n.Status,
+		ScheduledAt: n.ScheduledAt,
+		CreatedAt:   n.CreatedAt,
+		UpdatedAt:   n.UpdatedAt,
+		RetryCount:  n.RetryCount,
+		TenantID:    n.TenantID,
+		LastError:   n.LastError,
+	}
+}
@@ -0,0 +1,204 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/kafka"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	kafkago "github.com/segmentio/kafka-go"
+	wbfkafka "github.com/wb-go/wbf/kafka"
+	"github.com/wb-go/wbf/retry"
+)
+
+const (
+	defaultTopic   = "notifications"
+	defaultGroupID = "notifier-worker"
+
+	// batchTimeout - сколько писатель ждет добора пачки. По умолчанию в kafka-go
+	// это секунда, и каждая синхронная публикация планировщика ждала бы ее целиком
+	batchTimeout = 10 * time.Millisecond
+)
+
+var defaultStrategy = retry.Strategy{Attempts: 3, Delay: time.Second, Backoff: 1.5}
+
+type NotifyQueueAdapter struct {
+	cfg      kafka.Config
+	producer *wbfkafka.Producer
+	log      log.Log
+}
+
+func NewKafkaQueueAdapter(cfg kafka.Config, log log.Log) (domain.QueueProvider, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka brokers are not set")
+	}
+	if cfg.Topic == "" {
+		cfg.Topic = defaultTopic
+	}
+	if cfg.GroupID == "" {
+		cfg.GroupID = defaultGroupID
+	}
+	if cfg.Partitions <= 0 {
+		cfg.Partitions = 1
+	}
+	if cfg.ReplicationFactor <= 0 {
+		cfg.ReplicationFactor = 1
+	}
+	if cfg.ProducingStrat.Attempts <= 0 {
+		cfg.ProducingStrat = defaultStrategy
+	}
+	if cfg.ConsumingStrat.Attempts <= 0 {
+		cfg.ConsumingStrat = defaultStrategy
+	}
+
+	producer := wbfkafka.NewProducer(cfg.Brokers, cfg.Topic)
+	producer.Writer.BatchTimeout = batchTimeout
+	producer.Writer.RequiredAcks = kafkago.RequireAll
+
+	return &NotifyQueueAdapter{
+		cfg:      cfg,
+		producer: producer,
+		log:      log,
+	}, nil
+}
+
+// Init создает топик через контроллер кластера, если его еще нет
+func (q *NotifyQueueAdapter) Init() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := q.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		return fmt.Errorf("failed to get kafka controller: %w", err)
+	}
+
+	controllerConn, err := kafkago.DialContext(ctx, "tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		return fmt.Errorf("failed to connect kafka controller: %w", err)
+	}
+	defer controllerConn.Close()
+
+	err = controllerConn.CreateTopics(kafkago.TopicConfig{
+		Topic:             q.cfg.Topic,
+		NumPartitions:     q.cfg.Partitions,
+		ReplicationFactor: q.cfg.ReplicationFactor,
+	})
+	if err != nil && !errors.Is(err, kafkago.TopicAlreadyExists) {
+		return fmt.Errorf("failed to create topic %s: %w", q.cfg.Topic, err)
+	}
+	return nil
+}
+
+// Publish пишет notify с ключом ID: повторные публикации одного notify
+// попадают в ту же партицию
+func (q *NotifyQueueAdapter) Publish(ctx context.Context, n *domain.Notify) error {
+	body, err := json.Marshal(toKafkaDTO(n))
+	if err != nil {
+		return fmt.Errorf("marshal notify: %w", err)
+	}
+
+	return q.producer.SendWithRetry(ctx, q.cfg.ProducingStrat, []byte(n.ID), body)
+}
+
+// Consume читает топик в группе консьюмеров. В Kafka нет отложенной доставки,
+// поэтому сообщение с будущим scheduled_at держится до наступления времени.
+// Оффсет коммитится после обработки; если обработчик так и не справился,
+// notify останется InProcess и будет повторно выбран планировщиком после visibility timeout
+func (q *NotifyQueueAdapter) Consume(ctx context.Context, handler domain.MessageHandler) error {
+	consumer := wbfkafka.NewConsumer(q.cfg.Brokers, q.cfg.Topic, q.cfg.GroupID)
+	defer consumer.Close()
+
+	for {
+		msg, err := consumer.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to fetch message: %w", err)
+		}
+
+		if err := waitScheduled(ctx, msg.Value); err != nil {
+			return err
+		}
+
+		err = retry.DoContext(ctx, q.cfg.ConsumingStrat, func() error {
+			return handler(ctx, msg.Value)
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			q.log.Error().Err(err).Str("key", string(msg.Key)).Msg("kafka: handler failed, dropping message")
+		}
+
+		if err := consumer.Commit(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to commit offset: %w", err)
+		}
+	}
+}
+
+func (q *NotifyQueueAdapter) Ping(ctx context.Context) error {
+	conn, err := q.dial(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (q *NotifyQueueAdapter) Close() error {
+	return q.producer.Close()
+}
+
+// dial подключается к первому доступному брокеру
+func (q *NotifyQueueAdapter) dial(ctx context.Context) (*kafkago.Conn, error) {
+	var errs []error
+	for _, broker := range q.cfg.Brokers {
+		conn, err := kafkago.DialContext(ctx, "tcp", broker)
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("failed to connect kafka: %w", errors.Join(errs...))
+}
+
+// waitScheduled ждет scheduled_at сообщения. Битое сообщение не задерживается:
+// с ним разберется обработчик
+func waitScheduled(ctx context.Context, body []byte) error {
+	var dto NotifyKafkaDTO
+	if err := json.Unmarshal(body, &dto); err != nil {
+		return nil
+	}
+
+	delay := time.Until(dto.ScheduledAt)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,38 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/queuetest"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/kafka"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+// Тесты с брокером запускаются, только если задан KAFKA_BROKERS
+func TestKafkaQueue_Conformance(t *testing.T) {
+	brokers := os.Getenv("KAFKA_BROKERS")
+	if brokers == "" {
+		t.Skip("KAFKA_BROKERS is not set")
+	}
+
+	queuetest.Run(t, func(t *testing.T) domain.QueueProvider {
+		q, err := NewKafkaQueueAdapter(kafka.Config{
+			Brokers: strings.Split(brokers, ","),
+			Topic:   "notifications_test",
+			GroupID: "queuetest",
+		}, log.New())
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		if err := q.Init(); err != nil {
+			t.Fatalf("init: %v", err)
+		}
+		t.Cleanup(func() { q.Close() })
+		return q
+	})
+}
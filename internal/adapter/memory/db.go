@@ -0,0 +1,98 @@
+// Package memory - потокобезопасные реализации хранилищ в памяти для локального
+// запуска (--memory) и тестов. Поведение повторяет адаптеры Postgres и Redis
+// и проверяется теми же контрактными тестами.
+package memory
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// DB - общее состояние репозиториев, аналог одной базы Postgres:
+// удаление серии отменяет ее notify, материализация создает notify.
+// Все операции выполняются под одной блокировкой, поэтому выборка
+// в LockAndFetchReady атомарна так же, как FOR UPDATE SKIP LOCKED
+type DB struct {
+	mu           sync.Mutex
+	notifies     map[string]*notifyRecord
+	attempts     map[string][]*domain.Attempt
+	schedules    map[string]*domain.Schedule
+	templates    []*domain.Template
+	recipients   map[string]*domain.Recipient
+	suppressions map[string]*domain.Suppression
+	now          func() time.Time
+}
+
+type notifyRecord struct {
+	notify  *domain.Notify
+	history []domain.ErrorRecord
+}
+
+func NewDB() *DB {
+	return &DB{
+		notifies:     make(map[string]*notifyRecord),
+		attempts:     make(map[string][]*domain.Attempt),
+		schedules:    make(map[string]*domain.Schedule),
+		recipients:   make(map[string]*domain.Recipient),
+		suppressions: make(map[string]*domain.Suppression),
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func cloneNotify(n *domain.Notify) *domain.Notify {
+	c := *n
+	c.Payload = append([]byte(nil), n.Payload...)
+	if n.LastError != nil {
+		lastErr := *n.LastError
+		c.LastError = &lastErr
+	}
+	if n.RetryPolicy != nil {
+		policy := *n.RetryPolicy
+		c.RetryPolicy = &policy
+	}
+	if n.DeliveryWindow != nil {
+		window := *n.DeliveryWindow
+		window.Weekdays = append([]time.Weekday(nil), n.DeliveryWindow.Weekdays...)
+		c.DeliveryWindow = &window
+	}
+	c.Channels = nil
+	c.Fallbacks = slices.Clone(n.Fallbacks)
+	c.Message = nil
+	return &c
+}
+
+func cloneSchedule(s *domain.Schedule) *domain.Schedule {
+	c := *s
+	c.Payload = append([]byte(nil), s.Payload...)
+	if s.EndAt != nil {
+		endAt := *s.EndAt
+		c.EndAt = &endAt
+	}
+	if s.NextRunAt != nil {
+		next := *s.NextRunAt
+		c.NextRunAt = &next
+	}
+	return &c
+}
+
+func cloneRecipient(r *domain.Recipient) *domain.Recipient {
+	c := *r
+	c.Contacts = maps.Clone(r.Contacts)
+	c.Channels = slices.Clone(r.Channels)
+	c.OptOuts = slices.Clone(r.OptOuts)
+	if r.DeliveryWindow != nil {
+		window := *r.DeliveryWindow
+		window.Weekdays = slices.Clone(r.DeliveryWindow.Weekdays)
+		c.DeliveryWindow = &window
+	}
+	return &c
+}
+
+func cloneTemplate(t *domain.Template) *domain.Template {
+	c := *t
+	return &c
+}
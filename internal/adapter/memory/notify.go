@@ -0,0 +1,516 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+type NotifyPostgres struct {
+	db *DB
+}
+
+func NewNotifyPostgres(db *DB) domain.NotifyPostgres {
+	return &NotifyPostgres{db: db}
+}
+
+func (p *NotifyPostgres) Create(ctx context.Context, n *domain.Notify) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	n.TenantID = domain.TenantFor(ctx, n.TenantID)
+	if _, ok := p.db.notifies[n.ID]; ok {
+		return domain.ErrNotifyAlreadyExists
+	}
+	if n.IdempotencyKey != "" && p.findByIdempotencyKey(n.TenantID, n.IdempotencyKey) != nil {
+		return domain.ErrNotifyAlreadyExists
+	}
+
+	stored := cloneNotify(n)
+	// updated_at при вставке не задается, в Postgres он читается как created_at
+	stored.UpdatedAt = stored.CreatedAt
+	stored.Version = 1
+	stored.RetryCount = 0
+	stored.LastError = nil
+	p.db.notifies[n.ID] = &notifyRecord{notify: stored}
+	return nil
+}
+
+// CreateBatch повторяет семантику INSERT ... ON CONFLICT DO NOTHING в транзакции:
+// конфликтующие notify пропускаются, при atomic пачка не вставляется вовсе
+func (p *NotifyPostgres) CreateBatch(ctx context.Context, notifies []*domain.Notify, atomic bool) ([]string, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	type tenantKey struct{ tenant, key string }
+	ids := make(map[string]bool)
+	keys := make(map[tenantKey]bool)
+	var accepted []*domain.Notify
+	for _, n := range notifies {
+		n.TenantID = domain.TenantFor(ctx, n.TenantID)
+		key := tenantKey{n.TenantID, n.IdempotencyKey}
+		if _, ok := p.db.notifies[n.ID]; ok || ids[n.ID] {
+			continue
+		}
+		if n.IdempotencyKey != "" && (keys[key] || p.findByIdempotencyKey(n.TenantID, n.IdempotencyKey) != nil) {
+			continue
+		}
+		ids[n.ID] = true
+		if n.IdempotencyKey != "" {
+			keys[key] = true
+		}
+		accepted = append(accepted, n)
+	}
+
+	inserted := make([]string, 0, len(accepted))
+	for _, n := range accepted {
+		inserted = append(inserted, n.ID)
+	}
+	if atomic && len(accepted) < len(notifies) {
+		return inserted, domain.ErrBatchConflict
+	}
+
+	for _, n := range accepted {
+		stored := cloneNotify(n)
+		stored.UpdatedAt = stored.CreatedAt
+		stored.Version = 1
+		stored.RetryCount = 0
+		stored.LastError = nil
+		p.db.notifies[n.ID] = &notifyRecord{notify: stored}
+	}
+	return inserted, nil
+}
+
+func (p *NotifyPostgres) GetNotifyByID(ctx context.Context, id string) (*domain.Notify, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	rec, ok := p.get(ctx, id)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return cloneNotify(rec.notify), nil
+}
+
+func (p *NotifyPostgres) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Notify, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	rec := p.findByIdempotencyKey(domain.TenantFor(ctx, ""), key)
+	if rec == nil {
+		return nil, domain.ErrNotFound
+	}
+	return cloneNotify(rec.notify), nil
+}
+
+// findByIdempotencyKey - ключи уникальны в пределах арендатора
+func (p *NotifyPostgres) findByIdempotencyKey(tenant, key string) *notifyRecord {
+	for _, rec := range p.db.notifies {
+		if rec.notify.TenantID == tenant && rec.notify.IdempotencyKey == key {
+			return rec
+		}
+	}
+	return nil
+}
+
+// get возвращает notify, если он виден арендатору из контекста
+func (p *NotifyPostgres) get(ctx context.Context, id string) (*notifyRecord, bool) {
+	rec, ok := p.db.notifies[id]
+	if !ok || !domain.Visible(ctx, rec.notify.TenantID) {
+		return nil, false
+	}
+	return rec, true
+}
+
+func (p *NotifyPostgres) UpdateStatus(
+	ctx context.Context,
+	id string,
+	status domain.Status,
+	scheduledAt *time.Time,
+	retryCount int,
+	lastErr *string,
+) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	rec, ok := p.get(ctx, id)
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if rec.notify.Status == domain.StatusCanceled {
+		return domain.ErrNotifyCanceled
+	}
+
+	now := p.db.now()
+	n := rec.notify
+	n.Status = status
+	if scheduledAt != nil {
+		n.ScheduledAt = *scheduledAt
+	}
+	n.RetryCount = retryCount
+	n.LastError = nil
+	if lastErr != nil {
+		e := *lastErr
+		n.LastError = &e
+		rec.history = append(rec.history, domain.ErrorRecord{At: now, Attempt: retryCount, Error: e})
+	}
+	n.UpdatedAt = now
+	n.Version++
+	return nil
+}
+
+// Reschedule возвращает notify в Pending на scheduledAt, не трогая повторы и историю ошибок
+func (p *NotifyPostgres) Reschedule(ctx context.Context, id string, scheduledAt time.Time) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	rec, ok := p.get(ctx, id)
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if rec.notify.Status == domain.StatusCanceled {
+		return domain.ErrNotifyCanceled
+	}
+
+	n := rec.notify
+	n.Status = domain.StatusPending
+	n.ScheduledAt = scheduledAt
+	n.UpdatedAt = p.db.now()
+	n.Version++
+	return nil
+}
+
+// Update повторяет условное UPDATE из Postgres: только Pending и совпадающая версия
+func (p *NotifyPostgres) Update(ctx context.Context, id string, patch domain.NotifyPatch, version int) (*domain.Notify, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	rec, ok := p.get(ctx, id)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	n := rec.notify
+	if n.Status != domain.StatusPending {
+		return nil, domain.ErrNotifyNotEditable
+	}
+	if version != 0 && n.Version != version {
+		return nil, domain.ErrVersionMismatch
+	}
+
+	if patch.Payload != nil {
+		n.Payload = append([]byte(nil), patch.Payload...)
+	}
+	if patch.Target != nil {
+		n.Target = *patch.Target
+	}
+	if patch.Channel != nil {
+		n.Channel = *patch.Channel
+	}
+	if patch.ScheduledAt != nil {
+		n.ScheduledAt = *patch.ScheduledAt
+	}
+	n.UpdatedAt = p.db.now()
+	n.Version++
+	return cloneNotify(n), nil
+}
+
+// Cancel переводит Pending/InProcess notify в StatusCanceled.
+// Повторная отмена уже отмененного notify не считается ошибкой.
+func (p *NotifyPostgres) Cancel(ctx context.Context, id string) (*domain.Notify, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	rec, ok := p.get(ctx, id)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	n := rec.notify
+	switch n.Status {
+	case domain.StatusPending, domain.StatusInProcess, domain.StatusCanceled:
+		n.Status = domain.StatusCanceled
+		n.UpdatedAt = p.db.now()
+		n.Version++
+		return cloneNotify(n), nil
+	default:
+		return nil, domain.ErrNotifyNotCancelable
+	}
+}
+
+func (p *NotifyPostgres) DeleteByID(ctx context.Context, id string) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	if _, ok := p.get(ctx, id); !ok {
+		return nil
+	}
+	delete(p.db.notifies, id)
+	delete(p.db.attempts, id)
+	return nil
+}
+
+// LockAndFetchReady захватывает наступившие Pending notify и InProcess,
+// зависшие дольше visibilityTimeout, в порядке scheduled_at
+func (p *NotifyPostgres) LockAndFetchReady(ctx context.Context, limit int, visibilityTimeout time.Duration) ([]*domain.Notify, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	now := p.db.now()
+	staleBefore := now.Add(-visibilityTimeout)
+
+	var ready []*domain.Notify
+	for _, rec := range p.db.notifies {
+		n := rec.notify
+		if !domain.Visible(ctx, n.TenantID) {
+			continue
+		}
+		due := n.Status == domain.StatusPending && !n.ScheduledAt.After(now)
+		stale := n.Status == domain.StatusInProcess && !n.UpdatedAt.After(staleBefore)
+		if due || stale {
+			ready = append(ready, n)
+		}
+	}
+	slices.SortFunc(ready, func(a, b *domain.Notify) int {
+		return cmp.Or(a.ScheduledAt.Compare(b.ScheduledAt), cmp.Compare(a.ID, b.ID))
+	})
+	if len(ready) > limit {
+		ready = ready[:limit]
+	}
+
+	results := make([]*domain.Notify, 0, len(ready))
+	for _, n := range ready {
+		n.Status = domain.StatusInProcess
+		n.UpdatedAt = now
+		n.Version++
+		results = append(results, cloneNotify(n))
+	}
+	return results, nil
+}
+
+func (p *NotifyPostgres) CountPending(ctx context.Context) (int, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	var count int
+	for _, rec := range p.db.notifies {
+		if rec.notify.Status == domain.StatusPending && domain.Visible(ctx, rec.notify.TenantID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (p *NotifyPostgres) List(ctx context.Context, filter domain.NotifyFilter) ([]*domain.Notify, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	var results []*domain.Notify
+	for _, rec := range p.selectPage(ctx, filter) {
+		results = append(results, cloneNotify(rec.notify))
+	}
+	return results, nil
+}
+
+func (p *NotifyPostgres) ListDeadLetters(ctx context.Context, filter domain.NotifyFilter) ([]*domain.DeadLetter, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	filter.Statuses = []domain.Status{domain.StatusFailed}
+
+	var results []*domain.DeadLetter
+	for _, rec := range p.selectPage(ctx, filter) {
+		results = append(results, &domain.DeadLetter{
+			Notify: cloneNotify(rec.notify),
+			Errors: slices.Clone(rec.history),
+		})
+	}
+	return results, nil
+}
+
+// Requeue возвращает StatusFailed notify в очередь. last_error и история ошибок сохраняются.
+func (p *NotifyPostgres) Requeue(ctx context.Context, id string) (*domain.Notify, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	rec, ok := p.get(ctx, id)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if rec.notify.Status != domain.StatusFailed {
+		return nil, domain.ErrNotifyNotRetryable
+	}
+
+	p.requeue(rec.notify)
+	return cloneNotify(rec.notify), nil
+}
+
+// Fallback переключает notify на первый запасной маршрут, как одно UPDATE в Postgres
+func (p *NotifyPostgres) Fallback(ctx context.Context, id string, lastErr *string) (*domain.Notify, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	rec, ok := p.get(ctx, id)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	n := rec.notify
+	if n.Status == domain.StatusSent || n.Status == domain.StatusCanceled || len(n.Fallbacks) == 0 {
+		return nil, domain.ErrNoRoute
+	}
+
+	now := p.db.now()
+	n.LastError = nil
+	if lastErr != nil {
+		e := *lastErr
+		n.LastError = &e
+		rec.history = append(rec.history, domain.ErrorRecord{At: now, Attempt: n.RetryCount, Error: e})
+	}
+	n.Channel = n.Fallbacks[0].Channel
+	n.Target = n.Fallbacks[0].Target
+	n.Fallbacks = n.Fallbacks[1:]
+	if len(n.Fallbacks) == 0 {
+		n.Fallbacks = nil
+	}
+	n.Status = domain.StatusPending
+	n.RetryCount = 0
+	n.ScheduledAt = now
+	n.UpdatedAt = now
+	n.Version++
+	return cloneNotify(n), nil
+}
+
+// RequeueFailed делает Requeue для всех failed notify по фильтру.
+// Сортировка, лимит и курсор фильтра не учитываются.
+func (p *NotifyPostgres) RequeueFailed(ctx context.Context, filter domain.NotifyFilter) ([]string, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	filter.Statuses = []domain.Status{domain.StatusFailed}
+
+	var ids []string
+	for _, rec := range p.db.notifies {
+		if domain.Visible(ctx, rec.notify.TenantID) && matches(rec.notify, filter) {
+			p.requeue(rec.notify)
+			ids = append(ids, rec.notify.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (p *NotifyPostgres) requeue(n *domain.Notify) {
+	now := p.db.now()
+	n.Status = domain.StatusPending
+	n.RetryCount = 0
+	n.ScheduledAt = now
+	n.UpdatedAt = now
+	n.Version++
+}
+
+func (p *NotifyPostgres) RecordAttempt(ctx context.Context, a *domain.Attempt) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	if _, ok := p.db.notifies[a.NotifyID]; !ok {
+		return fmt.Errorf("failed to record attempt: %w", domain.ErrNotFound)
+	}
+
+	stored := *a
+	p.db.attempts[a.NotifyID] = append(p.db.attempts[a.NotifyID], &stored)
+	return nil
+}
+
+func (p *NotifyPostgres) ListAttempts(ctx context.Context, notifyID string) ([]*domain.Attempt, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	if _, ok := p.get(ctx, notifyID); !ok {
+		return nil, nil
+	}
+
+	var results []*domain.Attempt
+	for _, a := range p.db.attempts[notifyID] {
+		c := *a
+		results = append(results, &c)
+	}
+	slices.SortStableFunc(results, func(a, b *domain.Attempt) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return results, nil
+}
+
+func (p *NotifyPostgres) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (p *NotifyPostgres) Close() error {
+	return nil
+}
+
+// selectPage - аналог buildSelectQuery: фильтр, сортировка с notify_id
+// для строгого порядка, курсор или offset и лимит
+func (p *NotifyPostgres) selectPage(ctx context.Context, f domain.NotifyFilter) []*notifyRecord {
+	sortValue := func(n *domain.Notify) time.Time {
+		if f.SortBy == domain.SortByScheduledAt {
+			return n.ScheduledAt
+		}
+		return n.CreatedAt
+	}
+	compare := func(aValue time.Time, aID string, bValue time.Time, bID string) int {
+		c := cmp.Or(aValue.Compare(bValue), cmp.Compare(aID, bID))
+		if f.Desc {
+			return -c
+		}
+		return c
+	}
+
+	var selected []*notifyRecord
+	for _, rec := range p.db.notifies {
+		n := rec.notify
+		if !domain.Visible(ctx, n.TenantID) || !matches(n, f) {
+			continue
+		}
+		if f.Cursor != nil && compare(sortValue(n), n.ID, f.Cursor.Value, f.Cursor.ID) <= 0 {
+			continue
+		}
+		selected = append(selected, rec)
+	}
+	slices.SortFunc(selected, func(a, b *notifyRecord) int {
+		return compare(sortValue(a.notify), a.notify.ID, sortValue(b.notify), b.notify.ID)
+	})
+
+	if f.Cursor == nil && f.Offset > 0 {
+		selected = selected[min(f.Offset, len(selected)):]
+	}
+	return selected[:min(max(f.Limit, 0), len(selected))]
+}
+
+// matches - аналог filterConditions
+func matches(n *domain.Notify, f domain.NotifyFilter) bool {
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, n.Status) {
+		return false
+	}
+	if f.Channel != "" && n.Channel != f.Channel {
+		return false
+	}
+	if f.Target != "" && n.Target != f.Target {
+		return false
+	}
+	if f.ScheduledFrom != nil && n.ScheduledAt.Before(*f.ScheduledFrom) {
+		return false
+	}
+	if f.ScheduledTo != nil && !n.ScheduledAt.Before(*f.ScheduledTo) {
+		return false
+	}
+	if f.CreatedFrom != nil && n.CreatedAt.Before(*f.CreatedFrom) {
+		return false
+	}
+	if f.CreatedTo != nil && !n.CreatedAt.Before(*f.CreatedTo) {
+		return false
+	}
+	return true
+}
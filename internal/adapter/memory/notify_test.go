@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/storetest"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+func TestNotifyPostgres_Contract(t *testing.T) {
+	storetest.RunNotifyPostgres(t, func(t *testing.T) domain.NotifyPostgres {
+		return NewNotifyPostgres(NewDB())
+	})
+}
+
+func TestRecipientPostgres_Contract(t *testing.T) {
+	storetest.RunRecipientPostgres(t, func(t *testing.T) domain.RecipientPostgres {
+		return NewRecipientPostgres(NewDB())
+	})
+}
+
+func TestSuppressionPostgres_Contract(t *testing.T) {
+	storetest.RunSuppressionPostgres(t, func(t *testing.T) domain.SuppressionPostgres {
+		return NewSuppressionPostgres(NewDB())
+	})
+}
+
+func TestTemplatePostgres_Contract(t *testing.T) {
+	storetest.RunTemplatePostgres(t, func(t *testing.T) domain.TemplatePostgres {
+		return NewTemplatePostgres(NewDB())
+	})
+}
+
+func TestSchedulePostgres_Contract(t *testing.T) {
+	storetest.RunSchedulePostgres(t, func(t *testing.T) (domain.SchedulePostgres, domain.NotifyPostgres) {
+		db := NewDB()
+		return NewSchedulePostgres(db), NewNotifyPostgres(db)
+	})
+}
+
+func TestRedis_Contract(t *testing.T) {
+	storetest.RunNotifyRedis(t, func(t *testing.T) domain.NotifyRedis {
+		return NewRedis(time.Hour)
+	})
+}
+
+func TestRateLimiter_Contract(t *testing.T) {
+	storetest.RunRateLimiter(t, func(t *testing.T) domain.RateLimiter {
+		return NewRateLimiter()
+	})
+}
+
+func TestRedis_Expires(t *testing.T) {
+	now := time.Now()
+	r := &Redis{items: make(map[string]cacheItem), expiration: time.Minute, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	n := domain.NewNotify()
+	if err := r.SetWithExpiration(ctx, n); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	// Act
+	now = now.Add(time.Minute)
+
+	// Assert
+	if _, err := r.Get(ctx, n.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after TTL, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// RateLimiter - token bucket в памяти процесса, тот же алгоритм, что у лимитера на Redis
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	ts     time.Time
+}
+
+func NewRateLimiter() domain.RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (l *RateLimiter) Take(ctx context.Context, buckets []domain.RateBucket) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	tokens := make([]float64, len(buckets))
+	for i, b := range buckets {
+		capacity := float64(b.Limit.Capacity())
+		interval := b.Limit.Interval()
+
+		t := capacity
+		if state, ok := l.buckets[b.Key]; ok {
+			elapsed := max(0, now.Sub(state.ts))
+			t = math.Min(capacity, state.tokens+float64(elapsed)/float64(interval))
+		}
+		if t < 1 {
+			wait = max(wait, time.Duration(math.Ceil((1-t)*float64(interval))))
+		}
+		tokens[i] = t
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+
+	for i, b := range buckets {
+		l.buckets[b.Key] = tokenBucket{tokens: tokens[i] - 1, ts: now}
+	}
+	return 0, nil
+}
+
+func (l *RateLimiter) Close() error {
+	return nil
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+type RecipientPostgres struct {
+	db *DB
+}
+
+func NewRecipientPostgres(db *DB) domain.RecipientPostgres {
+	return &RecipientPostgres{db: db}
+}
+
+func (p *RecipientPostgres) CreateRecipient(ctx context.Context, r *domain.Recipient) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	if _, ok := p.db.recipients[r.ID]; ok {
+		return fmt.Errorf("failed to create recipient: duplicate id %s", r.ID)
+	}
+
+	r.TenantID = domain.TenantFor(ctx, r.TenantID)
+
+	stored := cloneRecipient(r)
+	stored.UpdatedAt = stored.CreatedAt
+	p.db.recipients[r.ID] = stored
+	return nil
+}
+
+func (p *RecipientPostgres) GetRecipientByID(ctx context.Context, id string) (*domain.Recipient, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	r, ok := p.get(ctx, id)
+	if !ok {
+		return nil, domain.ErrRecipientNotFound
+	}
+	return cloneRecipient(r), nil
+}
+
+func (p *RecipientPostgres) ListRecipients(ctx context.Context, limit, offset int) ([]*domain.Recipient, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	all := make([]*domain.Recipient, 0, len(p.db.recipients))
+	for _, r := range p.db.recipients {
+		if domain.Visible(ctx, r.TenantID) {
+			all = append(all, r)
+		}
+	}
+	slices.SortFunc(all, func(a, b *domain.Recipient) int {
+		return -cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	all = all[min(max(offset, 0), len(all)):]
+	all = all[:min(max(limit, 0), len(all))]
+
+	var results []*domain.Recipient
+	for _, r := range all {
+		results = append(results, cloneRecipient(r))
+	}
+	return results, nil
+}
+
+// UpdateRecipient заменяет контакты и предпочтения, ID, арендатор и время создания не меняются
+func (p *RecipientPostgres) UpdateRecipient(ctx context.Context, r *domain.Recipient) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	stored, ok := p.get(ctx, r.ID)
+	if !ok {
+		return domain.ErrRecipientNotFound
+	}
+
+	updated := cloneRecipient(r)
+	updated.TenantID = stored.TenantID
+	updated.CreatedAt = stored.CreatedAt
+	updated.UpdatedAt = p.db.now()
+	p.db.recipients[r.ID] = updated
+
+	r.TenantID = updated.TenantID
+	r.CreatedAt = updated.CreatedAt
+	r.UpdatedAt = updated.UpdatedAt
+	return nil
+}
+
+// DeleteRecipient удаляет получателя и, как ON DELETE SET NULL, отвязывает от него notify
+func (p *RecipientPostgres) DeleteRecipient(ctx context.Context, id string) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	if _, ok := p.get(ctx, id); !ok {
+		return domain.ErrRecipientNotFound
+	}
+
+	for _, rec := range p.db.notifies {
+		if rec.notify.RecipientID == id {
+			rec.notify.RecipientID = ""
+		}
+	}
+	delete(p.db.recipients, id)
+	return nil
+}
+
+func (p *RecipientPostgres) get(ctx context.Context, id string) (*domain.Recipient, bool) {
+	r, ok := p.db.recipients[id]
+	if !ok || !domain.Visible(ctx, r.TenantID) {
+		return nil, false
+	}
+	return r, true
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// Redis - кеш notify с TTL. Просроченные записи удаляются при чтении
+type Redis struct {
+	mu         sync.Mutex
+	items      map[string]cacheItem
+	expiration time.Duration
+	now        func() time.Time
+}
+
+type cacheItem struct {
+	notify    *domain.Notify
+	expiresAt time.Time // нулевое - без TTL
+}
+
+func NewRedis(ttl time.Duration) domain.NotifyRedis {
+	return &Redis{
+		items:      make(map[string]cacheItem),
+		expiration: ttl,
+		now:        time.Now,
+	}
+}
+
+func (r *Redis) SetWithExpiration(ctx context.Context, n *domain.Notify) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item := cacheItem{notify: cloneNotify(n)}
+	if r.expiration > 0 {
+		item.expiresAt = r.now().Add(r.expiration)
+	}
+	r.items[n.ID] = item
+	return nil
+}
+
+func (r *Redis) Get(ctx context.Context, id string) (*domain.Notify, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.get(id)
+	if !ok {
+		return &domain.Notify{}, domain.ErrNotFound
+	}
+	return cloneNotify(item.notify), nil
+}
+
+// UpdateStatus обновляет статус закешированного notify, сохраняя TTL, и поднимает версию, как БД.
+// Если notify нет в кеше, ничего не делает
+func (r *Redis) UpdateStatus(
+	ctx context.Context,
+	id string,
+	status domain.Status,
+	scheduledAt *time.Time,
+	retryCount int,
+	lastErr *string,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.get(id)
+	if !ok {
+		return nil
+	}
+
+	n := item.notify
+	n.Status = status
+	if scheduledAt != nil {
+		n.ScheduledAt = *scheduledAt
+	}
+	n.RetryCount = retryCount
+	n.LastError = nil
+	if lastErr != nil {
+		e := *lastErr
+		n.LastError = &e
+	}
+	n.UpdatedAt = r.now().UTC()
+	n.Version++
+	return nil
+}
+
+func (r *Redis) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.items, id)
+	return nil
+}
+
+func (r *Redis) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (r *Redis) Close() error {
+	return nil
+}
+
+func (r *Redis) get(id string) (cacheItem, bool) {
+	item, ok := r.items[id]
+	if !ok {
+		return cacheItem{}, false
+	}
+	if !item.expiresAt.IsZero() && !r.now().Before(item.expiresAt) {
+		delete(r.items, id)
+		return cacheItem{}, false
+	}
+	return item, true
+}
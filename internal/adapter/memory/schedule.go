@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+type SchedulePostgres struct {
+	db *DB
+}
+
+func NewSchedulePostgres(db *DB) domain.SchedulePostgres {
+	return &SchedulePostgres{db: db}
+}
+
+func (p *SchedulePostgres) CreateSchedule(ctx context.Context, s *domain.Schedule) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	if _, ok := p.db.schedules[s.ID]; ok {
+		return fmt.Errorf("failed to create schedule: duplicate id %s", s.ID)
+	}
+
+	s.TenantID = domain.TenantFor(ctx, s.TenantID)
+
+	stored := cloneSchedule(s)
+	stored.UpdatedAt = stored.CreatedAt
+	p.db.schedules[s.ID] = stored
+	return nil
+}
+
+func (p *SchedulePostgres) GetScheduleByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	s, ok := p.get(ctx, id)
+	if !ok {
+		return nil, domain.ErrScheduleNotFound
+	}
+	return cloneSchedule(s), nil
+}
+
+func (p *SchedulePostgres) ListSchedules(ctx context.Context, limit, offset int) ([]*domain.Schedule, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	all := make([]*domain.Schedule, 0, len(p.db.schedules))
+	for _, s := range p.db.schedules {
+		if domain.Visible(ctx, s.TenantID) {
+			all = append(all, s)
+		}
+	}
+	slices.SortFunc(all, func(a, b *domain.Schedule) int {
+		return -cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	all = all[min(max(offset, 0), len(all)):]
+	all = all[:min(max(limit, 0), len(all))]
+
+	var results []*domain.Schedule
+	for _, s := range all {
+		results = append(results, cloneSchedule(s))
+	}
+	return results, nil
+}
+
+func (p *SchedulePostgres) UpdateScheduleState(
+	ctx context.Context,
+	id string,
+	status domain.ScheduleStatus,
+	nextRunAt *time.Time,
+) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	s, ok := p.get(ctx, id)
+	if !ok {
+		return domain.ErrScheduleNotFound
+	}
+	p.setState(s, status, nextRunAt)
+	return nil
+}
+
+// DeleteSchedule удаляет серию, отменяет ее еще не отправленные notify и возвращает их ID
+func (p *SchedulePostgres) DeleteSchedule(ctx context.Context, id string) ([]string, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	if _, ok := p.get(ctx, id); !ok {
+		return nil, domain.ErrScheduleNotFound
+	}
+
+	now := p.db.now()
+	var canceled []string
+	for _, rec := range p.db.notifies {
+		n := rec.notify
+		if n.ScheduleID == id && (n.Status == domain.StatusPending || n.Status == domain.StatusInProcess) {
+			n.Status = domain.StatusCanceled
+			n.UpdatedAt = now
+			n.Version++
+			canceled = append(canceled, n.ID)
+		}
+	}
+	delete(p.db.schedules, id)
+	return canceled, nil
+}
+
+// MaterializeDue создает notify на next_run_at наступивших серий и сдвигает
+// next_run_at на следующее срабатывание после текущего момента
+func (p *SchedulePostgres) MaterializeDue(ctx context.Context, limit int) (int, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	now := p.db.now()
+
+	var due []*domain.Schedule
+	for _, s := range p.db.schedules {
+		if s.Status == domain.ScheduleActive && s.NextRunAt != nil && !s.NextRunAt.After(now) && domain.Visible(ctx, s.TenantID) {
+			due = append(due, s)
+		}
+	}
+	slices.SortFunc(due, func(a, b *domain.Schedule) int {
+		return cmp.Or(a.NextRunAt.Compare(*b.NextRunAt), cmp.Compare(a.ID, b.ID))
+	})
+	due = due[:min(max(limit, 0), len(due))]
+
+	// как и в транзакции Postgres: сначала вычисляем все сдвиги, потом применяем
+	type advance struct {
+		status    domain.ScheduleStatus
+		nextRunAt *time.Time
+	}
+	advances := make([]advance, len(due))
+	for i, s := range due {
+		next, ok, err := s.Next(now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to compute next run for schedule %s: %w", s.ID, err)
+		}
+		advances[i] = advance{status: domain.ScheduleActive, nextRunAt: &next}
+		if !ok {
+			advances[i] = advance{status: domain.ScheduleFinished}
+		}
+	}
+
+	for i, s := range due {
+		n := domain.NewNotify()
+		n.Payload = append([]byte(nil), s.Payload...)
+		n.Target = s.Target
+		n.Channel = s.Channel
+		n.Status = domain.StatusPending
+		n.ScheduledAt = *s.NextRunAt
+		n.ScheduleID = s.ID
+		n.TenantID = s.TenantID
+		n.UpdatedAt = n.CreatedAt
+		p.db.notifies[n.ID] = &notifyRecord{notify: n}
+
+		p.setState(s, advances[i].status, advances[i].nextRunAt)
+	}
+
+	return len(due), nil
+}
+
+// get возвращает серию, если она видна арендатору из контекста
+func (p *SchedulePostgres) get(ctx context.Context, id string) (*domain.Schedule, bool) {
+	s, ok := p.db.schedules[id]
+	if !ok || !domain.Visible(ctx, s.TenantID) {
+		return nil, false
+	}
+	return s, true
+}
+
+func (p *SchedulePostgres) setState(s *domain.Schedule, status domain.ScheduleStatus, nextRunAt *time.Time) {
+	s.Status = status
+	s.NextRunAt = nil
+	if nextRunAt != nil {
+		next := *nextRunAt
+		s.NextRunAt = &next
+	}
+	s.UpdatedAt = p.db.now()
+}
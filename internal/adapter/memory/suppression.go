@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+type SuppressionPostgres struct {
+	db *DB
+}
+
+func NewSuppressionPostgres(db *DB) domain.SuppressionPostgres {
+	return &SuppressionPostgres{db: db}
+}
+
+func (p *SuppressionPostgres) CreateSuppression(ctx context.Context, s *domain.Suppression) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	if _, ok := p.db.suppressions[s.ID]; ok {
+		return fmt.Errorf("failed to create suppression: duplicate id %s", s.ID)
+	}
+	// уникальность как у индекса ux_suppression_scope
+	for _, stored := range p.db.suppressions {
+		if stored.Channel == s.Channel && stored.Target == s.Target &&
+			stored.TenantID == s.TenantID && stored.Category == s.Category {
+			return domain.ErrSuppressionExists
+		}
+	}
+
+	stored := *s
+	p.db.suppressions[s.ID] = &stored
+	return nil
+}
+
+// ListSuppressions возвращает записи арендатора и записи для всех арендаторов
+func (p *SuppressionPostgres) ListSuppressions(ctx context.Context, f domain.SuppressionFilter) ([]*domain.Suppression, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	var all []*domain.Suppression
+	for _, s := range p.db.suppressions {
+		if s.TenantID != "" && !domain.Visible(ctx, s.TenantID) {
+			continue
+		}
+		if (f.Channel != "" && s.Channel != f.Channel) || (f.Target != "" && s.Target != f.Target) {
+			continue
+		}
+		all = append(all, s)
+	}
+	slices.SortFunc(all, func(a, b *domain.Suppression) int {
+		return -cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	all = all[min(max(f.Offset, 0), len(all)):]
+	all = all[:min(max(f.Limit, 0), len(all))]
+
+	var results []*domain.Suppression
+	for _, s := range all {
+		c := *s
+		results = append(results, &c)
+	}
+	return results, nil
+}
+
+// DeleteSuppression удаляет запись. Записи для всех арендаторов удаляет только контекст
+// с domain.ManagesAllTenants
+func (p *SuppressionPostgres) DeleteSuppression(ctx context.Context, id string) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	s, ok := p.db.suppressions[id]
+	if !ok {
+		return domain.ErrSuppressionNotFound
+	}
+	if s.TenantID == "" && !domain.ManagesAllTenants(ctx) || s.TenantID != "" && !domain.Visible(ctx, s.TenantID) {
+		return domain.ErrSuppressionNotFound
+	}
+
+	delete(p.db.suppressions, id)
+	return nil
+}
+
+func (p *SuppressionPostgres) FindSuppression(ctx context.Context, n *domain.Notify) (*domain.Suppression, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	var found *domain.Suppression
+	for _, s := range p.db.suppressions {
+		if s.Matches(n) && (found == nil || s.CreatedAt.Before(found.CreatedAt)) {
+			found = s
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrSuppressionNotFound
+	}
+	c := *found
+	return &c, nil
+}
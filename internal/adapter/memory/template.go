@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+type TemplatePostgres struct {
+	db *DB
+}
+
+func NewTemplatePostgres(db *DB) domain.TemplatePostgres {
+	return &TemplatePostgres{db: db}
+}
+
+// CreateTemplateVersion сохраняет новую версию шаблона, номер версии проставляется в t.Version
+func (p *TemplatePostgres) CreateTemplateVersion(ctx context.Context, t *domain.Template) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	t.TenantID = domain.TenantFor(ctx, t.TenantID)
+	version := 0
+	for _, stored := range p.db.templates {
+		if stored.TenantID == t.TenantID && stored.Name == t.Name && stored.Channel == t.Channel {
+			version = max(version, stored.Version)
+		}
+	}
+
+	t.Version = version + 1
+	p.db.templates = append(p.db.templates, cloneTemplate(t))
+	return nil
+}
+
+func (p *TemplatePostgres) GetTemplate(ctx context.Context, name, channel string, version int) (*domain.Template, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	var found *domain.Template
+	for _, t := range p.db.templates {
+		if t.Name != name || t.Channel != channel || !domain.Visible(ctx, t.TenantID) {
+			continue
+		}
+		if version != 0 && t.Version != version {
+			continue
+		}
+		if found == nil || t.Version > found.Version {
+			found = t
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrTemplateNotFound
+	}
+	return cloneTemplate(found), nil
+}
+
+// ListTemplates возвращает последние версии всех шаблонов
+func (p *TemplatePostgres) ListTemplates(ctx context.Context) ([]*domain.Template, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	type key struct{ tenant, name, channel string }
+	latest := make(map[key]*domain.Template)
+	for _, t := range p.db.templates {
+		if !domain.Visible(ctx, t.TenantID) {
+			continue
+		}
+		k := key{t.TenantID, t.Name, t.Channel}
+		if cur, ok := latest[k]; !ok || t.Version > cur.Version {
+			latest[k] = t
+		}
+	}
+
+	var results []*domain.Template
+	for _, t := range latest {
+		results = append(results, cloneTemplate(t))
+	}
+	slices.SortFunc(results, func(a, b *domain.Template) int {
+		return cmp.Or(cmp.Compare(a.TenantID, b.TenantID), cmp.Compare(a.Name, b.Name), cmp.Compare(a.Channel, b.Channel))
+	})
+	return results, nil
+}
+
+func (p *TemplatePostgres) ListTemplateVersions(ctx context.Context, name, channel string) ([]*domain.Template, error) {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	var results []*domain.Template
+	for _, t := range p.db.templates {
+		if t.Name == name && t.Channel == channel && domain.Visible(ctx, t.TenantID) {
+			results = append(results, cloneTemplate(t))
+		}
+	}
+	if len(results) == 0 {
+		return nil, domain.ErrTemplateNotFound
+	}
+	slices.SortFunc(results, func(a, b *domain.Template) int {
+		return cmp.Compare(b.Version, a.Version)
+	})
+	return results, nil
+}
+
+// DeleteTemplate удаляет все версии шаблона
+func (p *TemplatePostgres) DeleteTemplate(ctx context.Context, name, channel string) error {
+	p.db.mu.Lock()
+	defer p.db.mu.Unlock()
+
+	kept := p.db.templates[:0]
+	for _, t := range p.db.templates {
+		if t.Name != name || t.Channel != channel || !domain.Visible(ctx, t.TenantID) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == len(p.db.templates) {
+		return domain.ErrTemplateNotFound
+	}
+	clear(p.db.templates[len(kept):])
+	p.db.templates = kept
+	return nil
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// Nop - метрики, которые никуда не пишутся
+type Nop struct{}
+
+func NewNop() domain.Metrics {
+	return Nop{}
+}
+
+func (Nop) NotifyCreated(string)                        {}
+func (Nop) NotifySent(string)                           {}
+func (Nop) NotifyFailed(string)                         {}
+func (Nop) NotifyThrottled(string)                      {}
+func (Nop) NotifySuppressed(string)                     {}
+func (Nop) ObservePublish(string, time.Duration, error) {}
+func (Nop) ObserveSend(string, time.Duration, error)    {}
+func (Nop) ObserveBatch(int)                            {}
+func (Nop) ObserveSchedulingLag(string, time.Duration)  {}
+func (Nop) SetBacklog(int)                              {}
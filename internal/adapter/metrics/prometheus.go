@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "notifier"
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+type Prometheus struct {
+	registry *prometheus.Registry
+
+	created    *prometheus.CounterVec
+	sent       *prometheus.CounterVec
+	failed     *prometheus.CounterVec
+	throttled  *prometheus.CounterVec
+	suppressed *prometheus.CounterVec
+
+	publishLatency *prometheus.HistogramVec
+	sendLatency    *prometheus.HistogramVec
+	schedulingLag  *prometheus.HistogramVec
+	batchSize      prometheus.Histogram
+	backlog        prometheus.Gauge
+}
+
+// NewPrometheus создает метрики в собственном реестре, чтобы не зависеть
+// от глобального prometheus.DefaultRegisterer
+func NewPrometheus() *Prometheus {
+	m := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		created: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifies_created_total",
+			Help:      "Number of created notifies.",
+		}, []string{"channel"}),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifies_sent_total",
+			Help:      "Number of successfully sent notifies.",
+		}, []string{"channel"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifies_failed_total",
+			Help:      "Number of notifies moved to Failed status.",
+		}, []string{"channel"}),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifies_throttled_total",
+			Help:      "Number of sends deferred by rate limits.",
+		}, []string{"channel"}),
+		suppressed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifies_suppressed_total",
+			Help:      "Number of sends skipped because the target is suppressed.",
+		}, []string{"channel"}),
+		publishLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "publish_duration_seconds",
+			Help:      "Latency of publishing notifies to the queue.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"channel", "result"}),
+		sendLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "send_duration_seconds",
+			Help:      "Latency of sending notifies to providers.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"channel", "result"}),
+		schedulingLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "scheduling_lag_seconds",
+			Help:      "Delay between scheduled_at and the actual send time.",
+			Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600},
+		}, []string{"channel"}),
+		batchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "fetch_batch_size",
+			Help:      "Number of notifies fetched by the scheduler per tick.",
+			Buckets:   prometheus.LinearBuckets(0, 10, 11),
+		}),
+		backlog: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pending_backlog",
+			Help:      "Number of notifies waiting in Pending status.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.created,
+		m.sent,
+		m.failed,
+		m.throttled,
+		m.suppressed,
+		m.publishLatency,
+		m.sendLatency,
+		m.schedulingLag,
+		m.batchSize,
+		m.backlog,
+	)
+
+	return m
+}
+
+// Handler отдает метрики в формате Prometheus для /metrics
+func (m *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Prometheus) NotifyCreated(channel string) {
+	m.created.WithLabelValues(channel).Inc()
+}
+
+func (m *Prometheus) NotifySent(channel string) {
+	m.sent.WithLabelValues(channel).Inc()
+}
+
+func (m *Prometheus) NotifyFailed(channel string) {
+	m.failed.WithLabelValues(channel).Inc()
+}
+
+func (m *Prometheus) NotifyThrottled(channel string) {
+	m.throttled.WithLabelValues(channel).Inc()
+}
+
+func (m *Prometheus) NotifySuppressed(channel string) {
+	m.suppressed.WithLabelValues(channel).Inc()
+}
+
+func (m *Prometheus) ObservePublish(channel string, latency time.Duration, err error) {
+	m.publishLatency.WithLabelValues(channel, result(err)).Observe(latency.Seconds())
+}
+
+func (m *Prometheus) ObserveSend(channel string, latency time.Duration, err error) {
+	m.sendLatency.WithLabelValues(channel, result(err)).Observe(latency.Seconds())
+}
+
+func (m *Prometheus) ObserveBatch(size int) {
+	m.batchSize.Observe(float64(size))
+}
+
+func (m *Prometheus) ObserveSchedulingLag(channel string, lag time.Duration) {
+	if lag < 0 {
+		lag = 0
+	}
+	m.schedulingLag.WithLabelValues(channel).Observe(lag.Seconds())
+}
+
+func (m *Prometheus) SetBacklog(count int) {
+	m.backlog.Set(float64(count))
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultError
+	}
+	return resultSuccess
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheus_Handler(t *testing.T) {
+	m := NewPrometheus()
+
+	m.NotifyCreated("email")
+	m.NotifySent("email")
+	m.NotifyFailed("telegram")
+	m.NotifySuppressed("email")
+	m.ObservePublish("email", 10*time.Millisecond, nil)
+	m.ObserveSend("telegram", time.Second, errors.New("timeout"))
+	m.ObserveBatch(7)
+	m.ObserveSchedulingLag("email", 2*time.Second)
+	m.SetBacklog(42)
+
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	expected := []string{
+		`notifier_notifies_created_total{channel="email"} 1`,
+		`notifier_notifies_sent_total{channel="email"} 1`,
+		`notifier_notifies_failed_total{channel="telegram"} 1`,
+		`notifier_notifies_suppressed_total{channel="email"} 1`,
+		`notifier_publish_duration_seconds_count{channel="email",result="success"} 1`,
+		`notifier_send_duration_seconds_count{channel="telegram",result="error"} 1`,
+		`notifier_fetch_batch_size_sum 7`,
+		`notifier_scheduling_lag_seconds_sum{channel="email"} 2`,
+		`notifier_pending_backlog 42`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("expected %q in metrics output", line)
+		}
+	}
+}
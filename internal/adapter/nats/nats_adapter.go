@@ -0,0 +1,213 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	natscfg "github.com/adexcell/delayed-notifier/pkg/nats"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	defaultStream     = "NOTIFICATIONS"
+	defaultSubject    = "notifications.ready"
+	defaultDurable    = "notifier-worker"
+	defaultAckWait    = 30 * time.Second
+	defaultMaxDeliver = 3
+	defaultWorkers    = 5
+
+	// scheduledAtHeader - время отправки, чтобы консьюмер не разбирал тело ради задержки
+	scheduledAtHeader = "Notify-Scheduled-At"
+	initTimeout       = 10 * time.Second
+)
+
+type NotifyQueueAdapter struct {
+	cfg  natscfg.Config
+	conn *nats.Conn
+	js   jetstream.JetStream
+	log  log.Log
+}
+
+func NewNatsQueueAdapter(cfg natscfg.Config, log log.Log) (domain.QueueProvider, error) {
+	if cfg.Stream == "" {
+		cfg.Stream = defaultStream
+	}
+	if cfg.Subject == "" {
+		cfg.Subject = defaultSubject
+	}
+	if cfg.Durable == "" {
+		cfg.Durable = defaultDurable
+	}
+	if cfg.AckWait <= 0 {
+		cfg.AckWait = defaultAckWait
+	}
+	if cfg.MaxDeliver <= 0 {
+		cfg.MaxDeliver = defaultMaxDeliver
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+
+	conn, err := natscfg.Connect(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect nats: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to init jetstream: %w", err)
+	}
+
+	return &NotifyQueueAdapter{
+		cfg:  cfg,
+		conn: conn,
+		js:   js,
+		log:  log,
+	}, nil
+}
+
+// Init объявляет work-queue стрим и durable консьюмер: сообщение удаляется
+// из стрима после подтверждения
+func (q *NotifyQueueAdapter) Init() error {
+	ctx, cancel := context.WithTimeout(context.Background(), initTimeout)
+	defer cancel()
+
+	_, err := q.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      q.cfg.Stream,
+		Subjects:  []string{q.cfg.Subject},
+		Retention: jetstream.WorkQueuePolicy,
+		Storage:   jetstream.FileStorage,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to declare stream: %w", err)
+	}
+
+	_, err = q.js.CreateOrUpdateConsumer(ctx, q.cfg.Stream, jetstream.ConsumerConfig{
+		Durable:       q.cfg.Durable,
+		FilterSubject: q.cfg.Subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       q.cfg.AckWait,
+		MaxDeliver:    q.cfg.MaxDeliver,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to declare consumer: %w", err)
+	}
+	return nil
+}
+
+func (q *NotifyQueueAdapter) Publish(ctx context.Context, n *domain.Notify) error {
+	body, err := json.Marshal(toNatsDTO(n))
+	if err != nil {
+		return fmt.Errorf("marshal notify: %w", err)
+	}
+
+	msg := nats.NewMsg(q.cfg.Subject)
+	msg.Data = body
+	msg.Header.Set(scheduledAtHeader, n.ScheduledAt.UTC().Format(time.RFC3339Nano))
+
+	_, err = q.js.PublishMsg(ctx, msg, jetstream.WithMsgID(publishID(n)))
+	return err
+}
+
+// Consume раздает сообщения воркерам. Сообщение с будущим scheduled_at
+// возвращается в стрим с задержкой. При ошибке обработчика сообщение
+// доставляется повторно, пока не исчерпан max_deliver; после этого notify
+// останется InProcess и будет повторно выбран планировщиком после visibility timeout
+func (q *NotifyQueueAdapter) Consume(ctx context.Context, handler domain.MessageHandler) error {
+	consumer, err := q.js.Consumer(ctx, q.cfg.Stream, q.cfg.Durable)
+	if err != nil {
+		return fmt.Errorf("failed to get consumer: %w", err)
+	}
+
+	msgs := make(chan jetstream.Msg)
+	cc, err := consumer.Consume(func(msg jetstream.Msg) {
+		select {
+		case msgs <- msg:
+		case <-ctx.Done():
+		}
+	}, jetstream.PullMaxMessages(q.cfg.Workers*2))
+	if err != nil {
+		return fmt.Errorf("failed to start consuming: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for range q.cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg := <-msgs:
+					q.handle(ctx, msg, handler)
+				}
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	cc.Stop()
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+func (q *NotifyQueueAdapter) handle(ctx context.Context, msg jetstream.Msg, handler domain.MessageHandler) {
+	if delay := time.Until(scheduledAt(msg)); delay > 0 {
+		if err := msg.NakWithDelay(delay); err != nil {
+			q.log.Error().Err(err).Msg("nats: failed to delay message")
+		}
+		return
+	}
+
+	if err := handler(ctx, msg.Data()); err != nil {
+		q.log.Error().Err(err).Msg("nats: handler failed")
+		if err := msg.Nak(); err != nil {
+			q.log.Error().Err(err).Msg("nats: failed to nak message")
+		}
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		q.log.Error().Err(err).Msg("nats: failed to ack message")
+	}
+}
+
+func (q *NotifyQueueAdapter) Ping(ctx context.Context) error {
+	if !q.conn.IsConnected() {
+		return errors.New("nats connection is not established")
+	}
+
+	if _, err := q.js.Stream(ctx, q.cfg.Stream); err != nil {
+		return fmt.Errorf("failed to get stream: %w", err)
+	}
+	return nil
+}
+
+func (q *NotifyQueueAdapter) Close() error {
+	q.conn.Close()
+	return nil
+}
+
+// publishID - ключ дедупликации JetStream. Повторная публикация того же notify
+// после ретрая идет с новым updated_at и не отбрасывается как дубликат
+func publishID(n *domain.Notify) string {
+	return fmt.Sprintf("%s:%d", n.ID, n.UpdatedAt.UnixNano())
+}
+
+func scheduledAt(msg jetstream.Msg) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, msg.Headers().Get(scheduledAtHeader))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
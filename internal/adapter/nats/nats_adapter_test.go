@@ -0,0 +1,47 @@
+package nats
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/queuetest"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	natscfg "github.com/adexcell/delayed-notifier/pkg/nats"
+)
+
+// Тесты с брокером запускаются, только если задан NATS_URL
+func TestNatsQueue_Conformance(t *testing.T) {
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		t.Skip("NATS_URL is not set")
+	}
+
+	queuetest.Run(t, func(t *testing.T) domain.QueueProvider {
+		q, err := NewNatsQueueAdapter(natscfg.Config{
+			URL:     url,
+			Stream:  "NOTIFICATIONS_TEST",
+			Subject: "notifications.test",
+			Durable: "queuetest",
+		}, log.New())
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		if err := q.Init(); err != nil {
+			t.Fatalf("init: %v", err)
+		}
+		t.Cleanup(func() { q.Close() })
+		return q
+	})
+}
+
+func TestPublishID_ChangesWithUpdatedAt(t *testing.T) {
+	now := time.Now()
+	first := publishID(&domain.Notify{ID: "id-1", UpdatedAt: now})
+	retry := publishID(&domain.Notify{ID: "id-1", UpdatedAt: now.Add(time.Minute)})
+
+	if first == retry {
+		t.Errorf("expected different dedup ids for republished notify, got %s", first)
+	}
+}
@@ -0,0 +1,167 @@
+// Package pgqueue - провайдер очереди без брокера. Источником истины остается Postgres:
+// планировщик забирает готовые notify через LockAndFetchReady и передает их воркерам
+// этого же процесса через канал. Если процесс упадет, захваченные notify вернутся
+// в выборку после visibility timeout.
+package pgqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+const (
+	defaultBuffer  = 100
+	defaultWorkers = 5
+)
+
+var ErrQueueClosed = errors.New("queue is closed")
+
+type Config struct {
+	Buffer  int `mapstructure:"buffer"`
+	Workers int `mapstructure:"workers"`
+}
+
+type Queue struct {
+	messages chan []byte
+	workers  int
+	log      log.Log
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+	timers sync.WaitGroup
+}
+
+func New(cfg Config, log log.Log) domain.QueueProvider {
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = defaultBuffer
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+
+	return &Queue{
+		messages: make(chan []byte, cfg.Buffer),
+		workers:  cfg.Workers,
+		log:      log,
+		done:     make(chan struct{}),
+	}
+}
+
+func (q *Queue) Init() error {
+	return nil
+}
+
+// Publish передает notify воркерам. Notify с будущим scheduled_at придерживается
+// в памяти до наступления времени
+func (q *Queue) Publish(ctx context.Context, n *domain.Notify) error {
+	body, err := json.Marshal(toQueueDTO(n))
+	if err != nil {
+		return fmt.Errorf("marshal notify: %w", err)
+	}
+
+	// Блокировка нужна только для проверки closed и timers.Add: отправка в полный буфер
+	// под ней не дала бы Close взять блокировку и закрыть done
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return ErrQueueClosed
+	}
+
+	if delay := time.Until(n.ScheduledAt); delay > 0 {
+		q.timers.Add(1)
+		go q.publishAfter(body, delay)
+		q.mu.RUnlock()
+		return nil
+	}
+	q.mu.RUnlock()
+
+	return q.enqueue(ctx, body)
+}
+
+func (q *Queue) publishAfter(body []byte, delay time.Duration) {
+	defer q.timers.Done()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		if err := q.enqueue(context.Background(), body); err != nil {
+			q.log.Error().Err(err).Msg("pgqueue: failed to enqueue delayed notify")
+		}
+	case <-q.done:
+	}
+}
+
+func (q *Queue) enqueue(ctx context.Context, body []byte) error {
+	select {
+	case q.messages <- body:
+		return nil
+	case <-q.done:
+		return ErrQueueClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Consume запускает воркеры и блокируется до отмены контекста или закрытия очереди.
+// Ошибка обработчика не возвращает сообщение в очередь: notify останется InProcess
+// и будет повторно выбран планировщиком после visibility timeout
+func (q *Queue) Consume(ctx context.Context, handler domain.MessageHandler) error {
+	var wg sync.WaitGroup
+	for range q.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-q.done:
+					return
+				case body := <-q.messages:
+					if err := handler(ctx, body); err != nil {
+						q.log.Error().Err(err).Msg("pgqueue: handler failed")
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return ErrQueueClosed
+}
+
+func (q *Queue) Ping(ctx context.Context) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	return nil
+}
+
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	close(q.done)
+	q.mu.Unlock()
+
+	q.timers.Wait()
+	return nil
+}
@@ -0,0 +1,96 @@
+package pgqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/queuetest"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+func TestQueue_Conformance(t *testing.T) {
+	queuetest.Run(t, func(t *testing.T) domain.QueueProvider {
+		q := New(Config{}, log.New())
+		t.Cleanup(func() { q.Close() })
+		return q
+	})
+}
+
+func TestQueue_PublishAfterClose(t *testing.T) {
+	q := New(Config{}, log.New())
+	if err := q.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// Act
+	err := q.Publish(context.Background(), &domain.Notify{ID: "id-1", ScheduledAt: time.Now()})
+
+	// Assert
+	if !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("expected ErrQueueClosed, got %v", err)
+	}
+	if err := q.Ping(context.Background()); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("expected ping to fail after close, got %v", err)
+	}
+}
+
+func TestQueue_PublishBlocksOnFullBuffer(t *testing.T) {
+	q := New(Config{Buffer: 1}, log.New())
+	t.Cleanup(func() { q.Close() })
+
+	ctx := context.Background()
+	if err := q.Publish(ctx, &domain.Notify{ID: "id-1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// Act: буфер занят, воркеров нет
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err := q.Publish(ctx, &domain.Notify{ID: "id-2"})
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestQueue_CloseUnblocksPublishOnFullBuffer(t *testing.T) {
+	q := New(Config{Buffer: 1}, log.New())
+
+	ctx := context.Background()
+	if err := q.Publish(ctx, &domain.Notify{ID: "id-1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// Буфер занят, воркеров нет: публикация висит до закрытия очереди
+	published := make(chan error, 1)
+	go func() {
+		published <- q.Publish(ctx, &domain.Notify{ID: "id-2"})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// Act
+	closed := make(chan error, 1)
+	go func() { closed <- q.Close() }()
+
+	// Assert
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("close: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("close is blocked by pending publish")
+	}
+	select {
+	case err := <-published:
+		if !errors.Is(err, ErrQueueClosed) {
+			t.Errorf("expected ErrQueueClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("publish was not released by close")
+	}
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// RecordAttempt пишут только планировщик и воркер из системного контекста,
+// поэтому арендатор здесь не проверяется
+func (p *Postgres) RecordAttempt(ctx context.Context, a *domain.Attempt) error {
+	dto := toAttemptDTO(a)
+
+	query := `
+		INSERT INTO notify_attempts (
+			attempt_id, notify_id, kind, worker_id, channel, outcome,
+			error, provider_message_id, provider_response, latency_ms, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);`
+
+	_, err := p.db.ExecContext(ctx, query,
+		dto.ID, dto.NotifyID, dto.Kind, dto.WorkerID, dto.Channel, dto.Outcome,
+		dto.Error, dto.ProviderMessageID, dto.ProviderResponse, dto.LatencyMs, dto.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record attempt: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) ListAttempts(ctx context.Context, notifyID string) ([]*domain.Attempt, error) {
+	query := `
+		SELECT attempt_id, notify_id, kind, worker_id, channel, outcome,
+			error, provider_message_id, provider_response, latency_ms, created_at
+		FROM notify_attempts a
+		WHERE notify_id = $1 AND EXISTS (
+			SELECT 1 FROM notify n
+			WHERE n.notify_id = a.notify_id AND ($2::text IS NULL OR n.tenant_id = $2)
+		)
+		ORDER BY created_at ASC, attempt_id ASC;`
+
+	rows, err := p.db.QueryContext(ctx, query, notifyID, tenantArg(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to get attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.Attempt
+	for rows.Next() {
+		var dto attemptPostgresDTO
+		if err := rows.Scan(
+			&dto.ID,
+			&dto.NotifyID,
+			&dto.Kind,
+			&dto.WorkerID,
+			&dto.Channel,
+			&dto.Outcome,
+			&dto.Error,
+			&dto.ProviderMessageID,
+			&dto.ProviderResponse,
+			&dto.LatencyMs,
+			&dto.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, attemptToDomain(&dto))
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+const batchInsertColumns = `notify_id, payload, target, channel, status, scheduled_at, created_at,
+			idempotency_key, request_hash, schedule_id, retry_policy, tenant_id, delivery_window,
+			recipient_id, fallbacks, category`
+
+// batchInsertColumnCount - число параметров на одну строку batchInsertColumns
+const batchInsertColumnCount = 16
+
+// buildBatchInsertQuery собирает многострочный INSERT. Строки, нарушающие любое
+// уникальное ограничение (notify_id, ключ идемпотентности арендатора), пропускаются, RETURNING
+// отдает только вставленные notify_id.
+func buildBatchInsertQuery(notifies []*domain.Notify) (string, []any) {
+	args := make([]any, 0, len(notifies)*batchInsertColumnCount)
+	rows := make([]string, 0, len(notifies))
+	for _, n := range notifies {
+		dto := toPostgresDTO(n)
+		placeholders := make([]string, batchInsertColumnCount)
+		for i := range placeholders {
+			placeholders[i] = fmt.Sprintf("$%d", len(args)+i+1)
+		}
+		rows = append(rows, "("+strings.Join(placeholders, ", ")+")")
+		args = append(args,
+			dto.ID, dto.Payload, dto.Target, dto.Channel, dto.Status, dto.ScheduledAt, dto.CreatedAt,
+			dto.IdempotencyKey, dto.RequestHash, dto.ScheduleID, nullJSON(dto.RetryPolicy), dto.TenantID, nullJSON(dto.DeliveryWindow),
+			dto.RecipientID, nullJSON(dto.Fallbacks), dto.Category)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`
+		INSERT INTO notify (
+			` + batchInsertColumns + `
+		)
+		VALUES `)
+	sb.WriteString(strings.Join(rows, ",\n\t\t\t"))
+	sb.WriteString(`
+		ON CONFLICT DO NOTHING
+		RETURNING notify_id;`)
+	return sb.String(), args
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+func TestBuildBatchInsertQuery(t *testing.T) {
+	first := domain.NewNotify()
+	first.ScheduledAt = time.Now()
+	second := domain.NewNotify()
+	second.IdempotencyKey = "key-2"
+	second.TenantID = "team-a"
+
+	// Act
+	query, args := buildBatchInsertQuery([]*domain.Notify{first, second})
+
+	// Assert
+	for _, part := range []string{"($1, $2, $3", "$16)", "($17, $18", "$32)", "ON CONFLICT DO NOTHING", "RETURNING notify_id"} {
+		if !strings.Contains(query, part) {
+			t.Errorf("expected %q in query %s", part, query)
+		}
+	}
+	if strings.Contains(query, "$33") {
+		t.Errorf("unexpected extra placeholder in %s", query)
+	}
+	if len(args) != 2*batchInsertColumnCount {
+		t.Fatalf("expected %d args, got %d", 2*batchInsertColumnCount, len(args))
+	}
+	if args[0] != first.ID || args[batchInsertColumnCount] != second.ID {
+		t.Errorf("expected ids at row starts, got %v and %v", args[0], args[batchInsertColumnCount])
+	}
+	if key, ok := args[batchInsertColumnCount+7].(*string); !ok || key == nil || *key != "key-2" {
+		t.Errorf("expected idempotency key of second row, got %v", args[batchInsertColumnCount+7])
+	}
+	if key, ok := args[7].(*string); !ok || key != nil {
+		t.Errorf("expected NULL idempotency key for first row, got %v", args[7])
+	}
+	if args[2*batchInsertColumnCount-5] != "team-a" {
+		t.Errorf("expected tenant of second row, got %v", args[2*batchInsertColumnCount-5])
+	}
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/internal/domain"
@@ -8,6 +9,7 @@ import (
 
 type notifyPostgresDTO struct {
 	ID          string        `db:"notify_id"`
+	TenantID    string        `db:"tenant_id"`
 	Payload     []byte        `db:"payload"`
 	Target      string        `db:"target"`
 	Channel     string        `db:"channel"`
@@ -17,11 +19,22 @@ type notifyPostgresDTO struct {
 	UpdatedAt   time.Time     `db:"updated_at"`
 	RetryCount  int           `db:"retry_count"`
 	LastError   *string       `db:"last_error"`
+	Version     int           `db:"version"`
+
+	IdempotencyKey *string `db:"idempotency_key"`
+	RequestHash    *string `db:"request_hash"`
+	ScheduleID     *string `db:"schedule_id"`
+	RetryPolicy    []byte  `db:"retry_policy"`
+	DeliveryWindow []byte  `db:"delivery_window"`
+	RecipientID    *string `db:"recipient_id"`
+	Fallbacks      []byte  `db:"fallbacks"`
+	Category       string  `db:"category"`
 }
 
 func toPostgresDTO(n *domain.Notify) *notifyPostgresDTO {
 	return &notifyPostgresDTO{
 		ID:          n.ID,
+		TenantID:    n.TenantID,
 		Payload:     n.Payload,
 		Target:      n.Target,
 		Channel:     n.Channel,
@@ -31,12 +44,22 @@ func toPostgresDTO(n *domain.Notify) *notifyPostgresDTO {
 		UpdatedAt:   n.UpdatedAt,
 		RetryCount:  n.RetryCount,
 		LastError:   n.LastError,
+
+		IdempotencyKey: nullString(n.IdempotencyKey),
+		RequestHash:    nullString(n.RequestHash),
+		ScheduleID:     nullString(n.ScheduleID),
+		RetryPolicy:    encodeRetryPolicy(n.RetryPolicy),
+		DeliveryWindow: encodeDeliveryWindow(n.DeliveryWindow),
+		RecipientID:    nullString(n.RecipientID),
+		Fallbacks:      encodeRoutes(n.Fallbacks),
+		Category:       n.Category,
 	}
 }
 
 func toDomain(dto *notifyPostgresDTO) *domain.Notify {
 	return &domain.Notify{
 		ID:          dto.ID,
+		TenantID:    dto.TenantID,
 		Payload:     dto.Payload,
 		Target:      dto.Target,
 		Channel:     dto.Channel,
@@ -46,5 +69,347 @@ func toDomain(dto *notifyPostgresDTO) *domain.Notify {
 		UpdatedAt:   dto.UpdatedAt,
 		RetryCount:  dto.RetryCount,
 		LastError:   dto.LastError,
+		Version:     dto.Version,
+
+		IdempotencyKey: fromNullString(dto.IdempotencyKey),
+		RequestHash:    fromNullString(dto.RequestHash),
+		ScheduleID:     fromNullString(dto.ScheduleID),
+		RetryPolicy:    decodeRetryPolicy(dto.RetryPolicy),
+		DeliveryWindow: decodeDeliveryWindow(dto.DeliveryWindow),
+		RecipientID:    fromNullString(dto.RecipientID),
+		Fallbacks:      decodeRoutes(dto.Fallbacks),
+		Category:       dto.Category,
+	}
+}
+
+type schedulePostgresDTO struct {
+	ID              string                `db:"schedule_id"`
+	Payload         []byte                `db:"payload"`
+	Target          string                `db:"target"`
+	Channel         string                `db:"channel"`
+	CronExpr        *string               `db:"cron_expr"`
+	IntervalSeconds *int64                `db:"interval_seconds"`
+	Timezone        string                `db:"timezone"`
+	StartAt         time.Time             `db:"start_at"`
+	EndAt           *time.Time            `db:"end_at"`
+	NextRunAt       *time.Time            `db:"next_run_at"`
+	Status          domain.ScheduleStatus `db:"status"`
+	CreatedAt       time.Time             `db:"created_at"`
+	UpdatedAt       time.Time             `db:"updated_at"`
+	TenantID        string                `db:"tenant_id"`
+}
+
+func toScheduleDTO(s *domain.Schedule) *schedulePostgresDTO {
+	dto := &schedulePostgresDTO{
+		ID:        s.ID,
+		Payload:   s.Payload,
+		Target:    s.Target,
+		Channel:   s.Channel,
+		CronExpr:  nullString(s.CronExpr),
+		Timezone:  s.Timezone,
+		StartAt:   s.StartAt,
+		EndAt:     s.EndAt,
+		NextRunAt: s.NextRunAt,
+		Status:    s.Status,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+		TenantID:  s.TenantID,
+	}
+	if dto.Timezone == "" {
+		dto.Timezone = "UTC"
+	}
+	if s.Interval > 0 {
+		seconds := int64(s.Interval / time.Second)
+		dto.IntervalSeconds = &seconds
+	}
+	return dto
+}
+
+func scheduleToDomain(dto *schedulePostgresDTO) *domain.Schedule {
+	s := &domain.Schedule{
+		ID:        dto.ID,
+		Payload:   dto.Payload,
+		Target:    dto.Target,
+		Channel:   dto.Channel,
+		CronExpr:  fromNullString(dto.CronExpr),
+		Timezone:  dto.Timezone,
+		StartAt:   dto.StartAt,
+		EndAt:     dto.EndAt,
+		NextRunAt: dto.NextRunAt,
+		Status:    dto.Status,
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+		TenantID:  dto.TenantID,
+	}
+	if dto.IntervalSeconds != nil {
+		s.Interval = time.Duration(*dto.IntervalSeconds) * time.Second
+	}
+	return s
+}
+
+type templatePostgresDTO struct {
+	ID        string    `db:"template_id"`
+	TenantID  string    `db:"tenant_id"`
+	Name      string    `db:"name"`
+	Channel   string    `db:"channel"`
+	Version   int       `db:"version"`
+	Subject   string    `db:"subject"`
+	Text      string    `db:"body_text"`
+	HTML      string    `db:"body_html"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toTemplateDTO(t *domain.Template) *templatePostgresDTO {
+	return &templatePostgresDTO{
+		ID:        t.ID,
+		TenantID:  t.TenantID,
+		Name:      t.Name,
+		Channel:   t.Channel,
+		Version:   t.Version,
+		Subject:   t.Subject,
+		Text:      t.Text,
+		HTML:      t.HTML,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func templateToDomain(dto *templatePostgresDTO) *domain.Template {
+	return &domain.Template{
+		ID:        dto.ID,
+		TenantID:  dto.TenantID,
+		Name:      dto.Name,
+		Channel:   dto.Channel,
+		Version:   dto.Version,
+		Subject:   dto.Subject,
+		Text:      dto.Text,
+		HTML:      dto.HTML,
+		CreatedAt: dto.CreatedAt,
+	}
+}
+
+type recipientPostgresDTO struct {
+	ID             string    `db:"recipient_id"`
+	TenantID       string    `db:"tenant_id"`
+	Name           string    `db:"name"`
+	Contacts       []byte    `db:"contacts"`
+	Channels       []byte    `db:"channels"`
+	OptOuts        []byte    `db:"opt_outs"`
+	DeliveryWindow []byte    `db:"delivery_window"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func toRecipientDTO(r *domain.Recipient) *recipientPostgresDTO {
+	contacts, _ := json.Marshal(r.Contacts)
+	return &recipientPostgresDTO{
+		ID:             r.ID,
+		TenantID:       r.TenantID,
+		Name:           r.Name,
+		Contacts:       contacts,
+		Channels:       encodeStrings(r.Channels),
+		OptOuts:        encodeStrings(r.OptOuts),
+		DeliveryWindow: encodeDeliveryWindow(r.DeliveryWindow),
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
+
+// recipientToDomain - колонки пишет только toRecipientDTO, поэтому ошибки разбора не ожидаются
+func recipientToDomain(dto *recipientPostgresDTO) *domain.Recipient {
+	r := &domain.Recipient{
+		ID:             dto.ID,
+		TenantID:       dto.TenantID,
+		Name:           dto.Name,
+		DeliveryWindow: decodeDeliveryWindow(dto.DeliveryWindow),
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+	}
+	_ = json.Unmarshal(dto.Contacts, &r.Contacts)
+	_ = json.Unmarshal(dto.Channels, &r.Channels)
+	_ = json.Unmarshal(dto.OptOuts, &r.OptOuts)
+	return r
+}
+
+// encodeStrings - пустой список хранится как [], а не JSON null
+func encodeStrings(values []string) []byte {
+	if len(values) == 0 {
+		return []byte("[]")
+	}
+	raw, _ := json.Marshal(values)
+	return raw
+}
+
+type attemptPostgresDTO struct {
+	ID                string    `db:"attempt_id"`
+	NotifyID          string    `db:"notify_id"`
+	Kind              string    `db:"kind"`
+	WorkerID          string    `db:"worker_id"`
+	Channel           string    `db:"channel"`
+	Outcome           string    `db:"outcome"`
+	Error             *string   `db:"error"`
+	ProviderMessageID *string   `db:"provider_message_id"`
+	ProviderResponse  *string   `db:"provider_response"`
+	LatencyMs         int64     `db:"latency_ms"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+func toAttemptDTO(a *domain.Attempt) *attemptPostgresDTO {
+	return &attemptPostgresDTO{
+		ID:                a.ID,
+		NotifyID:          a.NotifyID,
+		Kind:              string(a.Kind),
+		WorkerID:          a.WorkerID,
+		Channel:           a.Channel,
+		Outcome:           string(a.Outcome),
+		Error:             nullString(a.Error),
+		ProviderMessageID: nullString(a.ProviderMessageID),
+		ProviderResponse:  nullString(a.ProviderResponse),
+		LatencyMs:         a.Latency.Milliseconds(),
+		CreatedAt:         a.CreatedAt,
+	}
+}
+
+func attemptToDomain(dto *attemptPostgresDTO) *domain.Attempt {
+	return &domain.Attempt{
+		ID:                dto.ID,
+		NotifyID:          dto.NotifyID,
+		Kind:              domain.AttemptKind(dto.Kind),
+		WorkerID:          dto.WorkerID,
+		Channel:           dto.Channel,
+		Outcome:           domain.AttemptOutcome(dto.Outcome),
+		Error:             fromNullString(dto.Error),
+		ProviderMessageID: fromNullString(dto.ProviderMessageID),
+		ProviderResponse:  fromNullString(dto.ProviderResponse),
+		Latency:           time.Duration(dto.LatencyMs) * time.Millisecond,
+		CreatedAt:         dto.CreatedAt,
+	}
+}
+
+func encodeRetryPolicy(p *domain.RetryPolicy) []byte {
+	if p == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(p)
+	return raw
+}
+
+// decodeRetryPolicy - битая политика в БД не должна блокировать доставку,
+// в этом случае notify получает политику своего канала
+func decodeRetryPolicy(raw []byte) *domain.RetryPolicy {
+	if len(raw) == 0 {
+		return nil
+	}
+	var p domain.RetryPolicy
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil
+	}
+	return &p
+}
+
+func encodeDeliveryWindow(w *domain.DeliveryWindow) []byte {
+	if w == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(w)
+	return raw
+}
+
+// decodeDeliveryWindow - как и с политикой повторов, битое окно не блокирует доставку
+func decodeDeliveryWindow(raw []byte) *domain.DeliveryWindow {
+	if len(raw) == 0 {
+		return nil
+	}
+	var w domain.DeliveryWindow
+	if err := json.Unmarshal(raw, &w); err != nil {
+		return nil
+	}
+	return &w
+}
+
+func encodeRoutes(routes []domain.Route) []byte {
+	if len(routes) == 0 {
+		return nil
+	}
+	raw, _ := json.Marshal(routes)
+	return raw
+}
+
+// decodeRoutes - битый список запасных маршрутов означает, что переключаться некуда
+func decodeRoutes(raw []byte) []domain.Route {
+	if len(raw) == 0 {
+		return nil
+	}
+	var routes []domain.Route
+	if err := json.Unmarshal(raw, &routes); err != nil {
+		return nil
+	}
+	return routes
+}
+
+func decodeErrorHistory(raw []byte) ([]domain.ErrorRecord, error) {
+	var history []domain.ErrorRecord
+	if len(raw) == 0 {
+		return history, nil
+	}
+	if err := json.Unmarshal(raw, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// nullJSON - параметр nullable JSONB колонки: nil []byte драйвер передал бы
+// пустой строкой, которую Postgres не примет как JSON
+func nullJSON(raw []byte) any {
+	if raw == nil {
+		return nil
+	}
+	return raw
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func fromNullString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+type suppressionPostgresDTO struct {
+	ID        string    `db:"suppression_id"`
+	TenantID  *string   `db:"tenant_id"`
+	Channel   string    `db:"channel"`
+	Target    string    `db:"target"`
+	Category  string    `db:"category"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toSuppressionDTO(s *domain.Suppression) *suppressionPostgresDTO {
+	return &suppressionPostgresDTO{
+		ID:        s.ID,
+		TenantID:  nullString(s.TenantID),
+		Channel:   s.Channel,
+		Target:    s.Target,
+		Category:  s.Category,
+		Reason:    string(s.Reason),
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func suppressionToDomain(dto *suppressionPostgresDTO) *domain.Suppression {
+	return &domain.Suppression{
+		ID:        dto.ID,
+		TenantID:  fromNullString(dto.TenantID),
+		Channel:   dto.Channel,
+		Target:    dto.Target,
+		Category:  dto.Category,
+		Reason:    domain.SuppressionReason(dto.Reason),
+		CreatedAt: dto.CreatedAt,
 	}
 }
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/lib/pq"
+)
+
+const listColumns = `
+			notify_id, payload, target, channel, status,
+			scheduled_at, created_at, COALESCE(updated_at, created_at), retry_count, last_error, version,
+			delivery_window, recipient_id, fallbacks, category`
+
+const deadLetterColumns = listColumns + `, error_history`
+
+// buildListQuery собирает запрос списка notify по фильтру.
+// Сортировка всегда дополняется notify_id, чтобы порядок был строгим
+// и keyset-пагинация по паре (sort_column, notify_id) не теряла строки.
+// tenant - значение tenantArg: арендатор или nil для системного контекста.
+func buildListQuery(f domain.NotifyFilter, tenant any) (string, []any) {
+	return buildSelectQuery(listColumns, f, tenant)
+}
+
+// buildDeadLetterQuery - список failed notify с историей ошибок
+func buildDeadLetterQuery(f domain.NotifyFilter, tenant any) (string, []any) {
+	f.Statuses = []domain.Status{domain.StatusFailed}
+	return buildSelectQuery(deadLetterColumns, f, tenant)
+}
+
+// buildRequeueQuery возвращает в очередь все failed notify, подходящие под фильтр.
+// Сортировка, лимит и курсор фильтра не учитываются.
+func buildRequeueQuery(f domain.NotifyFilter, tenant any) (string, []any) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	f.Statuses = []domain.Status{domain.StatusFailed}
+	conds := filterConditions(f, tenant, arg)
+
+	var sb strings.Builder
+	sb.WriteString("\n\t\tUPDATE notify")
+	sb.WriteString("\n\t\tSET status = " + arg(domain.StatusPending) + ", retry_count = 0, scheduled_at = NOW(), updated_at = NOW(), version = version + 1")
+	sb.WriteString("\n\t\tWHERE ")
+	sb.WriteString(strings.Join(conds, "\n\t\t\tAND "))
+	sb.WriteString("\n\t\tRETURNING notify_id;")
+
+	return sb.String(), args
+}
+
+func buildSelectQuery(columns string, f domain.NotifyFilter, tenant any) (string, []any) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := filterConditions(f, tenant, arg)
+
+	sortColumn := string(domain.SortByCreatedAt)
+	if f.SortBy == domain.SortByScheduledAt {
+		sortColumn = string(domain.SortByScheduledAt)
+	}
+	direction, cmp := "ASC", ">"
+	if f.Desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	if f.Cursor != nil {
+		conds = append(conds, fmt.Sprintf("(%s, notify_id) %s (%s, %s)",
+			sortColumn, cmp, arg(f.Cursor.Value), arg(f.Cursor.ID)))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n\t\tSELECT ")
+	sb.WriteString(columns)
+	sb.WriteString("\n\t\tFROM notify")
+	if len(conds) > 0 {
+		sb.WriteString("\n\t\tWHERE ")
+		sb.WriteString(strings.Join(conds, "\n\t\t\tAND "))
+	}
+	fmt.Fprintf(&sb, "\n\t\tORDER BY %s %s, notify_id %s", sortColumn, direction, direction)
+	sb.WriteString("\n\t\tLIMIT " + arg(f.Limit))
+	if f.Cursor == nil && f.Offset > 0 {
+		sb.WriteString("\n\t\tOFFSET " + arg(f.Offset))
+	}
+	sb.WriteString(";")
+
+	return sb.String(), args
+}
+
+// filterConditions переводит поля фильтра в условия WHERE, значения добавляются через arg
+func filterConditions(f domain.NotifyFilter, tenant any, arg func(any) string) []string {
+	var conds []string
+
+	if tenant != nil {
+		conds = append(conds, "tenant_id = "+arg(tenant))
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]int64, 0, len(f.Statuses))
+		for _, s := range f.Statuses {
+			statuses = append(statuses, int64(s))
+		}
+		conds = append(conds, "status = ANY("+arg(pq.Array(statuses))+")")
+	}
+	if f.Channel != "" {
+		conds = append(conds, "channel = "+arg(f.Channel))
+	}
+	if f.Target != "" {
+		conds = append(conds, "target = "+arg(f.Target))
+	}
+	if f.ScheduledFrom != nil {
+		conds = append(conds, "scheduled_at >= "+arg(*f.ScheduledFrom))
+	}
+	if f.ScheduledTo != nil {
+		conds = append(conds, "scheduled_at < "+arg(*f.ScheduledTo))
+	}
+	if f.CreatedFrom != nil {
+		conds = append(conds, "created_at >= "+arg(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		conds = append(conds, "created_at < "+arg(*f.CreatedTo))
+	}
+
+	return conds
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+func TestBuildListQuery_Defaults(t *testing.T) {
+	query, args := buildListQuery(domain.NotifyFilter{Limit: 10, Offset: 20, Desc: true}, nil)
+
+	if strings.Contains(query, "WHERE") {
+		t.Errorf("expected no WHERE clause, got %s", query)
+	}
+	if !strings.Contains(query, "ORDER BY created_at DESC, notify_id DESC") {
+		t.Errorf("expected created_at DESC ordering, got %s", query)
+	}
+	if !strings.Contains(query, "OFFSET $2") {
+		t.Errorf("expected OFFSET placeholder, got %s", query)
+	}
+	if len(args) != 2 || args[0] != 10 || args[1] != 20 {
+		t.Errorf("unexpected args %v", args)
+	}
+}
+
+func TestBuildListQuery_FiltersAndCursor(t *testing.T) {
+	now := time.Now().UTC()
+	f := domain.NotifyFilter{
+		Statuses:      []domain.Status{domain.StatusPending},
+		Channel:       "email",
+		ScheduledFrom: &now,
+		SortBy:        domain.SortByScheduledAt,
+		Limit:         5,
+		Offset:        100,
+		Cursor:        &domain.Cursor{SortBy: domain.SortByScheduledAt, Value: now, ID: "id-1"},
+	}
+
+	query, args := buildListQuery(f, nil)
+
+	for _, part := range []string{
+		"status = ANY($1)",
+		"channel = $2",
+		"scheduled_at >= $3",
+		"(scheduled_at, notify_id) > ($4, $5)",
+		"ORDER BY scheduled_at ASC, notify_id ASC",
+		"LIMIT $6",
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("expected query to contain %q, got %s", part, query)
+		}
+	}
+	// при курсоре offset не используется
+	if strings.Contains(query, "OFFSET") {
+		t.Errorf("expected no OFFSET with cursor, got %s", query)
+	}
+	if len(args) != 6 {
+		t.Errorf("expected 6 args, got %d", len(args))
+	}
+}
+
+func TestBuildDeadLetterQuery_ForcesFailedStatus(t *testing.T) {
+	f := domain.NotifyFilter{
+		Statuses: []domain.Status{domain.StatusPending, domain.StatusSent},
+		Channel:  "email",
+		Limit:    10,
+		Desc:     true,
+	}
+
+	query, args := buildDeadLetterQuery(f, nil)
+
+	if !strings.Contains(query, "error_history") {
+		t.Errorf("expected error_history column, got %s", query)
+	}
+	statuses, ok := args[0].(driver.Valuer)
+	if !ok {
+		t.Fatalf("expected status array as first arg, got %T", args[0])
+	}
+	if v, _ := statuses.Value(); v != "{3}" {
+		t.Errorf("expected only failed status, got %v", v)
+	}
+}
+
+func TestBuildRequeueQuery(t *testing.T) {
+	from := time.Now().Add(-time.Hour).UTC()
+	f := domain.NotifyFilter{
+		Channel:     "telegram",
+		CreatedFrom: &from,
+		Limit:       50,
+		Cursor:      &domain.Cursor{ID: "ignored"},
+	}
+
+	query, args := buildRequeueQuery(f, nil)
+
+	for _, part := range []string{
+		"UPDATE notify",
+		"status = ANY($1)",
+		"channel = $2",
+		"created_at >= $3",
+		"SET status = $4, retry_count = 0",
+		"RETURNING notify_id",
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("expected query to contain %q, got %s", part, query)
+		}
+	}
+	// лимит и курсор для массового повтора не применяются
+	if strings.Contains(query, "LIMIT") || strings.Contains(query, "ignored") {
+		t.Errorf("expected no LIMIT or cursor, got %s", query)
+	}
+	if len(args) != 4 || args[3] != domain.StatusPending {
+		t.Errorf("unexpected args %v", args)
+	}
+}
+
+func TestBuildListQuery_Tenant(t *testing.T) {
+	query, args := buildListQuery(domain.NotifyFilter{Channel: "email", Limit: 10}, "team-a")
+
+	if !strings.Contains(query, "tenant_id = $1") || !strings.Contains(query, "channel = $2") {
+		t.Errorf("expected tenant condition first, got %s", query)
+	}
+	if len(args) != 3 || args[0] != "team-a" {
+		t.Errorf("unexpected args %v", args)
+	}
+
+	query, _ = buildRequeueQuery(domain.NotifyFilter{}, "team-a")
+	if !strings.Contains(query, "WHERE tenant_id = $1") {
+		t.Errorf("expected requeue to be scoped by tenant, got %s", query)
+	}
+}
@@ -20,26 +20,89 @@ func New(cfg postgres.Config) (domain.NotifyPostgres, error) {
 	return &Postgres{db: db}, err
 }
 
+// NewWithDB создает адаптер поверх уже открытого пула соединений,
+// чтобы несколько репозиториев делили один пул.
+func NewWithDB(db *postgres.DB) domain.NotifyPostgres {
+	return &Postgres{db: db}
+}
+
 func (p *Postgres) Create(ctx context.Context, n *domain.Notify) error {
+	n.TenantID = domain.TenantFor(ctx, n.TenantID)
 	dto := toPostgresDTO(n)
 
 	query := `
-		INSERT INTO notify (notify_id, payload, target, channel, status, scheduled_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+		INSERT INTO notify (
+			notify_id, payload, target, channel, status, scheduled_at, created_at,
+			idempotency_key, request_hash, schedule_id, retry_policy, tenant_id, delivery_window,
+			recipient_id, fallbacks, category
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);`
 
 	_, err := p.db.ExecContext(ctx, query,
-		dto.ID, dto.Payload, dto.Target, dto.Channel, dto.Status, dto.ScheduledAt, dto.CreatedAt)
+		dto.ID, dto.Payload, dto.Target, dto.Channel, dto.Status, dto.ScheduledAt, dto.CreatedAt,
+		dto.IdempotencyKey, dto.RequestHash, dto.ScheduleID, nullJSON(dto.RetryPolicy), dto.TenantID, nullJSON(dto.DeliveryWindow),
+		dto.RecipientID, nullJSON(dto.Fallbacks), dto.Category)
+	if postgres.IsUniqueViolation(err) {
+		return domain.ErrNotifyAlreadyExists
+	}
 	return err
 }
 
+func (p *Postgres) CreateBatch(ctx context.Context, notifies []*domain.Notify, atomic bool) ([]string, error) {
+	if len(notifies) == 0 {
+		return nil, nil
+	}
+
+	for _, n := range notifies {
+		n.TenantID = domain.TenantFor(ctx, n.TenantID)
+	}
+
+	var inserted []string
+	err := p.db.WithTx(ctx, func(tx *sql.Tx) error {
+		query, args := buildBatchInsertQuery(notifies)
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert batch: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return fmt.Errorf("failed to scan inserted id: %w", err)
+			}
+			inserted = append(inserted, id)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read inserted ids: %w", err)
+		}
+
+		if atomic && len(inserted) < len(notifies) {
+			return domain.ErrBatchConflict
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrBatchConflict) {
+		return inserted, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
 func (p *Postgres) GetNotifyByID(ctx context.Context, id string) (*domain.Notify, error) {
 	query := `
-		SELECT notify_id, payload, target, channel, status, scheduled_at
-		FROM notify WHERE notify_id=$1;`
+		SELECT notify_id, payload, target, channel, status, scheduled_at,
+			created_at, retry_count, last_error, retry_policy, version, tenant_id, delivery_window,
+			recipient_id, fallbacks, category
+		FROM notify WHERE notify_id=$1 AND ($2::text IS NULL OR tenant_id = $2);`
 	var dto notifyPostgresDTO
 
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
+	err := p.db.QueryRowContext(ctx, query, id, tenantArg(ctx)).Scan(
 		&dto.ID, &dto.Payload, &dto.Target, &dto.Channel, &dto.Status, &dto.ScheduledAt,
+		&dto.CreatedAt, &dto.RetryCount, &dto.LastError, &dto.RetryPolicy, &dto.Version, &dto.TenantID,
+		&dto.DeliveryWindow, &dto.RecipientID, &dto.Fallbacks, &dto.Category,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, domain.ErrNotFound
@@ -47,6 +110,27 @@ func (p *Postgres) GetNotifyByID(ctx context.Context, id string) (*domain.Notify
 	return toDomain(&dto), err
 }
 
+func (p *Postgres) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Notify, error) {
+	query := `
+		SELECT notify_id, payload, target, channel, status, scheduled_at,
+			created_at, idempotency_key, request_hash, tenant_id
+		FROM notify WHERE idempotency_key=$1 AND tenant_id=$2;`
+	var dto notifyPostgresDTO
+
+	// ключи уникальны в пределах арендатора, поэтому ищем всегда в одном
+	err := p.db.QueryRowContext(ctx, query, key, domain.TenantFor(ctx, "")).Scan(
+		&dto.ID, &dto.Payload, &dto.Target, &dto.Channel, &dto.Status, &dto.ScheduledAt,
+		&dto.CreatedAt, &dto.IdempotencyKey, &dto.RequestHash, &dto.TenantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notify by idempotency key: %w", err)
+	}
+	return toDomain(&dto), nil
+}
+
 func (p *Postgres) UpdateStatus(
 	ctx context.Context,
 	id string,
@@ -56,33 +140,181 @@ func (p *Postgres) UpdateStatus(
 	lastErr *string,
 
 ) error {
+	// условие на статус не дает опоздавшему воркеру затереть отмену notify, отмененного в InProcess
 	query := `
 		UPDATE notify
 		SET status       = $2, 
 			scheduled_at = COALESCE($3, scheduled_at),
 			retry_count  = $4,
 			last_error   = $5, 
-			updated_at   = NOW()
-		WHERE notify_id  = $1;`
+			updated_at   = NOW(),
+			version      = version + 1,
+			error_history = CASE WHEN $5::text IS NULL THEN error_history
+				ELSE error_history || jsonb_build_array(
+					jsonb_build_object('at', NOW(), 'attempt', $4::int, 'error', $5::text))
+			END
+		WHERE notify_id  = $1 AND status <> $7 AND ($6::text IS NULL OR tenant_id = $6);`
 
-	res, err := p.db.ExecContext(ctx, query, id, status, scheduledAt, retryCount, lastErr)
+	res, err := p.db.ExecContext(ctx, query,
+		id, status, scheduledAt, retryCount, lastErr, tenantArg(ctx), domain.StatusCanceled)
 	if err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
 	rows, _ := res.RowsAffected()
 	if rows == 0 {
-		return domain.ErrNotFound
+		// строка либо отсутствует, либо уже отменена
+		if _, err := p.GetNotifyByID(ctx, id); err != nil {
+			return err
+		}
+		return domain.ErrNotifyCanceled
+	}
+	return nil
+}
+
+// - перенос notify без попытки доставки: StatusInProcess -> StatusPending на новое время
+func (p *Postgres) Reschedule(ctx context.Context, id string, scheduledAt time.Time) error {
+	query := `
+		UPDATE notify
+		SET status = $2, scheduled_at = $3, updated_at = NOW(), version = version + 1
+		WHERE notify_id = $1 AND status <> $4 AND ($5::text IS NULL OR tenant_id = $5);`
+
+	res, err := p.db.ExecContext(ctx, query,
+		id, domain.StatusPending, scheduledAt, domain.StatusCanceled, tenantArg(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to reschedule notify: %w", err)
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		if _, err := p.GetNotifyByID(ctx, id); err != nil {
+			return err
+		}
+		return domain.ErrNotifyCanceled
 	}
 	return nil
 }
 
+// - редактирование notify: только в StatusPending и при совпадении версии (version 0 - без проверки).
+// Условие на статус не дает изменить notify, который LockAndFetchReady уже забрал в отправку.
+func (p *Postgres) Update(ctx context.Context, id string, patch domain.NotifyPatch, version int) (*domain.Notify, error) {
+	query := `
+		UPDATE notify
+		SET payload      = COALESCE($3, payload),
+			target       = COALESCE($4, target),
+			channel      = COALESCE($5, channel),
+			scheduled_at = COALESCE($6, scheduled_at),
+			updated_at   = NOW(),
+			version      = version + 1
+		WHERE notify_id = $1 AND status = $2 AND ($7::int = 0 OR version = $7::int)
+			AND ($8::text IS NULL OR tenant_id = $8)
+		RETURNING ` + listColumns + `, retry_policy;`
+
+	// nil []byte драйвер передал бы пустой строкой, а не NULL
+	var payload any
+	if patch.Payload != nil {
+		payload = patch.Payload
+	}
+
+	var dto notifyPostgresDTO
+	err := p.db.QueryRowContext(ctx, query,
+		id, domain.StatusPending, payload, patch.Target, patch.Channel, patch.ScheduledAt, version, tenantArg(ctx),
+	).Scan(
+		&dto.ID,
+		&dto.Payload,
+		&dto.Target,
+		&dto.Channel,
+		&dto.Status,
+		&dto.ScheduledAt,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+		&dto.RetryCount,
+		&dto.LastError,
+		&dto.Version,
+		&dto.DeliveryWindow,
+		&dto.RecipientID,
+		&dto.Fallbacks,
+		&dto.Category,
+		&dto.RetryPolicy,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		current, err := p.GetNotifyByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if current.Status != domain.StatusPending {
+			return nil, domain.ErrNotifyNotEditable
+		}
+		return nil, domain.ErrVersionMismatch
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update notify: %w", err)
+	}
+
+	return toDomain(&dto), nil
+}
+
+// - отмена notify: StatusPending/StatusInProcess -> StatusCanceled.
+// Повторная отмена уже отмененного notify не считается ошибкой.
+func (p *Postgres) Cancel(ctx context.Context, id string) (*domain.Notify, error) {
+	query := `
+		UPDATE notify
+		SET status = $2, updated_at = NOW(), version = version + 1
+		WHERE notify_id = $1 AND status IN ($2, $3, $4) AND ($5::text IS NULL OR tenant_id = $5)
+		RETURNING   notify_id,
+					payload,
+					target,
+					channel,
+					status,
+					scheduled_at,
+					created_at,
+					updated_at,
+					retry_count,
+					last_error,
+					version;`
+
+	var dto notifyPostgresDTO
+	err := p.db.QueryRowContext(
+		ctx,
+		query,
+		id,
+		domain.StatusCanceled,
+		domain.StatusPending,
+		domain.StatusInProcess,
+		tenantArg(ctx),
+	).Scan(
+		&dto.ID,
+		&dto.Payload,
+		&dto.Target,
+		&dto.Channel,
+		&dto.Status,
+		&dto.ScheduledAt,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+		&dto.RetryCount,
+		&dto.LastError,
+		&dto.Version,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		// строка либо отсутствует, либо уже в финальном статусе Sent/Failed
+		if _, err := p.GetNotifyByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrNotifyNotCancelable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel notify: %w", err)
+	}
+
+	return toDomain(&dto), nil
+}
+
 func (p *Postgres) DeleteByID(ctx context.Context, id string) error {
 	query := `
 	DELETE FROM notify
-	WHERE notify_id = $1`
+	WHERE notify_id = $1 AND ($2::text IS NULL OR tenant_id = $2)`
 
-	_, err := p.db.ExecContext(ctx, query, id)
+	_, err := p.db.ExecContext(ctx, query, id, tenantArg(ctx))
 
 	return err
 }
@@ -92,14 +324,15 @@ func (p *Postgres) LockAndFetchReady(ctx context.Context, limit int, visibilityT
 	query := `
 		WITH selected AS (
 			SELECT notify_id FROM notify
-			WHERE (status = $1 AND scheduled_at <= NOW())
-   				OR (status = $2 AND COALESCE(updated_at, created_at) <= NOW() - make_interval(secs => $5))
+			WHERE ((status = $1 AND scheduled_at <= NOW())
+   				OR (status = $2 AND COALESCE(updated_at, created_at) <= NOW() - make_interval(secs => $5)))
+				AND ($6::text IS NULL OR tenant_id = $6)
 			ORDER BY scheduled_at ASC
 			LIMIT $3
 			FOR UPDATE SKIP LOCKED
 		)
 		UPDATE notify
-		SET status = $4, updated_at = NOW()
+		SET status = $4, updated_at = NOW(), version = notify.version + 1
 		FROM selected
 		WHERE notify.notify_id = selected.notify_id
 		RETURNING   notify.notify_id, 
@@ -111,7 +344,13 @@ func (p *Postgres) LockAndFetchReady(ctx context.Context, limit int, visibilityT
 					notify.created_at, 
 					notify.updated_at,
 					notify.retry_count, 
-					notify.last_error;`
+					notify.last_error,
+					notify.retry_policy,
+					notify.version,
+					notify.delivery_window,
+					notify.recipient_id,
+					notify.fallbacks,
+					notify.category;`
 
 	rows, err := p.db.QueryContext(
 		ctx,
@@ -121,6 +360,7 @@ func (p *Postgres) LockAndFetchReady(ctx context.Context, limit int, visibilityT
 		limit,
 		domain.StatusInProcess,
 		int(visibilityTimeout.Seconds()),
+		tenantArg(ctx),
 	)
 	if err != nil {
 		return nil, err
@@ -141,6 +381,12 @@ func (p *Postgres) LockAndFetchReady(ctx context.Context, limit int, visibilityT
 			&dto.UpdatedAt,
 			&dto.RetryCount,
 			&dto.LastError,
+			&dto.RetryPolicy,
+			&dto.Version,
+			&dto.DeliveryWindow,
+			&dto.RecipientID,
+			&dto.Fallbacks,
+			&dto.Category,
 		); err != nil {
 			return nil, err
 		}
@@ -149,19 +395,22 @@ func (p *Postgres) LockAndFetchReady(ctx context.Context, limit int, visibilityT
 	return results, nil
 }
 
-func (p *Postgres) List(ctx context.Context, limit, offset int) ([]*domain.Notify, error) {
-	query := `
-		SELECT 
-			notify_id, payload, target, channel, status, 
-			scheduled_at, created_at, updated_at, retry_count, last_error
-		FROM notify
-		ORDER BY created_at DESC 
-		LIMIT $1
-		OFFSET $2;`
-
-	rows, err := p.db.QueryContext(ctx, query, limit, offset)
+func (p *Postgres) CountPending(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM notify WHERE status = $1 AND ($2::text IS NULL OR tenant_id = $2);`
+
+	var count int
+	if err := p.db.QueryRowContext(ctx, query, domain.StatusPending, tenantArg(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("postgres: failed to count pending notifies: %w", err)
+	}
+	return count, nil
+}
+
+func (p *Postgres) List(ctx context.Context, filter domain.NotifyFilter) ([]*domain.Notify, error) {
+	query, args := buildListQuery(filter, tenantArg(ctx))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("postgres: failed to get list of notifies")
+		return nil, fmt.Errorf("postgres: failed to get list of notifies: %w", err)
 	}
 	if rows == nil {
 		return nil, nil
@@ -182,12 +431,182 @@ func (p *Postgres) List(ctx context.Context, limit, offset int) ([]*domain.Notif
 			&dto.UpdatedAt,
 			&dto.RetryCount,
 			&dto.LastError,
+			&dto.Version,
+			&dto.DeliveryWindow,
+			&dto.RecipientID,
+			&dto.Fallbacks,
+			&dto.Category,
 		); err != nil {
 			return nil, err
 		}
 		results = append(results, toDomain(&dto))
 	}
-	return results, nil
+	return results, rows.Err()
+}
+
+func (p *Postgres) ListDeadLetters(ctx context.Context, filter domain.NotifyFilter) ([]*domain.DeadLetter, error) {
+	query, args := buildDeadLetterQuery(filter, tenantArg(ctx))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to get list of dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.DeadLetter
+	for rows.Next() {
+		var (
+			dto     notifyPostgresDTO
+			history []byte
+		)
+		if err := rows.Scan(
+			&dto.ID,
+			&dto.Payload,
+			&dto.Target,
+			&dto.Channel,
+			&dto.Status,
+			&dto.ScheduledAt,
+			&dto.CreatedAt,
+			&dto.UpdatedAt,
+			&dto.RetryCount,
+			&dto.LastError,
+			&dto.Version,
+			&dto.DeliveryWindow,
+			&dto.RecipientID,
+			&dto.Fallbacks,
+			&dto.Category,
+			&history,
+		); err != nil {
+			return nil, err
+		}
+
+		errs, err := decodeErrorHistory(history)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode error history of %s: %w", dto.ID, err)
+		}
+		results = append(results, &domain.DeadLetter{Notify: toDomain(&dto), Errors: errs})
+	}
+	return results, rows.Err()
+}
+
+// - ручной повтор: StatusFailed -> StatusPending с обнулением попыток.
+// last_error и история ошибок сохраняются.
+func (p *Postgres) Requeue(ctx context.Context, id string) (*domain.Notify, error) {
+	query := `
+		UPDATE notify
+		SET status = $2, retry_count = 0, scheduled_at = NOW(), updated_at = NOW(), version = version + 1
+		WHERE notify_id = $1 AND status = $3 AND ($4::text IS NULL OR tenant_id = $4)
+		RETURNING ` + listColumns + `;`
+
+	var dto notifyPostgresDTO
+	err := p.db.QueryRowContext(ctx, query, id, domain.StatusPending, domain.StatusFailed, tenantArg(ctx)).Scan(
+		&dto.ID,
+		&dto.Payload,
+		&dto.Target,
+		&dto.Channel,
+		&dto.Status,
+		&dto.ScheduledAt,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+		&dto.RetryCount,
+		&dto.LastError,
+		&dto.Version,
+		&dto.DeliveryWindow,
+		&dto.RecipientID,
+		&dto.Fallbacks,
+		&dto.Category,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := p.GetNotifyByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrNotifyNotRetryable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue notify: %w", err)
+	}
+
+	return toDomain(&dto), nil
+}
+
+// - переключение на запасной маршрут: первый элемент fallbacks становится каналом и адресом.
+// Финальные Sent/Canceled не переключаются, чтобы опоздавший воркер не отправил notify повторно.
+func (p *Postgres) Fallback(ctx context.Context, id string, lastErr *string) (*domain.Notify, error) {
+	query := `
+		UPDATE notify
+		SET channel      = fallbacks->0->>'channel',
+			target       = fallbacks->0->>'target',
+			fallbacks    = fallbacks - 0,
+			status       = $2,
+			retry_count  = 0,
+			scheduled_at = NOW(),
+			last_error   = $3,
+			updated_at   = NOW(),
+			version      = version + 1,
+			error_history = CASE WHEN $3::text IS NULL THEN error_history
+				ELSE error_history || jsonb_build_array(
+					jsonb_build_object('at', NOW(), 'attempt', retry_count, 'error', $3::text))
+			END
+		WHERE notify_id = $1 AND status NOT IN ($4, $5)
+			AND COALESCE(jsonb_array_length(fallbacks), 0) > 0
+			AND ($6::text IS NULL OR tenant_id = $6)
+		RETURNING ` + listColumns + `;`
+
+	var dto notifyPostgresDTO
+	err := p.db.QueryRowContext(ctx, query,
+		id, domain.StatusPending, lastErr, domain.StatusSent, domain.StatusCanceled, tenantArg(ctx),
+	).Scan(
+		&dto.ID,
+		&dto.Payload,
+		&dto.Target,
+		&dto.Channel,
+		&dto.Status,
+		&dto.ScheduledAt,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+		&dto.RetryCount,
+		&dto.LastError,
+		&dto.Version,
+		&dto.DeliveryWindow,
+		&dto.RecipientID,
+		&dto.Fallbacks,
+		&dto.Category,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := p.GetNotifyByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrNoRoute
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to switch notify to fallback: %w", err)
+	}
+
+	return toDomain(&dto), nil
+}
+
+func (p *Postgres) RequeueFailed(ctx context.Context, filter domain.NotifyFilter) ([]string, error) {
+	query, args := buildRequeueQuery(filter, tenantArg(ctx))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue failed notifies: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (p *Postgres) Ping(ctx context.Context) error {
+	return p.db.Master.PingContext(ctx)
 }
 
 func (p *Postgres) Close() error {
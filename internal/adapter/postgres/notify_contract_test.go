@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"os"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/storetest"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/postgres"
+)
+
+// Тест запускается, только если задан POSTGRES_TEST_DSN базы с накатанными миграциями.
+// Таблицы notify, schedule, recipient, suppression и template очищаются перед каждой проверкой
+func TestPostgres_Contract(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	storetest.RunNotifyPostgres(t, func(t *testing.T) domain.NotifyPostgres {
+		db, err := postgres.New(postgres.Config{MasterDSN: dsn, MaxOpenConns: 10})
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		if _, err := db.Master.Exec(`TRUNCATE notify CASCADE;`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		p := NewWithDB(db)
+		t.Cleanup(func() { p.Close() })
+		return p
+	})
+
+	storetest.RunRecipientPostgres(t, func(t *testing.T) domain.RecipientPostgres {
+		db, err := postgres.New(postgres.Config{MasterDSN: dsn, MaxOpenConns: 10})
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		if _, err := db.Master.Exec(`TRUNCATE recipient CASCADE;`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		t.Cleanup(func() { db.Master.Close() })
+		return NewRecipientPostgres(db)
+	})
+
+	storetest.RunSuppressionPostgres(t, func(t *testing.T) domain.SuppressionPostgres {
+		db, err := postgres.New(postgres.Config{MasterDSN: dsn, MaxOpenConns: 10})
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		if _, err := db.Master.Exec(`TRUNCATE suppression;`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		t.Cleanup(func() { db.Master.Close() })
+		return NewSuppressionPostgres(db)
+	})
+
+	storetest.RunTemplatePostgres(t, func(t *testing.T) domain.TemplatePostgres {
+		db, err := postgres.New(postgres.Config{MasterDSN: dsn, MaxOpenConns: 10})
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		if _, err := db.Master.Exec(`TRUNCATE template;`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		t.Cleanup(func() { db.Master.Close() })
+		return NewTemplatePostgres(db)
+	})
+
+	storetest.RunSchedulePostgres(t, func(t *testing.T) (domain.SchedulePostgres, domain.NotifyPostgres) {
+		db, err := postgres.New(postgres.Config{MasterDSN: dsn, MaxOpenConns: 10})
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		if _, err := db.Master.Exec(`TRUNCATE schedule, notify CASCADE;`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		p := NewWithDB(db)
+		t.Cleanup(func() { p.Close() })
+		return NewSchedulePostgres(db), p
+	})
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/postgres"
+)
+
+const recipientColumns = `
+			recipient_id, tenant_id, name, contacts, channels, opt_outs, delivery_window,
+			created_at, COALESCE(updated_at, created_at)`
+
+type RecipientPostgres struct {
+	db *postgres.DB
+}
+
+func NewRecipientPostgres(db *postgres.DB) domain.RecipientPostgres {
+	return &RecipientPostgres{db: db}
+}
+
+func (p *RecipientPostgres) CreateRecipient(ctx context.Context, r *domain.Recipient) error {
+	r.TenantID = domain.TenantFor(ctx, r.TenantID)
+	dto := toRecipientDTO(r)
+
+	query := `
+		INSERT INTO recipient (
+			recipient_id, tenant_id, name, contacts, channels, opt_outs, delivery_window, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
+
+	_, err := p.db.ExecContext(ctx, query,
+		dto.ID, dto.TenantID, dto.Name, dto.Contacts, dto.Channels, dto.OptOuts, nullJSON(dto.DeliveryWindow), dto.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create recipient: %w", err)
+	}
+	return nil
+}
+
+func (p *RecipientPostgres) GetRecipientByID(ctx context.Context, id string) (*domain.Recipient, error) {
+	query := `
+		SELECT ` + recipientColumns + `
+		FROM recipient WHERE recipient_id = $1 AND ($2::text IS NULL OR tenant_id = $2);`
+
+	dto, err := scanRecipient(p.db.QueryRowContext(ctx, query, id, tenantArg(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrRecipientNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipient: %w", err)
+	}
+	return recipientToDomain(dto), nil
+}
+
+func (p *RecipientPostgres) ListRecipients(ctx context.Context, limit, offset int) ([]*domain.Recipient, error) {
+	query := `
+		SELECT ` + recipientColumns + `
+		FROM recipient
+		WHERE ($3::text IS NULL OR tenant_id = $3)
+		ORDER BY created_at DESC, recipient_id DESC
+		LIMIT $1
+		OFFSET $2;`
+
+	rows, err := p.db.QueryContext(ctx, query, limit, offset, tenantArg(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to get list of recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.Recipient
+	for rows.Next() {
+		dto, err := scanRecipient(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, recipientToDomain(dto))
+	}
+	return results, rows.Err()
+}
+
+func (p *RecipientPostgres) UpdateRecipient(ctx context.Context, r *domain.Recipient) error {
+	dto := toRecipientDTO(r)
+
+	query := `
+		UPDATE recipient
+		SET name            = $2,
+			contacts        = $3,
+			channels        = $4,
+			opt_outs        = $5,
+			delivery_window = $6,
+			updated_at      = NOW()
+		WHERE recipient_id = $1 AND ($7::text IS NULL OR tenant_id = $7)
+		RETURNING tenant_id, created_at, updated_at;`
+
+	err := p.db.QueryRowContext(ctx, query,
+		dto.ID, dto.Name, dto.Contacts, dto.Channels, dto.OptOuts, nullJSON(dto.DeliveryWindow), tenantArg(ctx),
+	).Scan(&r.TenantID, &r.CreatedAt, &r.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrRecipientNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update recipient: %w", err)
+	}
+	return nil
+}
+
+// - notify получателя не удаляются: recipient_id обнуляется по ON DELETE SET NULL
+func (p *RecipientPostgres) DeleteRecipient(ctx context.Context, id string) error {
+	query := `DELETE FROM recipient WHERE recipient_id = $1 AND ($2::text IS NULL OR tenant_id = $2);`
+
+	res, err := p.db.ExecContext(ctx, query, id, tenantArg(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete recipient: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrRecipientNotFound
+	}
+	return nil
+}
+
+func scanRecipient(row rowScanner) (*recipientPostgresDTO, error) {
+	var dto recipientPostgresDTO
+	err := row.Scan(
+		&dto.ID,
+		&dto.TenantID,
+		&dto.Name,
+		&dto.Contacts,
+		&dto.Channels,
+		&dto.OptOuts,
+		&dto.DeliveryWindow,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+	)
+	return &dto, err
+}
@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/postgres"
+)
+
+const scheduleColumns = `
+			schedule_id, payload, target, channel, cron_expr, interval_seconds, timezone,
+			start_at, end_at, next_run_at, status, created_at, COALESCE(updated_at, created_at), tenant_id`
+
+type SchedulePostgres struct {
+	db *postgres.DB
+}
+
+func NewSchedulePostgres(db *postgres.DB) domain.SchedulePostgres {
+	return &SchedulePostgres{db: db}
+}
+
+func (p *SchedulePostgres) CreateSchedule(ctx context.Context, s *domain.Schedule) error {
+	s.TenantID = domain.TenantFor(ctx, s.TenantID)
+	dto := toScheduleDTO(s)
+
+	query := `
+		INSERT INTO schedule (
+			schedule_id, payload, target, channel, cron_expr, interval_seconds, timezone,
+			start_at, end_at, next_run_at, status, created_at, tenant_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);`
+
+	_, err := p.db.ExecContext(ctx, query,
+		dto.ID, dto.Payload, dto.Target, dto.Channel, dto.CronExpr, dto.IntervalSeconds, dto.Timezone,
+		dto.StartAt, dto.EndAt, dto.NextRunAt, dto.Status, dto.CreatedAt, dto.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+	return nil
+}
+
+func (p *SchedulePostgres) GetScheduleByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	query := `
+		SELECT ` + scheduleColumns + `
+		FROM schedule WHERE schedule_id = $1 AND ($2::text IS NULL OR tenant_id = $2);`
+
+	dto, err := scanSchedule(p.db.QueryRowContext(ctx, query, id, tenantArg(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	return scheduleToDomain(dto), nil
+}
+
+func (p *SchedulePostgres) ListSchedules(ctx context.Context, limit, offset int) ([]*domain.Schedule, error) {
+	query := `
+		SELECT ` + scheduleColumns + `
+		FROM schedule
+		WHERE ($3::text IS NULL OR tenant_id = $3)
+		ORDER BY created_at DESC, schedule_id DESC
+		LIMIT $1
+		OFFSET $2;`
+
+	rows, err := p.db.QueryContext(ctx, query, limit, offset, tenantArg(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to get list of schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.Schedule
+	for rows.Next() {
+		dto, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, scheduleToDomain(dto))
+	}
+	return results, rows.Err()
+}
+
+func (p *SchedulePostgres) UpdateScheduleState(
+	ctx context.Context,
+	id string,
+	status domain.ScheduleStatus,
+	nextRunAt *time.Time,
+) error {
+	query := `
+		UPDATE schedule
+		SET status      = $2,
+			next_run_at = $3,
+			updated_at  = NOW()
+		WHERE schedule_id = $1 AND ($4::text IS NULL OR tenant_id = $4);`
+
+	res, err := p.db.ExecContext(ctx, query, id, status, nextRunAt, tenantArg(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update schedule state: %w", err)
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (p *SchedulePostgres) DeleteSchedule(ctx context.Context, id string) ([]string, error) {
+	tenant := tenantArg(ctx)
+	var canceled []string
+	err := p.db.WithTx(ctx, func(tx *sql.Tx) error {
+		cancelQuery := `
+			UPDATE notify
+			SET status = $2, updated_at = NOW(), version = version + 1
+			WHERE schedule_id = $1 AND status IN ($3, $4) AND ($5::text IS NULL OR tenant_id = $5)
+			RETURNING notify_id;`
+
+		rows, err := tx.QueryContext(ctx, cancelQuery,
+			id, domain.StatusCanceled, domain.StatusPending, domain.StatusInProcess, tenant)
+		if err != nil {
+			return fmt.Errorf("failed to cancel schedule notifies: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var notifyID string
+			if err := rows.Scan(&notifyID); err != nil {
+				return fmt.Errorf("failed to scan canceled notify id: %w", err)
+			}
+			canceled = append(canceled, notifyID)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read canceled notify ids: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx,
+			`DELETE FROM schedule WHERE schedule_id = $1 AND ($2::text IS NULL OR tenant_id = $2);`, id, tenant)
+		if err != nil {
+			return fmt.Errorf("failed to delete schedule: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return domain.ErrScheduleNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return canceled, nil
+}
+
+// - материализация срабатываний: для каждой наступившей серии создается notify
+// на next_run_at, а next_run_at сдвигается на следующее срабатывание после текущего момента.
+// Пропущенные за время простоя срабатывания схлопываются в одно.
+func (p *SchedulePostgres) MaterializeDue(ctx context.Context, limit int) (int, error) {
+	var created int
+
+	err := p.db.WithTx(ctx, func(tx *sql.Tx) error {
+		selectQuery := `
+			SELECT ` + scheduleColumns + `
+			FROM schedule
+			WHERE status = $1 AND next_run_at <= NOW() AND ($3::text IS NULL OR tenant_id = $3)
+			ORDER BY next_run_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED;`
+
+		rows, err := tx.QueryContext(ctx, selectQuery, domain.ScheduleActive, limit, tenantArg(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to fetch due schedules: %w", err)
+		}
+
+		var due []*domain.Schedule
+		for rows.Next() {
+			dto, err := scanSchedule(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			due = append(due, scheduleToDomain(dto))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		for _, s := range due {
+			n := domain.NewNotify()
+			n.Payload = s.Payload
+			n.Target = s.Target
+			n.Channel = s.Channel
+			n.Status = domain.StatusPending
+			n.ScheduledAt = *s.NextRunAt
+			n.ScheduleID = s.ID
+			n.TenantID = s.TenantID
+			dto := toPostgresDTO(n)
+
+			insertQuery := `
+				INSERT INTO notify (notify_id, payload, target, channel, status, scheduled_at, created_at, schedule_id, tenant_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+
+			if _, err := tx.ExecContext(ctx, insertQuery,
+				dto.ID, dto.Payload, dto.Target, dto.Channel, dto.Status, dto.ScheduledAt, dto.CreatedAt,
+				dto.ScheduleID, dto.TenantID); err != nil {
+				return fmt.Errorf("failed to materialize schedule %s: %w", s.ID, err)
+			}
+
+			status := domain.ScheduleActive
+			next, ok, err := s.Next(now)
+			if err != nil {
+				return fmt.Errorf("failed to compute next run for schedule %s: %w", s.ID, err)
+			}
+			nextRunAt := &next
+			if !ok {
+				status = domain.ScheduleFinished
+				nextRunAt = nil
+			}
+
+			updateQuery := `
+				UPDATE schedule
+				SET status = $2, next_run_at = $3, updated_at = NOW()
+				WHERE schedule_id = $1;`
+
+			if _, err := tx.ExecContext(ctx, updateQuery, s.ID, status, nextRunAt); err != nil {
+				return fmt.Errorf("failed to advance schedule %s: %w", s.ID, err)
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSchedule(row rowScanner) (*schedulePostgresDTO, error) {
+	var dto schedulePostgresDTO
+	err := row.Scan(
+		&dto.ID,
+		&dto.Payload,
+		&dto.Target,
+		&dto.Channel,
+		&dto.CronExpr,
+		&dto.IntervalSeconds,
+		&dto.Timezone,
+		&dto.StartAt,
+		&dto.EndAt,
+		&dto.NextRunAt,
+		&dto.Status,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+		&dto.TenantID,
+	)
+	return &dto, err
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/postgres"
+)
+
+const suppressionColumns = `suppression_id, tenant_id, channel, target, category, reason, created_at`
+
+type SuppressionPostgres struct {
+	db *postgres.DB
+}
+
+func NewSuppressionPostgres(db *postgres.DB) domain.SuppressionPostgres {
+	return &SuppressionPostgres{db: db}
+}
+
+func (p *SuppressionPostgres) CreateSuppression(ctx context.Context, s *domain.Suppression) error {
+	dto := toSuppressionDTO(s)
+
+	query := `
+		INSERT INTO suppression (` + suppressionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	_, err := p.db.ExecContext(ctx, query,
+		dto.ID, dto.TenantID, dto.Channel, dto.Target, dto.Category, dto.Reason, dto.CreatedAt)
+	if postgres.IsUniqueViolation(err) {
+		return domain.ErrSuppressionExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create suppression: %w", err)
+	}
+	return nil
+}
+
+// - арендатору видны его записи и записи для всех арендаторов
+func (p *SuppressionPostgres) ListSuppressions(ctx context.Context, f domain.SuppressionFilter) ([]*domain.Suppression, error) {
+	query := `
+		SELECT ` + suppressionColumns + `
+		FROM suppression
+		WHERE ($1::text IS NULL OR tenant_id = $1 OR tenant_id IS NULL)
+			AND ($2 = '' OR channel = $2)
+			AND ($3 = '' OR target = $3)
+		ORDER BY created_at DESC, suppression_id DESC
+		LIMIT $4
+		OFFSET $5;`
+
+	rows, err := p.db.QueryContext(ctx, query, tenantArg(ctx), f.Channel, f.Target, f.Limit, f.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to get list of suppressions: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.Suppression
+	for rows.Next() {
+		dto, err := scanSuppression(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, suppressionToDomain(dto))
+	}
+	return results, rows.Err()
+}
+
+// DeleteSuppression удаляет запись арендатора, записи для всех арендаторов - только
+// контекст с domain.ManagesAllTenants
+func (p *SuppressionPostgres) DeleteSuppression(ctx context.Context, id string) error {
+	query := `
+		DELETE FROM suppression
+		WHERE suppression_id = $1
+			AND ($2::text IS NULL OR tenant_id = $2 OR (tenant_id IS NULL AND $3));`
+
+	res, err := p.db.ExecContext(ctx, query, id, tenantArg(ctx), domain.ManagesAllTenants(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete suppression: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrSuppressionNotFound
+	}
+	return nil
+}
+
+func (p *SuppressionPostgres) FindSuppression(ctx context.Context, n *domain.Notify) (*domain.Suppression, error) {
+	query := `
+		SELECT ` + suppressionColumns + `
+		FROM suppression
+		WHERE channel = $1 AND target = $2
+			AND (tenant_id IS NULL OR tenant_id = $3)
+			AND (category = '' OR category = $4)
+		ORDER BY created_at
+		LIMIT 1;`
+
+	tenant := n.TenantID
+	if tenant == "" {
+		tenant = domain.DefaultTenant
+	}
+	dto, err := scanSuppression(p.db.QueryRowContext(ctx, query,
+		n.Channel, domain.NormalizeTarget(n.Channel, n.Target), tenant, n.Category))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSuppressionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find suppression: %w", err)
+	}
+	return suppressionToDomain(dto), nil
+}
+
+func scanSuppression(row rowScanner) (*suppressionPostgresDTO, error) {
+	var dto suppressionPostgresDTO
+	err := row.Scan(
+		&dto.ID,
+		&dto.TenantID,
+		&dto.Channel,
+		&dto.Target,
+		&dto.Category,
+		&dto.Reason,
+		&dto.CreatedAt,
+	)
+	return &dto, err
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/postgres"
+)
+
+const templateColumns = `template_id, tenant_id, name, channel, version, subject, body_text, body_html, created_at`
+
+type TemplatePostgres struct {
+	db *postgres.DB
+}
+
+func NewTemplatePostgres(db *postgres.DB) domain.TemplatePostgres {
+	return &TemplatePostgres{db: db}
+}
+
+// - номер версии вычисляется в том же запросе; при гонке двух обновлений
+// одно из них упадет на UNIQUE (tenant_id, name, channel, version)
+func (p *TemplatePostgres) CreateTemplateVersion(ctx context.Context, t *domain.Template) error {
+	t.TenantID = domain.TenantFor(ctx, t.TenantID)
+	dto := toTemplateDTO(t)
+
+	query := `
+		INSERT INTO template (template_id, tenant_id, name, channel, version, subject, body_text, body_html, created_at)
+		SELECT $1, $8, $2, $3, COALESCE(MAX(version), 0) + 1, $4, $5, $6, $7
+		FROM template
+		WHERE name = $2 AND channel = $3 AND tenant_id = $8
+		RETURNING version;`
+
+	err := p.db.QueryRowContext(ctx, query,
+		dto.ID, dto.Name, dto.Channel, dto.Subject, dto.Text, dto.HTML, dto.CreatedAt, dto.TenantID,
+	).Scan(&t.Version)
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return domain.ErrTemplateAlreadyExists
+		}
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+	return nil
+}
+
+func (p *TemplatePostgres) GetTemplate(ctx context.Context, name, channel string, version int) (*domain.Template, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM template
+		WHERE name = $1 AND channel = $2 AND ($3 = 0 OR version = $3)
+			AND ($4::text IS NULL OR tenant_id = $4)
+		ORDER BY version DESC
+		LIMIT 1;`
+
+	dto, err := scanTemplate(p.db.QueryRowContext(ctx, query, name, channel, version, tenantArg(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	return templateToDomain(dto), nil
+}
+
+func (p *TemplatePostgres) ListTemplates(ctx context.Context) ([]*domain.Template, error) {
+	query := `
+		SELECT DISTINCT ON (tenant_id, name, channel) ` + templateColumns + `
+		FROM template
+		WHERE ($1::text IS NULL OR tenant_id = $1)
+		ORDER BY tenant_id, name, channel, version DESC;`
+
+	return p.queryTemplates(ctx, query, tenantArg(ctx))
+}
+
+func (p *TemplatePostgres) ListTemplateVersions(ctx context.Context, name, channel string) ([]*domain.Template, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM template
+		WHERE name = $1 AND channel = $2 AND ($3::text IS NULL OR tenant_id = $3)
+		ORDER BY version DESC;`
+
+	templates, err := p.queryTemplates(ctx, query, name, channel, tenantArg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, domain.ErrTemplateNotFound
+	}
+	return templates, nil
+}
+
+func (p *TemplatePostgres) DeleteTemplate(ctx context.Context, name, channel string) error {
+	query := `DELETE FROM template WHERE name = $1 AND channel = $2 AND ($3::text IS NULL OR tenant_id = $3);`
+
+	res, err := p.db.ExecContext(ctx, query, name, channel, tenantArg(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrTemplateNotFound
+	}
+	return nil
+}
+
+func (p *TemplatePostgres) queryTemplates(ctx context.Context, query string, args ...any) ([]*domain.Template, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to get list of templates: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.Template
+	for rows.Next() {
+		dto, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, templateToDomain(dto))
+	}
+	return results, rows.Err()
+}
+
+func scanTemplate(row rowScanner) (*templatePostgresDTO, error) {
+	var dto templatePostgresDTO
+	err := row.Scan(
+		&dto.ID,
+		&dto.TenantID,
+		&dto.Name,
+		&dto.Channel,
+		&dto.Version,
+		&dto.Subject,
+		&dto.Text,
+		&dto.HTML,
+		&dto.CreatedAt,
+	)
+	return &dto, err
+}
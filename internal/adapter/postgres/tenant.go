@@ -0,0 +1,16 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// tenantArg - значение параметра условия "($n::text IS NULL OR tenant_id = $n)":
+// арендатор из контекста или NULL для системного контекста, которому видны все строки
+func tenantArg(ctx context.Context) any {
+	if tenant, ok := domain.TenantFrom(ctx); ok {
+		return tenant
+	}
+	return nil
+}
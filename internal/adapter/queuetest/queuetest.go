@@ -0,0 +1,224 @@
+// Package queuetest - общий набор тестов, который должна проходить каждая реализация domain.QueueProvider.
+package queuetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+const (
+	receiveTimeout = 15 * time.Second
+	stopTimeout    = 10 * time.Second
+	// earlyTolerance - допустимый сдвиг часов между брокером и тестом
+	earlyTolerance = 200 * time.Millisecond
+)
+
+// NewQueue создает инициализированного провайдера. Закрывать его должен сам
+// фабричный метод через t.Cleanup
+type NewQueue func(t *testing.T) domain.QueueProvider
+
+// message - поля сообщения, которые читает воркер
+type message struct {
+	ID          string        `json:"id"`
+	Payload     []byte        `json:"payload"`
+	Target      string        `json:"target"`
+	Channel     string        `json:"channel"`
+	Status      domain.Status `json:"status"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	RetryCount  int           `json:"retry_count"`
+	LastError   *string       `json:"last_error"`
+}
+
+type received struct {
+	msg message
+	at  time.Time
+}
+
+// Run прогоняет набор проверок. Провайдеры могут делить брокер между
+// подтестами, поэтому каждая проверка ждет только свои notify по ID
+func Run(t *testing.T, newQueue NewQueue) {
+	t.Run("RoundTrip", func(t *testing.T) { testRoundTrip(t, newQueue(t)) })
+	t.Run("DeliversAll", func(t *testing.T) { testDeliversAll(t, newQueue(t)) })
+	t.Run("Delayed", func(t *testing.T) { testDelayed(t, newQueue(t)) })
+	t.Run("ConsumeStopsOnCancel", func(t *testing.T) { testConsumeStops(t, newQueue(t)) })
+	t.Run("Ping", func(t *testing.T) { testPing(t, newQueue(t)) })
+}
+
+func testRoundTrip(t *testing.T, q domain.QueueProvider) {
+	lastErr := "timeout"
+	n := newNotify()
+	n.Payload = []byte(`{"text":"hello"}`)
+	n.RetryCount = 2
+	n.LastError = &lastErr
+
+	msgs := consume(t, q)
+
+	// Act
+	if err := q.Publish(context.Background(), n); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// Assert
+	got := waitFor(t, msgs, n.ID)
+	if string(got.msg.Payload) != string(n.Payload) || got.msg.Target != n.Target || got.msg.Channel != n.Channel {
+		t.Errorf("message mismatch: got %+v", got.msg)
+	}
+	if got.msg.Status != n.Status || got.msg.RetryCount != n.RetryCount {
+		t.Errorf("expected status %v and retry_count %d, got %v and %d", n.Status, n.RetryCount, got.msg.Status, got.msg.RetryCount)
+	}
+	if got.msg.LastError == nil || *got.msg.LastError != lastErr {
+		t.Errorf("expected last_error %q, got %v", lastErr, got.msg.LastError)
+	}
+	if !got.msg.ScheduledAt.Equal(n.ScheduledAt) {
+		t.Errorf("expected scheduled_at %v, got %v", n.ScheduledAt, got.msg.ScheduledAt)
+	}
+}
+
+func testDeliversAll(t *testing.T, q domain.QueueProvider) {
+	const count = 20
+
+	msgs := consume(t, q)
+
+	// Act
+	want := make(map[string]bool, count)
+	for range count {
+		n := newNotify()
+		want[n.ID] = true
+		if err := q.Publish(context.Background(), n); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	// Assert: каждый notify доставлен хотя бы раз
+	deadline := time.After(receiveTimeout)
+	for len(want) > 0 {
+		select {
+		case r := <-msgs:
+			delete(want, r.msg.ID)
+		case <-deadline:
+			t.Fatalf("%d of %d messages were not delivered", len(want), count)
+		}
+	}
+}
+
+func testDelayed(t *testing.T, q domain.QueueProvider) {
+	n := newNotify()
+	n.ScheduledAt = time.Now().Add(1500 * time.Millisecond).UTC()
+
+	msgs := consume(t, q)
+
+	// Act
+	if err := q.Publish(context.Background(), n); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// Assert: сообщение не пришло раньше scheduled_at
+	got := waitFor(t, msgs, n.ID)
+	if early := n.ScheduledAt.Sub(got.at); early > earlyTolerance {
+		t.Errorf("message delivered %v before scheduled_at", early)
+	}
+}
+
+func testConsumeStops(t *testing.T, q domain.QueueProvider) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = q.Consume(ctx, func(context.Context, []byte) error { return nil })
+	}()
+
+	// Act
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(stopTimeout):
+		t.Fatal("Consume did not return after context cancel")
+	}
+}
+
+func testPing(t *testing.T, q domain.QueueProvider) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := q.Ping(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+var seq atomic.Int64
+
+func newNotify() *domain.Notify {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return &domain.Notify{
+		ID:          fmt.Sprintf("queuetest-%d-%d", now.UnixNano(), seq.Add(1)),
+		Payload:     []byte("hi"),
+		Target:      "user@example.com",
+		Channel:     "email",
+		Status:      domain.StatusInProcess,
+		ScheduledAt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// consume запускает Consume до конца теста и проверяет, что он завершается после отмены
+func consume(t *testing.T, q domain.QueueProvider) <-chan received {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msgs := make(chan received, 100)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		_ = q.Consume(ctx, func(_ context.Context, payload []byte) error {
+			r := received{at: time.Now()}
+			if err := json.Unmarshal(payload, &r.msg); err != nil {
+				t.Errorf("unmarshal message: %v", err)
+				return nil
+			}
+			select {
+			case msgs <- r:
+			case <-ctx.Done():
+			}
+			return nil
+		})
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(stopTimeout):
+			t.Error("Consume did not return after context cancel")
+		}
+	})
+
+	return msgs
+}
+
+func waitFor(t *testing.T, msgs <-chan received, id string) received {
+	t.Helper()
+
+	deadline := time.After(receiveTimeout)
+	for {
+		select {
+		case r := <-msgs:
+			if r.msg.ID == id {
+				return r
+			}
+		case <-deadline:
+			t.Fatalf("message %s was not delivered", id)
+			return received{}
+		}
+	}
+}
@@ -16,6 +16,7 @@ type NotifyRabbitDTO struct {
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
 	RetryCount  int           `json:"retry_count"`
+	TenantID    string        `json:"tenant_id,omitempty"`
 	LastError   *string       `json:"last_error"`
 }
 
@@ -30,6 +31,7 @@ func toRabbitDTO(n *domain.Notify) *NotifyRabbitDTO {
 		CreatedAt:   n.CreatedAt,
 		UpdatedAt:   n.UpdatedAt,
 		RetryCount:  n.RetryCount,
+		TenantID:    n.TenantID,
 		LastError:   n.LastError,
 	}
 }
@@ -45,6 +47,7 @@ func toDomain(dto NotifyRabbitDTO) *domain.Notify {
 		CreatedAt:   dto.CreatedAt,
 		UpdatedAt:   dto.UpdatedAt,
 		RetryCount:  dto.RetryCount,
+		TenantID:    dto.TenantID,
 		LastError:   dto.LastError,
 	}
 }
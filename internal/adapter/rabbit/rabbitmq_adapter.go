@@ -3,6 +3,7 @@ package rabbit
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,31 +19,70 @@ const (
 	queueName    = "notifications_queue"
 	routingKey   = "notification_key"
 	contentType  = "application/json"
+
+	// waitExchangeName - обычный direct exchange для стратегии wait_queue:
+	// имя delayed_exchange занято exchange типа x-delayed-message
+	waitExchangeName = "notify_exchange"
+	// waitQueueIdle - сколько очередь ожидания живет без сообщений после истечения задержки
+	waitQueueIdle = time.Minute
+
+	defaultWorkers       = 5
+	defaultPrefetchCount = 10
 )
 
 type NotifyQueueAdapter struct {
-	client    *rabbitmq.RabbitClient
-	publisher *rabbitmq.Publisher
+	client        *rabbitmq.RabbitClient
+	publisher     *rabbitmq.Publisher
+	waitPublisher *rabbitmq.Publisher // default exchange, маршрутизация по имени очереди ожидания
+	strategy      string
+	workers       int
+	prefetchCount int
 }
 
 func NewRabbitQueueAdapter(cfg rabbit.Config) (domain.QueueProvider, error) {
+	strategy := cfg.DelayStrategy
+	if strategy == "" {
+		strategy = rabbit.DelayExchange
+	}
+	if strategy != rabbit.DelayExchange && strategy != rabbit.DelayWaitQueue {
+		return nil, fmt.Errorf("unknown rabbit delay strategy %q", strategy)
+	}
+
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.PrefetchCount <= 0 {
+		cfg.PrefetchCount = defaultPrefetchCount
+	}
+
 	client, err := rabbit.NewClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect rabbitmq: %w", err)
 	}
 
-	pub := rabbitmq.NewPublisher(client, exchangeName, contentType)
+	exchange := exchangeName
+	if strategy == rabbit.DelayWaitQueue {
+		exchange = waitExchangeName
+	}
 
 	return &NotifyQueueAdapter{
-		client:    client,
-		publisher: pub,
+		client:        client,
+		publisher:     rabbitmq.NewPublisher(client, exchange, contentType),
+		waitPublisher: rabbitmq.NewPublisher(client, "", contentType),
+		strategy:      strategy,
+		workers:       cfg.Workers,
+		prefetchCount: cfg.PrefetchCount,
 	}, nil
 }
 
 func (q *NotifyQueueAdapter) Init() error {
+	if err := q.declareExchange(); err != nil {
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
 	if err := q.client.DeclareQueue(
 		queueName,
-		exchangeName,
+		q.publisher.GetExchangeName(),
 		routingKey,
 		true,  // durable
 		false, // autoDelete
@@ -55,6 +95,15 @@ func (q *NotifyQueueAdapter) Init() error {
 	return nil
 }
 
+func (q *NotifyQueueAdapter) declareExchange() error {
+	if q.strategy == rabbit.DelayWaitQueue {
+		return q.client.DeclareExchange(waitExchangeName, amqp091.ExchangeDirect, true, false, false, nil)
+	}
+	return q.client.DeclareExchange(exchangeName, "x-delayed-message", true, false, false, amqp091.Table{
+		"x-delayed-type": amqp091.ExchangeDirect,
+	})
+}
+
 func (q *NotifyQueueAdapter) Publish(ctx context.Context, n *domain.Notify) error {
 	delay := max(time.Until(n.ScheduledAt), 0)
 
@@ -64,14 +113,49 @@ func (q *NotifyQueueAdapter) Publish(ctx context.Context, n *domain.Notify) erro
 		return fmt.Errorf("marshal notify: %w", err)
 	}
 
+	if q.strategy == rabbit.DelayWaitQueue {
+		return q.publishToWaitQueue(ctx, body, delay)
+	}
+
 	return q.publisher.Publish(
 		ctx,
 		body,
 		routingKey,
-		rabbitmq.WithExpiration(delay),
+		rabbitmq.WithHeaders(delayHeaders(delay)),
 	)
 }
 
+// publishToWaitQueue кладет сообщение в очередь ожидания с TTL, равным задержке.
+// У каждой очереди один TTL, поэтому сообщения с разной задержкой не блокируют
+// друг друга в голове очереди
+func (q *NotifyQueueAdapter) publishToWaitQueue(ctx context.Context, body []byte, delay time.Duration) error {
+	delay = waitQueueDelay(delay)
+	if delay == 0 {
+		return q.publisher.Publish(ctx, body, routingKey)
+	}
+
+	name := waitQueueName(delay)
+	if err := q.declareWaitQueue(name, delay); err != nil {
+		return fmt.Errorf("failed to declare wait queue %s: %w", name, err)
+	}
+
+	return q.waitPublisher.Publish(ctx, body, name)
+}
+
+// declareWaitQueue объявляет очередь ожидания перед каждой публикацией:
+// объявление идемпотентно и продлевает x-expires, так что очередь не удалится
+// между объявлением и публикацией
+func (q *NotifyQueueAdapter) declareWaitQueue(name string, delay time.Duration) error {
+	ch, err := q.client.GetChannel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	_, err = ch.QueueDeclare(name, true, false, false, false, waitQueueArgs(delay))
+	return err
+}
+
 func (q *NotifyQueueAdapter) Consume(ctx context.Context, handler domain.MessageHandler) error {
 	wbfHandler := func(c context.Context, d amqp091.Delivery) error {
 		return handler(c, d.Body)
@@ -80,14 +164,58 @@ func (q *NotifyQueueAdapter) Consume(ctx context.Context, handler domain.Message
 	cfg := rabbitmq.ConsumerConfig{
 		Queue:         queueName,
 		ConsumerTag:   "notifier-worker",
-		Workers:       5,
-		PrefetchCount: 10,
+		Workers:       q.workers,
+		PrefetchCount: q.prefetchCount,
 	}
 
 	consumer := rabbitmq.NewConsumer(q.client, cfg, wbfHandler)
 	return consumer.Start(ctx)
 }
 
+func (q *NotifyQueueAdapter) Ping(ctx context.Context) error {
+	if !q.client.Healthy() {
+		return errors.New("rabbitmq connection is closed")
+	}
+
+	ch, err := q.client.GetChannel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	return ch.Close()
+}
+
 func (q *NotifyQueueAdapter) Close() error {
 	return q.client.Close()
 }
+
+// delayHeaders - заголовок x-delay в миллисекундах для exchange x-delayed-message
+func delayHeaders(delay time.Duration) amqp091.Table {
+	return amqp091.Table{"x-delay": delay.Milliseconds()}
+}
+
+// waitQueueDelay округляет задержку вверх до секунды, чтобы ограничить число очередей ожидания
+func waitQueueDelay(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	rounded := delay.Truncate(time.Second)
+	if rounded < delay {
+		rounded += time.Second
+	}
+	return rounded
+}
+
+func waitQueueName(delay time.Duration) string {
+	return fmt.Sprintf("%s.wait.%ds", queueName, int64(delay/time.Second))
+}
+
+// waitQueueArgs - TTL сообщений, перенаправление истекших в рабочую очередь
+// и удаление очереди, когда она больше не используется
+func waitQueueArgs(delay time.Duration) amqp091.Table {
+	return amqp091.Table{
+		"x-message-ttl":             delay.Milliseconds(),
+		"x-dead-letter-exchange":    waitExchangeName,
+		"x-dead-letter-routing-key": routingKey,
+		"x-expires":                 (delay + waitQueueIdle).Milliseconds(),
+	}
+}
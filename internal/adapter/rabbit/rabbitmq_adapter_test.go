@@ -0,0 +1,95 @@
+package rabbit
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/queuetest"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/rabbit"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestNewRabbitQueueAdapter_UnknownStrategy(t *testing.T) {
+	_, err := NewRabbitQueueAdapter(rabbit.Config{URL: "amqp://localhost", DelayStrategy: "ttl"})
+	if err == nil {
+		t.Fatal("expected error for unknown delay strategy")
+	}
+}
+
+func TestDelayHeaders(t *testing.T) {
+	headers := delayHeaders(90 * time.Second)
+	if headers["x-delay"] != int64(90000) {
+		t.Errorf("expected x-delay 90000, got %v", headers["x-delay"])
+	}
+}
+
+func TestWaitQueueDelay(t *testing.T) {
+	cases := []struct {
+		delay time.Duration
+		want  time.Duration
+	}{
+		{0, 0},
+		{-time.Second, 0},
+		{time.Millisecond, time.Second},
+		{2 * time.Second, 2 * time.Second},
+		{2*time.Second + time.Millisecond, 3 * time.Second},
+	}
+	for _, c := range cases {
+		if got := waitQueueDelay(c.delay); got != c.want {
+			t.Errorf("waitQueueDelay(%v) = %v, want %v", c.delay, got, c.want)
+		}
+	}
+}
+
+func TestWaitQueueArgs(t *testing.T) {
+	delay := 30 * time.Second
+	args := waitQueueArgs(delay)
+
+	if name := waitQueueName(delay); name != "notifications_queue.wait.30s" {
+		t.Errorf("unexpected wait queue name %q", name)
+	}
+	if args["x-message-ttl"] != int64(30000) {
+		t.Errorf("expected ttl 30000, got %v", args["x-message-ttl"])
+	}
+	// истекшие сообщения возвращаются в рабочую очередь
+	if args["x-dead-letter-exchange"] != waitExchangeName || args["x-dead-letter-routing-key"] != routingKey {
+		t.Errorf("unexpected dead-letter routing: %v", args)
+	}
+	if args["x-expires"] != (delay + waitQueueIdle).Milliseconds() {
+		t.Errorf("expected queue to expire after delay + idle, got %v", args["x-expires"])
+	}
+}
+
+// Тесты с брокером запускаются, только если задан RABBIT_URL
+func TestRabbitQueue_Conformance(t *testing.T) {
+	url := os.Getenv("RABBIT_URL")
+	if url == "" {
+		t.Skip("RABBIT_URL is not set")
+	}
+
+	strat := retry.Strategy{Attempts: 3, Delay: 100 * time.Millisecond, Backoff: 2}
+	for _, strategy := range []string{rabbit.DelayExchange, rabbit.DelayWaitQueue} {
+		t.Run(strategy, func(t *testing.T) {
+			queuetest.Run(t, func(t *testing.T) domain.QueueProvider {
+				q, err := NewRabbitQueueAdapter(rabbit.Config{
+					URL:            url,
+					ConnectionName: "queuetest",
+					ReconnectStrat: strat,
+					ProducingStrat: strat,
+					ConsumingStrat: strat,
+					DelayStrategy:  strategy,
+				})
+				if err != nil {
+					t.Fatalf("connect: %v", err)
+				}
+				if err := q.Init(); err != nil {
+					t.Fatalf("init: %v", err)
+				}
+				t.Cleanup(func() { q.Close() })
+				return q
+			})
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 type NotifyRedisDTO struct {
 	ID          string        `json:"notify_id"`
+	TenantID    string        `json:"tenant_id"`
 	Payload     []byte        `json:"payload"`
 	Target      string        `json:"target"`
 	Channel     string        `json:"channel"`
@@ -18,20 +19,34 @@ type NotifyRedisDTO struct {
 	UpdatedAt   time.Time     `json:"updated_at"`
 	RetryCount  int           `json:"retry_count"`
 	LastError   *string       `json:"last_error"`
+	Version     int           `json:"version"`
+
+	RetryPolicy    *domain.RetryPolicy    `json:"retry_policy,omitempty"`
+	DeliveryWindow *domain.DeliveryWindow `json:"delivery_window,omitempty"`
+	RecipientID    string                 `json:"recipient_id,omitempty"`
+	Fallbacks      []domain.Route         `json:"fallbacks,omitempty"`
+	Category       string                 `json:"category,omitempty"`
 }
 
 func toRedisDTO(n *domain.Notify) ([]byte, error) {
 	redistDTO := &NotifyRedisDTO{
-		ID:          n.ID,
-		Payload:     n.Payload,
-		Target:      n.Target,
-		Channel:     n.Channel,
-		Status:      n.Status,
-		ScheduledAt: n.ScheduledAt,
-		CreatedAt:   n.CreatedAt,
-		UpdatedAt:   n.UpdatedAt,
-		RetryCount:  n.RetryCount,
-		LastError:   n.LastError,
+		ID:             n.ID,
+		TenantID:       n.TenantID,
+		Payload:        n.Payload,
+		Target:         n.Target,
+		Channel:        n.Channel,
+		Status:         n.Status,
+		ScheduledAt:    n.ScheduledAt,
+		CreatedAt:      n.CreatedAt,
+		UpdatedAt:      n.UpdatedAt,
+		RetryCount:     n.RetryCount,
+		LastError:      n.LastError,
+		Version:        n.Version,
+		RetryPolicy:    n.RetryPolicy,
+		DeliveryWindow: n.DeliveryWindow,
+		RecipientID:    n.RecipientID,
+		Fallbacks:      n.Fallbacks,
+		Category:       n.Category,
 	}
 
 	payload, err := json.Marshal(redistDTO)
@@ -43,15 +58,22 @@ func toDomain(payload string) *domain.Notify {
 	json.Unmarshal([]byte(payload), &dto)
 
 	return &domain.Notify{
-		ID:          dto.ID,
-		Payload:     dto.Payload,
-		Target:      dto.Target,
-		Channel:     dto.Channel,
-		Status:      dto.Status,
-		ScheduledAt: dto.ScheduledAt,
-		CreatedAt:   dto.CreatedAt,
-		UpdatedAt:   dto.UpdatedAt,
-		RetryCount:  dto.RetryCount,
-		LastError:   dto.LastError,
+		ID:             dto.ID,
+		TenantID:       dto.TenantID,
+		Payload:        dto.Payload,
+		Target:         dto.Target,
+		Channel:        dto.Channel,
+		Status:         dto.Status,
+		ScheduledAt:    dto.ScheduledAt,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+		RetryCount:     dto.RetryCount,
+		LastError:      dto.LastError,
+		Version:        dto.Version,
+		RetryPolicy:    dto.RetryPolicy,
+		DeliveryWindow: dto.DeliveryWindow,
+		RecipientID:    dto.RecipientID,
+		Fallbacks:      dto.Fallbacks,
+		Category:       dto.Category,
 	}
 }
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -47,6 +48,72 @@ func (r *Redis) Get(ctx context.Context, id string) (*domain.Notify, error) {
 	return n, nil
 }
 
+// UpdateStatus обновляет закешированный notify в транзакции с WATCH, чтобы не затереть
+// конкурентную запись. При конфликте запись удаляется: следующее чтение пойдет в БД
+func (r *Redis) UpdateStatus(
+	ctx context.Context,
+	id string,
+	status domain.Status,
+	scheduledAt *time.Time,
+	retryCount int,
+	lastErr *string,
+) error {
+	key := fmt.Sprintf("%s:%s", keyPrefix, id)
+
+	update := func(tx *redis.Tx) error {
+		payload, err := tx.Get(ctx, key).Result()
+		if err == redis.RedisError {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		n := toDomain(payload)
+		n.Status = status
+		if scheduledAt != nil {
+			n.ScheduledAt = *scheduledAt
+		}
+		n.RetryCount = retryCount
+		n.LastError = lastErr
+		n.UpdatedAt = time.Now().UTC()
+		// смена статуса в БД тоже поднимает версию
+		n.Version++
+
+		value, err := toRedisDTO(n)
+		if err != nil {
+			return fmt.Errorf("failed to serialize notify into json")
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, value, redis.KeepTTL)
+			return nil
+		})
+		return err
+	}
+
+	err := r.redis.Watch(ctx, update, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return r.Delete(ctx, id)
+	}
+	if err != nil {
+		return fmt.Errorf("redis error: %w", err)
+	}
+	return nil
+}
+
+func (r *Redis) Delete(ctx context.Context, id string) error {
+	key := fmt.Sprintf("%s:%s", keyPrefix, id)
+	if err := r.redis.Del(ctx, key); err != nil {
+		return fmt.Errorf("redis error: %w", err)
+	}
+	return nil
+}
+
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.redis.Ping(ctx)
+}
+
 func (r *Redis) Close() error {
 	return r.redis.Close()
 }
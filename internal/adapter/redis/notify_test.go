@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/storetest"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/redis"
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, domain.NotifyRedis) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	r := New(redis.Config{Addr: srv.Addr(), TTL: time.Hour})
+	t.Cleanup(func() { r.Close() })
+	return srv, r
+}
+
+func TestRedis_UpdateStatus_Cached(t *testing.T) {
+	srv, r := newTestRedis(t)
+	ctx := context.Background()
+
+	n := &domain.Notify{ID: "id-1", Channel: "email", Payload: []byte("hi"), Status: domain.StatusPending}
+	if err := r.SetWithExpiration(ctx, n); err != nil {
+		t.Fatalf("failed to cache notify: %v", err)
+	}
+
+	// Act
+	lastErr := "timeout"
+	next := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	if err := r.UpdateStatus(ctx, n.ID, domain.StatusPending, &next, 1, &lastErr); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Assert: статус и поля обновлены, остальное и TTL сохранены
+	got, err := r.Get(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("expected cached notify, got %v", err)
+	}
+	if got.RetryCount != 1 || got.LastError == nil || *got.LastError != lastErr || !got.ScheduledAt.Equal(next) {
+		t.Errorf("unexpected cached notify: %+v", got)
+	}
+	if string(got.Payload) != "hi" || got.Channel != "email" {
+		t.Errorf("expected payload and channel preserved, got %+v", got)
+	}
+	if ttl := srv.TTL("notify:" + n.ID); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("expected ttl preserved, got %v", ttl)
+	}
+}
+
+func TestRedis_UpdateStatus_NotCached(t *testing.T) {
+	srv, r := newTestRedis(t)
+	ctx := context.Background()
+
+	// Act
+	if err := r.UpdateStatus(ctx, "id-1", domain.StatusSent, nil, 0, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Assert: отсутствующая запись не создается
+	if srv.Exists("notify:id-1") {
+		t.Error("expected no cache entry to be created")
+	}
+}
+
+func TestCacheMode_SyncStatus_NoStaleRead(t *testing.T) {
+	for _, mode := range []domain.CacheMode{domain.CacheWriteThrough, domain.CacheInvalidate} {
+		t.Run(string(mode), func(t *testing.T) {
+			_, r := newTestRedis(t)
+			ctx := context.Background()
+
+			n := &domain.Notify{ID: "id-1", Status: domain.StatusPending}
+			if err := r.SetWithExpiration(ctx, n); err != nil {
+				t.Fatalf("failed to cache notify: %v", err)
+			}
+
+			// Act
+			if err := mode.SyncStatus(ctx, r, n.ID, domain.StatusSent, nil, 0, nil); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			// Assert: кеш либо отдает новый статус, либо промахивается в БД
+			got, err := r.Get(ctx, n.ID)
+			switch mode {
+			case domain.CacheWriteThrough:
+				if err != nil || got.Status != domain.StatusSent {
+					t.Errorf("expected Sent from cache, got %v (err=%v)", got.Status, err)
+				}
+			case domain.CacheInvalidate:
+				if !errors.Is(err, domain.ErrNotFound) {
+					t.Errorf("expected cache miss, got %v (err=%v)", got.Status, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRedis_Contract(t *testing.T) {
+	storetest.RunNotifyRedis(t, func(t *testing.T) domain.NotifyRedis {
+		_, r := newTestRedis(t)
+		return r
+	})
+}
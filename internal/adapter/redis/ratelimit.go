@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/redis"
+)
+
+const rateKeyPrefix = "ratelimit"
+
+// takeScript - token bucket по нескольким бакетам за один вызов. Бакет - hash {tokens, ts},
+// токены пополняются лениво при чтении. Токены списываются, только если они есть во всех
+// бакетах, иначе скрипт возвращает ожидание в микросекундах.
+// Время берется из часов Redis (TIME), а не экземпляров сервиса: расхождение их часов
+// иначе неверно пополняло бы общие бакеты. На Redis < 5 для записи после TIME нужна
+// репликация эффектов, дальше она включена по умолчанию.
+// ARGV: пары capacity и interval (микросекунды на токен)
+var takeScript = redis.NewScript(`
+if redis.replicate_commands then
+  redis.replicate_commands()
+end
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local wait = 0
+local tokens = {}
+for i, key in ipairs(KEYS) do
+  local capacity = tonumber(ARGV[2 * i - 1])
+  local interval = tonumber(ARGV[2 * i])
+  local state = redis.call('HMGET', key, 'tokens', 'ts')
+  local t = tonumber(state[1]) or capacity
+  local ts = tonumber(state[2]) or now
+  t = math.min(capacity, t + math.max(0, now - ts) / interval)
+  if t < 1 then
+    wait = math.max(wait, (1 - t) * interval)
+  end
+  tokens[i] = t
+end
+if wait > 0 then
+  return math.ceil(wait)
+end
+for i, key in ipairs(KEYS) do
+  local capacity = tonumber(ARGV[2 * i - 1])
+  local interval = tonumber(ARGV[2 * i])
+  redis.call('HSET', key, 'tokens', tostring(tokens[i] - 1), 'ts', tostring(now))
+  redis.call('PEXPIRE', key, math.ceil(capacity * interval / 1000) + 1000)
+end
+return 0
+`)
+
+type RateLimiter struct {
+	redis *redis.RDB
+}
+
+// NewRateLimiter - лимитер на Redis, общий для всех экземпляров сервиса
+func NewRateLimiter(cfg redis.Config) domain.RateLimiter {
+	return &RateLimiter{
+		redis: redis.New(cfg),
+	}
+}
+
+func (l *RateLimiter) Take(ctx context.Context, buckets []domain.RateBucket) (time.Duration, error) {
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, 0, len(buckets))
+	args := make([]any, 0, 2*len(buckets))
+	for _, b := range buckets {
+		keys = append(keys, fmt.Sprintf("%s:%s", rateKeyPrefix, b.Key))
+		args = append(args, b.Limit.Capacity(), b.Limit.Interval().Microseconds())
+	}
+
+	wait, err := takeScript.Run(ctx, l.redis, keys, args...).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("redis error: %w", err)
+	}
+	return time.Duration(wait) * time.Microsecond, nil
+}
+
+func (l *RateLimiter) Close() error {
+	return l.redis.Close()
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/storetest"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/redis"
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRateLimiter_Contract(t *testing.T) {
+	storetest.RunRateLimiter(t, func(t *testing.T) domain.RateLimiter {
+		srv := miniredis.RunT(t)
+		l := NewRateLimiter(redis.Config{Addr: srv.Addr()})
+		t.Cleanup(func() { l.Close() })
+		return l
+	})
+}
+
+func TestRateLimiter_SharedBetweenInstances(t *testing.T) {
+	srv := miniredis.RunT(t)
+	first := NewRateLimiter(redis.Config{Addr: srv.Addr()})
+	second := NewRateLimiter(redis.Config{Addr: srv.Addr()})
+	t.Cleanup(func() { first.Close(); second.Close() })
+	ctx := context.Background()
+	bucket := []domain.RateBucket{{Key: "channel:telegram", Limit: domain.RateLimit{Limit: 1, Period: time.Minute}}}
+
+	// Act
+	if wait, err := first.Take(ctx, bucket); err != nil || wait != 0 {
+		t.Fatalf("expected token, got wait %v, %v", wait, err)
+	}
+	wait, err := second.Take(ctx, bucket)
+
+	// Assert: токен, взятый одним экземпляром, не достается другому
+	if err != nil || wait == 0 {
+		t.Errorf("expected throttling on second instance, got wait %v, %v", wait, err)
+	}
+	if ttl := srv.TTL("ratelimit:channel:telegram"); ttl <= 0 {
+		t.Errorf("expected bucket key to expire, got ttl %v", ttl)
+	}
+}
+
+func TestRateLimiter_UsesRedisClock(t *testing.T) {
+	srv := miniredis.RunT(t)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	srv.SetTime(start)
+	l := NewRateLimiter(redis.Config{Addr: srv.Addr()})
+	t.Cleanup(func() { l.Close() })
+	ctx := context.Background()
+	bucket := []domain.RateBucket{{Key: "channel:telegram", Limit: domain.RateLimit{Limit: 1, Period: time.Hour}}}
+
+	if wait, err := l.Take(ctx, bucket); err != nil || wait != 0 {
+		t.Fatalf("expected token, got wait %v, %v", wait, err)
+	}
+	if wait, err := l.Take(ctx, bucket); err != nil || wait != time.Hour {
+		t.Fatalf("expected wait of a full period by redis clock, got %v, %v", wait, err)
+	}
+
+	// Act: часы Redis ушли вперед на период, часы экземпляра - нет
+	srv.SetTime(start.Add(time.Hour))
+	wait, err := l.Take(ctx, bucket)
+
+	// Assert: бакет пополняется по часам Redis
+	if err != nil || wait != 0 {
+		t.Errorf("expected token after redis clock advanced, got wait %v, %v", wait, err)
+	}
+}
@@ -1,8 +1,15 @@
 package sender
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"syscall"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/internal/domain"
@@ -10,6 +17,13 @@ import (
 	"github.com/wneessen/go-mail"
 )
 
+const (
+	defaultEmailSubject      = "Delayed Notification"
+	defaultAttachmentTimeout = 30 * time.Second
+	maxAttachmentRedirects   = 5
+	smtpTimeout              = 10 * time.Second
+)
+
 type EmailConfig struct {
 	SMTPHost     string `mapstructure:"smtp_host"`
 	SMTPPort     int    `mapstructure:"smtp_port"`
@@ -17,59 +31,315 @@ type EmailConfig struct {
 	SMTPPassword string `mapstructure:"smtp_password"`
 	FromEmail    string `mapstructure:"from_email"`
 	FromName     string `mapstructure:"from_name"`
+
+	// PoolSize - сколько SMTP-сессий открыто одновременно, IdleTimeout - сколько
+	// сессия может простаивать до закрытия. Ноль - значения по умолчанию
+	PoolSize    int           `mapstructure:"pool_size"`
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
 }
 
 type EmailSender struct {
-	config EmailConfig
-	log    log.Log
+	config      EmailConfig
+	unsubscribe domain.UnsubscribeConfig
+	log         log.Log
+	// client скачивает вложения, заданные ссылкой
+	client *http.Client
+
+	// pool - nil, если настройки SMTP невалидны: ошибка возвращается при отправке
+	pool    *smtpPool
+	poolErr error
+}
+
+func NewEmailSender(config EmailConfig, unsubscribe domain.UnsubscribeConfig, log log.Log) domain.Sender {
+	return newEmailSender(config, unsubscribe, log,
+		mail.WithSMTPAuth(mail.SMTPAuthPlain),
+		mail.WithUsername(config.SMTPUsername),
+		mail.WithPassword(config.SMTPPassword),
+		mail.WithTLSPolicy(mail.TLSMandatory),
+	)
+}
+
+// newEmailSender принимает опции подключения отдельно, чтобы тесты могли
+// отправлять на локальный SMTP-сервер без TLS и AUTH
+func newEmailSender(config EmailConfig, unsubscribe domain.UnsubscribeConfig, log log.Log, opts ...mail.Option) *EmailSender {
+	s := &EmailSender{
+		config:      config,
+		unsubscribe: unsubscribe,
+		log:         log,
+		client:      newAttachmentClient(),
+	}
+
+	opts = append([]mail.Option{mail.WithPort(config.SMTPPort), mail.WithTimeout(smtpTimeout)}, opts...)
+	client, err := mail.NewClient(config.SMTPHost, opts...)
+	if err != nil {
+		s.poolErr = err
+		return s
+	}
+	s.pool = newSMTPPool(client, config.PoolSize, config.IdleTimeout)
+	return s
 }
 
-func NewEmailSender(config EmailConfig, log log.Log) domain.Sender {
-	return &EmailSender{
-		config: config,
-		log:    log,
+// Close закрывает свободные SMTP-сессии
+func (s *EmailSender) Close() error {
+	if s.pool == nil {
+		return nil
 	}
+	return s.pool.Close()
 }
 
-func (s *EmailSender) Send(ctx context.Context, n *domain.Notify) error {
+func (s *EmailSender) Send(ctx context.Context, n *domain.Notify) (*domain.Delivery, error) {
 	m := mail.NewMsg()
 
+	// невалидный адрес не исправится повтором
 	if err := m.From(fmt.Sprintf("%s <%s>", s.config.FromName, s.config.FromEmail)); err != nil {
-		return fmt.Errorf("failed to set from address: %w", err)
+		return nil, fmt.Errorf("%w: failed to set from address: %w", domain.ErrPermanent, err)
 	}
 
 	if err := m.To(n.Target); err != nil {
-		return fmt.Errorf("failed to set to address: %w", err)
+		return nil, fmt.Errorf("%w: failed to set to address: %w", domain.ErrPermanent, err)
 	}
 
-	m.Subject("Delayed Notification")
-	m.SetBodyString(mail.TypeTextPlain, string(n.Payload))
+	if err := s.compose(ctx, m, n); err != nil {
+		return nil, err
+	}
+	if s.unsubscribe.Enabled() {
+		setUnsubscribe(m, s.unsubscribe.URL(n))
+	}
+	// Message-ID генерируем сами, чтобы сохранить его в истории попыток
+	m.SetMessageID()
 
-	client, err := mail.NewClient(
-		s.config.SMTPHost,
-		mail.WithPort(s.config.SMTPPort),
-		mail.WithSMTPAuth(mail.SMTPAuthPlain),
-		mail.WithUsername(s.config.SMTPUsername),
-		mail.WithPassword(s.config.SMTPPassword),
-		mail.WithTLSPolicy(mail.TLSMandatory),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create mail client: %w", err)
+	if s.pool == nil {
+		return nil, fmt.Errorf("failed to create mail client: %w", s.poolErr)
 	}
 
-	sendCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	// ожидание свободной сессии и подключение ограничены, сама отправка - таймаутом сессии
+	sendCtx, cancel := context.WithTimeout(ctx, smtpTimeout)
 	defer cancel()
 
-	if err := client.DialAndSendWithContext(sendCtx, m); err != nil {
+	if err := s.pool.Send(sendCtx, m); err != nil {
 		s.log.Error().
 			Err(err).
 			Str("target", n.Target).
 			Msg("failed to send email")
-		return fmt.Errorf("failed to send email: %w", err)
+		return nil, classifySMTPError(err)
 	}
 
 	s.log.Info().
 		Str("target", n.Target).
 		Msg("email sent successfully")
+	return &domain.Delivery{MessageID: m.GetMessageID()}, nil
+}
+
+// classifySMTPError помечает постоянными ошибки с кодом ответа 5xx
+// (нет такого ящика, отказ в приеме). 4xx и сетевые ошибки остаются временными.
+func classifySMTPError(err error) error {
+	if code := smtpReplyCode(err); code >= 500 && code < 600 {
+		return fmt.Errorf("%w: smtp replied %d: %w", domain.ErrPermanent, code, err)
+	}
+	return fmt.Errorf("failed to send email: %w", err)
+}
+
+func smtpReplyCode(err error) int {
+	var sendErr *mail.SendError
+	if errors.As(err, &sendErr) && sendErr.ErrorCode() != 0 {
+		return sendErr.ErrorCode()
+	}
+	// ошибки диалога (например, AUTH) приходят без обертки SendError
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code
+	}
+	return 0
+}
+
+// compose собирает письмо из структурированного payload, а остальное - через setContent.
+// Payload проверяется при создании notify, ошибка здесь возможна только у notify,
+// созданных до появления проверки, и повтором не исправится
+func (s *EmailSender) compose(ctx context.Context, m *mail.Msg, n *domain.Notify) error {
+	p, ok, err := domain.ParseEmailPayload(n.Payload)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrPermanent, err)
+	}
+	if !ok {
+		setContent(m, n)
+		return nil
+	}
+
+	if err := setEmailPayload(m, p); err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrPermanent, err)
+	}
+	return s.attach(ctx, m, p.Attachments)
+}
+
+// setContent заполняет тему и тело письма: отрендеренный шаблон или payload как есть.
+// Если в шаблоне есть и текст, и HTML, письмо уходит как multipart/alternative.
+func setContent(m *mail.Msg, n *domain.Notify) {
+	if n.Message == nil {
+		m.Subject(defaultEmailSubject)
+		m.SetBodyString(mail.TypeTextPlain, string(n.Payload))
+		return
+	}
+	setBody(m, n.Message.Subject, n.Message.Text, n.Message.HTML)
+}
+
+// setEmailPayload заполняет все, кроме вложений: тему, тело, копии, адрес ответа и заголовки
+func setEmailPayload(m *mail.Msg, p *domain.EmailPayload) error {
+	setBody(m, p.Subject, p.Text, p.HTML)
+
+	if len(p.CC) > 0 {
+		if err := m.Cc(p.CC...); err != nil {
+			return fmt.Errorf("failed to set cc addresses: %w", err)
+		}
+	}
+	if len(p.BCC) > 0 {
+		if err := m.Bcc(p.BCC...); err != nil {
+			return fmt.Errorf("failed to set bcc addresses: %w", err)
+		}
+	}
+	if p.ReplyTo != "" {
+		if err := m.ReplyTo(p.ReplyTo); err != nil {
+			return fmt.Errorf("failed to set reply-to address: %w", err)
+		}
+	}
+	for name, value := range p.Headers {
+		m.SetGenHeader(mail.Header(textproto.CanonicalMIMEHeaderKey(name)), value)
+	}
 	return nil
 }
+
+func setBody(m *mail.Msg, subject, text, html string) {
+	if subject == "" {
+		subject = defaultEmailSubject
+	}
+	m.Subject(subject)
+
+	switch {
+	case text != "" && html != "":
+		m.SetBodyString(mail.TypeTextPlain, text)
+		m.AddAlternativeString(mail.TypeTextHTML, html)
+	case html != "":
+		m.SetBodyString(mail.TypeTextHTML, html)
+	default:
+		m.SetBodyString(mail.TypeTextPlain, text)
+	}
+}
+
+// attach добавляет вложения. Вложения по ссылке скачиваются при каждой попытке отправки:
+// сервис не хранит их содержимое, и ссылка должна быть действительна до отправки
+func (s *EmailSender) attach(ctx context.Context, m *mail.Msg, attachments []domain.EmailAttachment) error {
+	budget := domain.MaxEmailAttachmentsSize
+	for _, a := range attachments {
+		contentType := a.ContentType
+		var data []byte
+		var err error
+		if a.URL != "" {
+			var fetchedType string
+			data, fetchedType, err = s.fetchAttachment(ctx, a, budget)
+			if contentType == "" {
+				contentType = fetchedType
+			}
+		} else {
+			data, err = a.Data()
+			if err != nil {
+				err = fmt.Errorf("%w: %w", domain.ErrPermanent, err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		budget -= len(data)
+		if budget < 0 {
+			return fmt.Errorf("%w: attachments larger than %d bytes", domain.ErrPermanent, domain.MaxEmailAttachmentsSize)
+		}
+
+		var opts []mail.FileOption
+		if contentType != "" {
+			opts = append(opts, mail.WithFileContentType(mail.ContentType(contentType)))
+		}
+		if err := m.AttachReader(a.Filename, bytes.NewReader(data), opts...); err != nil {
+			return fmt.Errorf("failed to attach %s: %w", a.Filename, err)
+		}
+	}
+	return nil
+}
+
+// errAttachmentAddress - ссылка на вложение ведет на внутренний адрес
+var errAttachmentAddress = errors.New("attachment url resolves to a non-public address")
+
+// newAttachmentClient создает клиент для скачивания вложений. Ссылки задает клиент API,
+// поэтому адрес проверяется при подключении, уже после разрешения имени в DNS:
+// так к внутренним адресам не ведут ни имена, ни редиректы. Прокси из окружения
+// не используется - иначе проверялся бы адрес прокси, а не хранилища
+func newAttachmentClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: defaultAttachmentTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !domain.PublicIP(ip) {
+				return fmt.Errorf("%w: %s", errAttachmentAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   defaultAttachmentTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxAttachmentRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxAttachmentRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect to %s", errAttachmentAddress, req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// fetchAttachment скачивает вложение не больше limit байт. Ответ 4xx значит, что ссылка
+// недействительна (истекла подпись, объект удален) - повтор не поможет
+func (s *EmailSender) fetchAttachment(ctx context.Context, a domain.EmailAttachment, limit int) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.URL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: failed to create attachment request: %w", domain.ErrPermanent, err)
+	}
+
+	resp, err := s.client.Do(req)
+	if errors.Is(err, errAttachmentAddress) {
+		return nil, "", fmt.Errorf("%w: %w", domain.ErrPermanent, err)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch attachment %s: %w", a.Filename, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return nil, "", fmt.Errorf("%w: attachment %s url returned %d", domain.ErrPermanent, a.Filename, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("attachment %s url returned %d", a.Filename, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read attachment %s: %w", a.Filename, err)
+	}
+	if len(data) > limit {
+		return nil, "", fmt.Errorf("%w: attachments larger than %d bytes", domain.ErrPermanent, domain.MaxEmailAttachmentsSize)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// setUnsubscribe добавляет ссылку отписки в один клик (RFC 8058): почтовый клиент
+// отправляет на нее POST с телом List-Unsubscribe=One-Click
+func setUnsubscribe(m *mail.Msg, url string) {
+	m.SetGenHeader(mail.HeaderListUnsubscribe, "<"+url+">")
+	m.SetGenHeader(mail.HeaderListUnsubscribePost, "List-Unsubscribe=One-Click")
+}
@@ -1,12 +1,20 @@
 package sender
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
 	"strings"
 	"testing"
 
 	"github.com/adexcell/delayed-notifier/internal/domain"
 	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/wneessen/go-mail"
 )
 
 func TestEmailSender_Send_InvalidAddress(t *testing.T) {
@@ -15,12 +23,12 @@ func TestEmailSender_Send_InvalidAddress(t *testing.T) {
 		FromName:  "Notifier",
 	}
 
-	sender := NewEmailSender(cfg, log.New())
+	sender := NewEmailSender(cfg, domain.UnsubscribeConfig{}, log.New())
 	notify := &domain.Notify{
 		Target: "recipient@example.com",
 	}
 
-	err := sender.Send(context.Background(), notify)
+	_, err := sender.Send(context.Background(), notify)
 
 	// Ожидаем ошибку валидации адреса (go-mail проверяет RFC 5322)
 	if err == nil {
@@ -38,12 +46,12 @@ func TestEmailSender_Send_InvalidTarget(t *testing.T) {
 		FromName:  "Notifier",
 	}
 
-	sender := NewEmailSender(cfg, log.New())
+	sender := NewEmailSender(cfg, domain.UnsubscribeConfig{}, log.New())
 	notify := &domain.Notify{
 		Target: "invalid-target-email", // Невалидный targer
 	}
 
-	err := sender.Send(context.Background(), notify)
+	_, err := sender.Send(context.Background(), notify)
 
 	if err == nil {
 		t.Fatal("expected error for invalid target address, got nil")
@@ -52,4 +60,233 @@ func TestEmailSender_Send_InvalidTarget(t *testing.T) {
 	if !strings.Contains(err.Error(), "failed to set to address") {
 		t.Errorf("expected 'failed to set to address' error, got %v", err)
 	}
+
+	// повтор не исправит невалидный адрес
+	if !errors.Is(err, domain.ErrPermanent) {
+		t.Errorf("expected permanent error, got %v", err)
+	}
+}
+
+func TestSetContent_Template(t *testing.T) {
+	m := mail.NewMsg()
+	setContent(m, &domain.Notify{
+		Payload: []byte(`{"template": "welcome"}`),
+		Message: &domain.Message{
+			Subject: "Hello, Ivan",
+			Text:    "plain body",
+			HTML:    "<b>html body</b>",
+		},
+	})
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write message: %v", err)
+	}
+	raw := buf.String()
+
+	for _, want := range []string{"Subject: Hello, Ivan", "multipart/alternative", "plain body", "<b>html body</b>"} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("expected message to contain %q", want)
+		}
+	}
+}
+
+func TestSetContent_RawPayload(t *testing.T) {
+	m := mail.NewMsg()
+	setContent(m, &domain.Notify{Payload: []byte("raw text")})
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write message: %v", err)
+	}
+	raw := buf.String()
+
+	if !strings.Contains(raw, "Subject: "+defaultEmailSubject) {
+		t.Errorf("expected default subject, got %s", raw)
+	}
+	if !strings.Contains(raw, "raw text") {
+		t.Errorf("expected raw payload in body, got %s", raw)
+	}
+}
+
+func TestEmailSender_Compose_Payload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/report.csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("id,amount\n1,100\n"))
+	}))
+	defer srv.Close()
+
+	sender := NewEmailSender(EmailConfig{}, domain.UnsubscribeConfig{}, log.New()).(*EmailSender)
+	// тестовый сервер слушает loopback, который клиент по умолчанию не пускает, а ссылка
+	// с IP loopback не пройдет проверку payload: подключаемся к нему по публичному имени
+	sender.client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	}}
+	storage := "http://storage.example.com"
+	n := &domain.Notify{Payload: []byte(`{
+		"subject": "Monthly report",
+		"text": "plain body",
+		"html": "<b>html body</b>",
+		"cc": ["boss@example.com"],
+		"bcc": ["audit@example.com"],
+		"reply_to": "support@example.com",
+		"headers": {"x-campaign": "report-2026"},
+		"attachments": [
+			{"filename": "hello.txt", "content_type": "text/plain", "content": "aGVsbG8gYXR0YWNobWVudA=="},
+			{"filename": "report.csv", "url": "` + storage + `/report.csv"}
+		]
+	}`)}
+
+	m := mail.NewMsg()
+	if err := sender.compose(context.Background(), m, n); err != nil {
+		t.Fatalf("compose: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write message: %v", err)
+	}
+	raw := buf.String()
+
+	for _, want := range []string{
+		"Subject: Monthly report", "Cc: <boss@example.com>", "Reply-To: <support@example.com>",
+		"X-Campaign: report-2026", "multipart/mixed", "multipart/alternative", "plain body", "<b>html body</b>",
+		`filename="hello.txt"`, "aGVsbG8gYXR0YWNobWVudA==", `filename="report.csv"`, "Content-Type: text/csv",
+	} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("expected message to contain %q, got %s", want, raw)
+		}
+	}
+	// Bcc не попадает в заголовки письма, только в конверт SMTP
+	if strings.Contains(raw, "audit@example.com") {
+		t.Errorf("expected bcc to be hidden, got %s", raw)
+	}
+	if rcpts, _ := m.GetRecipients(); len(rcpts) != 2 {
+		t.Errorf("expected cc and bcc in envelope, got %v", rcpts)
+	}
+
+	// Act: ссылка на удаленный объект
+	missing := &domain.Notify{Payload: []byte(`{"text": "hi", "attachments": [{"filename": "gone.pdf", "url": "` + storage + `/gone.pdf"}]}`)}
+	err := sender.compose(context.Background(), mail.NewMsg(), missing)
+
+	// Assert: повтор не вернет объект
+	if !errors.Is(err, domain.ErrPermanent) {
+		t.Errorf("expected permanent error for missing attachment, got %v", err)
+	}
+}
+
+func TestEmailSender_FetchAttachment_NonPublicAddress(t *testing.T) {
+	fetched := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = true
+		w.Write([]byte("secret"))
+	}))
+	defer internal.Close()
+	// редирект ведет на тот же loopback: проверяется каждый адрес подключения
+	redirect := httptest.NewServer(http.RedirectHandler(internal.URL+"/meta-data", http.StatusFound))
+	defer redirect.Close()
+
+	sender := NewEmailSender(EmailConfig{}, domain.UnsubscribeConfig{}, log.New()).(*EmailSender)
+
+	for _, url := range []string{internal.URL + "/meta-data", redirect.URL} {
+		// Act
+		_, _, err := sender.fetchAttachment(context.Background(), domain.EmailAttachment{Filename: "a.txt", URL: url}, 1024)
+
+		// Assert
+		if !errors.Is(err, domain.ErrPermanent) {
+			t.Errorf("expected permanent error for %s, got %v", url, err)
+		}
+	}
+	if fetched {
+		t.Error("expected internal address not to be requested")
+	}
+}
+
+func TestEmailSender_Compose_LegacyJSONPayload(t *testing.T) {
+	sender := NewEmailSender(EmailConfig{}, domain.UnsubscribeConfig{}, log.New()).(*EmailSender)
+
+	for _, payload := range []string{`{"message": "legacy body"}`, `{"subject": "legacy body"}`, `{"note": "legacy body", "event": 42}`} {
+		m := mail.NewMsg()
+
+		// Act
+		if err := sender.compose(context.Background(), m, &domain.Notify{Payload: []byte(payload)}); err != nil {
+			t.Fatalf("compose %s: %v", payload, err)
+		}
+
+		// Assert: объект вне схемы уходит телом письма как есть
+		var buf bytes.Buffer
+		if _, err := m.WriteTo(&buf); err != nil {
+			t.Fatalf("failed to write message: %v", err)
+		}
+		raw := buf.String()
+		if !strings.Contains(raw, "Subject: "+defaultEmailSubject) {
+			t.Errorf("expected default subject for %s, got %s", payload, raw)
+		}
+		if !strings.Contains(raw, "legacy body") {
+			t.Errorf("expected raw payload %s in body, got %s", payload, raw)
+		}
+	}
+}
+
+func TestSetUnsubscribe(t *testing.T) {
+	cfg := domain.UnsubscribeConfig{BaseURL: "https://notifier.example.com/", Secret: "secret"}
+	n := &domain.Notify{TenantID: "team-a", Channel: "email", Target: "User@Example.com", Category: "marketing"}
+
+	m := mail.NewMsg()
+	setContent(m, n)
+	setUnsubscribe(m, cfg.URL(n))
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatalf("failed to write message: %v", err)
+	}
+	// длинный заголовок переносится на следующую строку (folding), склеиваем обратно
+	raw := strings.ReplaceAll(buf.String(), "\r\n ", " ")
+
+	if !strings.Contains(raw, "List-Unsubscribe-Post: List-Unsubscribe=One-Click") {
+		t.Errorf("expected one-click header, got %s", raw)
+	}
+	_, rest, ok := strings.Cut(raw, "List-Unsubscribe: <https://notifier.example.com/unsubscribe/")
+	if !ok {
+		t.Fatalf("expected unsubscribe link, got %s", raw)
+	}
+	token, _, _ := strings.Cut(rest, ">")
+
+	// ссылка ведет на отписку этого адресата от категории notify
+	s, err := cfg.ParseToken(token)
+	if err != nil {
+		t.Fatalf("failed to parse token %q: %v", token, err)
+	}
+	if s.TenantID != "team-a" || s.Target != "user@example.com" || s.Category != "marketing" || s.Reason != domain.ReasonUnsubscribe {
+		t.Errorf("unexpected suppression from token: %+v", s)
+	}
+	if _, err := (domain.UnsubscribeConfig{Secret: "other"}).ParseToken(token); !errors.Is(err, domain.ErrInvalidUnsubscribeToken) {
+		t.Errorf("expected token signed with other secret to be rejected, got %v", err)
+	}
+}
+
+func TestClassifySMTPError(t *testing.T) {
+	cases := []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{"mailbox unavailable", &textproto.Error{Code: 550, Msg: "5.1.1 no such user"}, true},
+		{"auth failed", fmt.Errorf("dial: %w", &textproto.Error{Code: 535, Msg: "authentication failed"}), true},
+		{"greylisting", &textproto.Error{Code: 451, Msg: "try again later"}, false},
+		{"network", errors.New("dial tcp: connection refused"), false},
+	}
+
+	for _, tc := range cases {
+		err := classifySMTPError(tc.err)
+		if got := errors.Is(err, domain.ErrPermanent); got != tc.permanent {
+			t.Errorf("%s: expected permanent=%v, got %v (%v)", tc.name, tc.permanent, got, err)
+		}
+	}
 }
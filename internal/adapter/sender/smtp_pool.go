@@ -0,0 +1,168 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wneessen/go-mail"
+	"github.com/wneessen/go-mail/smtp"
+)
+
+const (
+	defaultSMTPPoolSize    = 5
+	defaultSMTPIdleTimeout = 30 * time.Second
+)
+
+var errSMTPPoolClosed = errors.New("smtp pool is closed")
+
+// smtpPool - пул SMTP-сессий, общий для воркеров. Сессия (TCP, TLS, AUTH) открывается
+// один раз и отправляет много писем подряд. Открытых сессий не больше size: остальные
+// отправки ждут свободную. Сессия, простоявшая дольше idleTimeout, закрывается при следующем
+// обращении - сервер, скорее всего, уже разорвал ее сам
+type smtpPool struct {
+	// client хранит только настройки подключения, сессии - отдельные smtp.Client
+	client      *mail.Client
+	idleTimeout time.Duration
+
+	idle  chan *smtpConn
+	slots chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+type smtpConn struct {
+	*smtp.Client
+	lastUsed time.Time
+}
+
+func newSMTPPool(client *mail.Client, size int, idleTimeout time.Duration) *smtpPool {
+	if size <= 0 {
+		size = defaultSMTPPoolSize
+	}
+	if idleTimeout <= 0 {
+		idleTimeout = defaultSMTPIdleTimeout
+	}
+	return &smtpPool{
+		client:      client,
+		idleTimeout: idleTimeout,
+		idle:        make(chan *smtpConn, size),
+		slots:       make(chan struct{}, size),
+	}
+}
+
+// Send отправляет письмо через свободную сессию. Перед отправкой go-mail проверяет сессию
+// командой NOOP: если сервер ее закрыл, письмо еще не отправлено, и оно повторяется один раз
+// через новую сессию
+func (p *smtpPool) Send(ctx context.Context, m *mail.Msg) error {
+	for attempt := 0; ; attempt++ {
+		conn, err := p.get(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = p.client.SendWithSMTPClient(conn.Client, m)
+		// письмо принято, но сессия сломалась после (например, на RSET)
+		if m.IsDelivered() {
+			p.put(conn, err == nil)
+			return nil
+		}
+		p.put(conn, err == nil || sessionUsable(err))
+
+		var sendErr *mail.SendError
+		if attempt == 0 && errors.As(err, &sendErr) && sendErr.Reason == mail.ErrConnCheck {
+			continue
+		}
+		return err
+	}
+}
+
+// sessionUsable - письмо отклонено сервером, а go-mail сбросил транзакцию командой RSET:
+// сессию можно использовать для следующих писем
+func sessionUsable(err error) bool {
+	var sendErr *mail.SendError
+	if !errors.As(err, &sendErr) {
+		return false
+	}
+	return sendErr.Reason == mail.ErrSMTPMailFrom || sendErr.Reason == mail.ErrSMTPRcptTo
+}
+
+// get возвращает свободную сессию, а если ее нет и лимит не исчерпан - открывает новую
+func (p *smtpPool) get(ctx context.Context) (*smtpConn, error) {
+	for {
+		var conn *smtpConn
+		select {
+		case conn = <-p.idle:
+		default:
+			select {
+			case conn = <-p.idle:
+			case p.slots <- struct{}{}:
+				return p.dial(ctx)
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if time.Since(conn.lastUsed) < p.idleTimeout {
+			return conn, nil
+		}
+		p.put(conn, false)
+	}
+}
+
+func (p *smtpPool) dial(ctx context.Context) (*smtpConn, error) {
+	if p.isClosed() {
+		<-p.slots
+		return nil, errSMTPPoolClosed
+	}
+
+	client, err := p.client.DialToSMTPClientWithContext(ctx)
+	if err != nil {
+		<-p.slots
+		return nil, fmt.Errorf("failed to dial smtp: %w", err)
+	}
+	return &smtpConn{Client: client, lastUsed: time.Now()}, nil
+}
+
+// put возвращает сессию в пул или закрывает ее и освобождает место под новую
+func (p *smtpPool) put(conn *smtpConn, reuse bool) {
+	// под блокировкой, чтобы не вернуть сессию в уже закрытый пул;
+	// в idle помещаются все сессии, поэтому запись не блокируется
+	p.mu.Lock()
+	if reuse && !p.closed {
+		conn.lastUsed = time.Now()
+		p.idle <- conn
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	// сессия могла быть уже разорвана сервером, ошибка QUIT ничего не меняет
+	_ = p.client.CloseWithSMTPClient(conn.Client)
+	<-p.slots
+}
+
+// Close закрывает свободные сессии. Занятые закроются, когда отправка вернет их в пул
+func (p *smtpPool) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	for {
+		select {
+		case conn := <-p.idle:
+			p.put(conn, false)
+		default:
+			return nil
+		}
+	}
+}
+
+func (p *smtpPool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
@@ -0,0 +1,245 @@
+package sender
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/wneessen/go-mail"
+)
+
+// fakeSMTP - SMTP-сервер без TLS и AUTH: считает сессии и принятые письма,
+// на RCPT с адресом rejected@... отвечает 550
+type fakeSMTP struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	sessions int
+	messages int
+	open     map[net.Conn]bool
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{ln: ln, open: make(map[net.Conn]bool)}
+	go s.accept()
+	t.Cleanup(func() {
+		ln.Close()
+		s.dropAll()
+	})
+	return s
+}
+
+func (s *fakeSMTP) accept() {
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.sessions++
+		s.open[c] = true
+		s.mu.Unlock()
+		go s.serve(c)
+	}
+}
+
+func (s *fakeSMTP) serve(c net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.open, c)
+		s.mu.Unlock()
+		c.Close()
+	}()
+
+	r := bufio.NewReader(c)
+	reply := func(line string) { fmt.Fprintf(c, "%s\r\n", line) }
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-fake")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "RCPT") && strings.Contains(cmd, "REJECTED@"):
+			reply("550 5.1.1 no such user")
+		case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"),
+			strings.HasPrefix(cmd, "RSET"), strings.HasPrefix(cmd, "NOOP"):
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end with .")
+			for {
+				data, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if data == ".\r\n" {
+					break
+				}
+			}
+			s.mu.Lock()
+			s.messages++
+			s.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+// dropAll разрывает все сессии, как сервер по своему таймауту простоя
+func (s *fakeSMTP) dropAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.open {
+		c.Close()
+	}
+}
+
+func (s *fakeSMTP) stats() (sessions, messages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions, s.messages
+}
+
+func newTestEmailSender(t *testing.T, srv *fakeSMTP, cfg EmailConfig) *EmailSender {
+	t.Helper()
+	addr := srv.ln.Addr().(*net.TCPAddr)
+	cfg.SMTPHost = addr.IP.String()
+	cfg.SMTPPort = addr.Port
+	cfg.FromEmail = "notifier@example.com"
+	cfg.FromName = "Notifier"
+
+	s := newEmailSender(cfg, domain.UnsubscribeConfig{}, log.New(), mail.WithTLSPolicy(mail.NoTLS))
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func sendTestEmail(s *EmailSender, target string) error {
+	_, err := s.Send(context.Background(), &domain.Notify{Target: target, Payload: []byte("hello")})
+	return err
+}
+
+func TestEmailSender_Pool_ReusesSession(t *testing.T) {
+	srv := newFakeSMTP(t)
+	s := newTestEmailSender(t, srv, EmailConfig{})
+
+	// Act
+	for i := 0; i < 5; i++ {
+		if err := sendTestEmail(s, "user@example.com"); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+
+	// Assert: одно подключение на все письма
+	if sessions, messages := srv.stats(); sessions != 1 || messages != 5 {
+		t.Errorf("expected 5 messages over 1 session, got %d over %d", messages, sessions)
+	}
+}
+
+func TestEmailSender_Pool_Concurrent(t *testing.T) {
+	srv := newFakeSMTP(t)
+	s := newTestEmailSender(t, srv, EmailConfig{PoolSize: 2})
+
+	// Act
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- sendTestEmail(s, "user@example.com")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert: воркеры делят не больше pool_size сессий
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if sessions, messages := srv.stats(); sessions > 2 || messages != 20 {
+		t.Errorf("expected 20 messages over at most 2 sessions, got %d over %d", messages, sessions)
+	}
+}
+
+func TestEmailSender_Pool_Reconnect(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    EmailConfig
+		expire func(srv *fakeSMTP)
+	}{
+		{
+			name:   "server disconnect",
+			cfg:    EmailConfig{},
+			expire: func(srv *fakeSMTP) { srv.dropAll() },
+		},
+		{
+			name:   "idle timeout",
+			cfg:    EmailConfig{IdleTimeout: 20 * time.Millisecond},
+			expire: func(*fakeSMTP) { time.Sleep(50 * time.Millisecond) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeSMTP(t)
+			s := newTestEmailSender(t, srv, tt.cfg)
+			if err := sendTestEmail(s, "user@example.com"); err != nil {
+				t.Fatalf("first send: %v", err)
+			}
+			tt.expire(srv)
+
+			// Act
+			err := sendTestEmail(s, "user@example.com")
+
+			// Assert: письмо уходит через новую сессию, а не возвращается ошибкой
+			if err != nil {
+				t.Fatalf("send after expired session: %v", err)
+			}
+			if sessions, messages := srv.stats(); sessions != 2 || messages != 2 {
+				t.Errorf("expected 2 messages over 2 sessions, got %d over %d", messages, sessions)
+			}
+		})
+	}
+}
+
+func TestEmailSender_Pool_RejectedKeepsSession(t *testing.T) {
+	srv := newFakeSMTP(t)
+	s := newTestEmailSender(t, srv, EmailConfig{})
+
+	// Act
+	rejected := sendTestEmail(s, "rejected@example.com")
+	accepted := sendTestEmail(s, "user@example.com")
+
+	// Assert: отказ по адресу постоянный, но сессия остается рабочей
+	if !errors.Is(rejected, domain.ErrPermanent) {
+		t.Errorf("expected permanent error for rejected recipient, got %v", rejected)
+	}
+	if accepted != nil {
+		t.Fatalf("send after rejection: %v", accepted)
+	}
+	if sessions, messages := srv.stats(); sessions != 1 || messages != 1 {
+		t.Errorf("expected 1 message over 1 session, got %d over %d", messages, sessions)
+	}
+}
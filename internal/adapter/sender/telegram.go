@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/internal/domain"
@@ -16,38 +18,53 @@ type TelegramConfig struct {
 	Token string
 }
 
+const telegramAPIURL = "https://api.telegram.org"
+
 type TelegramSender struct {
 	token  string
+	apiURL string
 	log    log.Log
 	client *http.Client
 }
 
 func NewTelegramSender(token string, log log.Log) domain.Sender {
 	return &TelegramSender{
-		token: token,
-		log:   log,
+		token:  token,
+		apiURL: telegramAPIURL,
+		log:    log,
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 	}
 }
 
+// tgResponse - ответ Bot API, для ошибок заполнены description и parameters.retry_after
+type tgResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+	Result struct {
+		MessageID int64 `json:"message_id"`
+	} `json:"result"`
+}
+
 type tgMessage struct {
-	ChatID string `json:"chat_id"`
-	Text   string `json:"text"`
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
 }
 
-func (s *TelegramSender) Send(ctx context.Context, n *domain.Notify) error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.token)
+func (s *TelegramSender) Send(ctx context.Context, n *domain.Notify) (*domain.Delivery, error) {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", s.apiURL, s.token)
 
-	msg := tgMessage{
-		ChatID: n.Target,
-		Text:   string(n.Payload),
-	}
+	msg := toTgMessage(n)
 
 	body, err := json.Marshal(msg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal tg message: %w", err)
+		return nil, fmt.Errorf("failed to marshal tg message: %w", err)
 	}
 
 	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -55,20 +72,71 @@ func (s *TelegramSender) Send(ctx context.Context, n *domain.Notify) error {
 
 	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("telegram api request failed: %w", err)
+		return nil, fmt.Errorf("telegram api request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("telegram api returned non-200 status: %d", resp.StatusCode)
+		return nil, classifyTelegramError(resp)
+	}
+
+	var result tgResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&result); err != nil {
+		// сообщение уже доставлено, нечитаемый ответ не повод для повтора
+		s.log.Warn().Err(err).Str("target", n.Target).Msg("[TELEGRAM] failed to decode response")
 	}
 
 	s.log.Info().Str("target", n.Target).Msg("[TELEGRAM] Message sent successfully")
-	return nil
+	return &domain.Delivery{MessageID: strconv.FormatInt(result.Result.MessageID, 10)}, nil
+}
+
+// toTgMessage берет текст отрендеренного шаблона, а если его нет - HTML-вариант
+// с parse_mode=HTML. Без шаблона отправляется payload как есть.
+func toTgMessage(n *domain.Notify) tgMessage {
+	msg := tgMessage{
+		ChatID: n.Target,
+		Text:   string(n.Payload),
+	}
+	if n.Message == nil {
+		return msg
+	}
+
+	msg.Text = n.Message.Text
+	if msg.Text == "" && n.Message.HTML != "" {
+		msg.Text = n.Message.HTML
+		msg.ParseMode = "HTML"
+	}
+	return msg
+}
+
+// classifyTelegramError: 400 и 403 (chat not found, bot was blocked) - постоянные ошибки
+// адресата, 429 - временная с retry_after. 401 и 404 значат неверный или отозванный токен
+// бота - это ошибка настройки, а не адресата, поэтому они, как и 5xx, временные:
+// notify дождутся исправления токена, а не уйдут в Failed все разом.
+func classifyTelegramError(resp *http.Response) error {
+	var body tgResponse
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&body)
+
+	err := fmt.Errorf("telegram api returned status %d: %s", resp.StatusCode, body.Description)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if body.Parameters.RetryAfter > 0 {
+			return &domain.RetryAfterError{
+				After: time.Duration(body.Parameters.RetryAfter) * time.Second,
+				Err:   err,
+			}
+		}
+		return err
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: %w", domain.ErrPermanent, err)
+	default:
+		return err
+	}
 }
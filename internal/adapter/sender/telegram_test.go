@@ -1,7 +1,12 @@
 package sender
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/adexcell/delayed-notifier/internal/domain"
 	"github.com/adexcell/delayed-notifier/pkg/log"
@@ -38,3 +43,65 @@ func TestTelegramSender_Send_Validation(t *testing.T) {
 	_ = s
 	_ = notify
 }
+
+func newTestTelegramSender(url string) *TelegramSender {
+	s := NewTelegramSender("123:test-token", log.New()).(*TelegramSender)
+	s.apiURL = url
+	return s
+}
+
+func TestTelegramSender_Send_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bot123:test-token/sendMessage" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"ok": true, "result": {"message_id": 1337}}`))
+	}))
+	defer srv.Close()
+
+	delivery, err := newTestTelegramSender(srv.URL).Send(context.Background(), &domain.Notify{Target: "42", Payload: []byte("hi")})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if delivery == nil || delivery.MessageID != "1337" {
+		t.Errorf("expected message id 1337, got %+v", delivery)
+	}
+}
+
+func TestTelegramSender_Send_ErrorClassification(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     int
+		body       string
+		permanent  bool
+		retryAfter time.Duration
+	}{
+		{"chat not found", http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`, true, 0},
+		{"bot blocked", http.StatusForbidden, `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`, true, 0},
+		{"flood control", http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":17}}`, false, 17 * time.Second},
+		{"invalid token", http.StatusUnauthorized, `{"ok":false,"error_code":401,"description":"Unauthorized"}`, false, 0},
+		{"revoked token", http.StatusNotFound, `{"ok":false,"error_code":404,"description":"Not Found"}`, false, 0},
+		{"server error", http.StatusBadGateway, `{"ok":false,"error_code":502,"description":"Bad Gateway"}`, false, 0},
+	}
+
+	for _, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			w.Write([]byte(tc.body))
+		}))
+
+		_, err := newTestTelegramSender(srv.URL).Send(context.Background(), &domain.Notify{Target: "42", Payload: []byte("hi")})
+		srv.Close()
+
+		if err == nil {
+			t.Errorf("%s: expected error", tc.name)
+			continue
+		}
+		if got := errors.Is(err, domain.ErrPermanent); got != tc.permanent {
+			t.Errorf("%s: expected permanent=%v, got %v (%v)", tc.name, tc.permanent, got, err)
+		}
+		if after, _ := domain.RetryAfter(err); after != tc.retryAfter {
+			t.Errorf("%s: expected retry after %v, got %v", tc.name, tc.retryAfter, after)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+const (
+	WebhookSignatureHeader = "X-Notify-Signature"
+	WebhookTimestampHeader = "X-Notify-Timestamp"
+	WebhookIDHeader        = "X-Notify-ID"
+	// WebhookMessageIDHeader - заголовок ответа, в котором получатель может вернуть свой ID сообщения
+	WebhookMessageIDHeader = "X-Message-ID"
+
+	defaultWebhookTimeout  = 10 * time.Second
+	maxWebhookResponseBody = 512
+)
+
+type WebhookConfig struct {
+	Secret  string            `mapstructure:"secret"`
+	Headers map[string]string `mapstructure:"headers"`
+	Timeout time.Duration     `mapstructure:"timeout"`
+}
+
+type WebhookSender struct {
+	config WebhookConfig
+	log    log.Log
+	client *http.Client
+}
+
+func NewWebhookSender(config WebhookConfig, log log.Log) domain.Sender {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &WebhookSender{
+		config: config,
+		log:    log,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Send отправляет POST на URL из n.Target. Тело подписывается HMAC-SHA256
+// от "<timestamp>.<body>", чтобы получатель мог проверить подлинность и отбросить повторы.
+// Ответы 4xx (кроме 408 и 429) считаются постоянной ошибкой, 5xx и сетевые - временной.
+func (s *WebhookSender) Send(ctx context.Context, n *domain.Notify) (*domain.Delivery, error) {
+	target, err := url.Parse(n.Target)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("%w: invalid webhook url %q", domain.ErrPermanent, n.Target)
+	}
+
+	body := n.Payload
+	if n.Message != nil {
+		body = []byte(n.Message.Text)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(WebhookIDHeader, n.ID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if s.config.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+Sign(s.config.Secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBody))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		s.log.Info().Str("target", n.Target).Msg("[WEBHOOK] Delivered successfully")
+		return &domain.Delivery{
+			MessageID: resp.Header.Get(WebhookMessageIDHeader),
+			Response:  fmt.Sprintf("%s: %s", resp.Status, respBody),
+		}, nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// Retry-After в секундах; формат HTTP-date встречается редко и не поддерживается
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			return nil, &domain.RetryAfterError{
+				After: time.Duration(secs) * time.Second,
+				Err:   fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, respBody),
+			}
+		}
+	}
+	if isPermanentStatus(resp.StatusCode) {
+		return nil, fmt.Errorf("%w: webhook returned status %d: %s", domain.ErrPermanent, resp.StatusCode, respBody)
+	}
+	return nil, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, respBody)
+}
+
+// Sign считает подпись тела webhook: hex(HMAC-SHA256(secret, timestamp + "." + body))
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func isPermanentStatus(code int) bool {
+	if code == http.StatusRequestTimeout || code == http.StatusTooManyRequests {
+		return false
+	}
+	return code >= 400 && code < 500
+}
@@ -0,0 +1,127 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+func TestWebhookSender_Send_Signed(t *testing.T) {
+	secret := "s3cret"
+	payload := []byte(`{"order_id": 42}`)
+
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		body, _ := io.ReadAll(r.Body)
+
+		if string(body) != string(payload) {
+			t.Errorf("unexpected body: %s", body)
+		}
+		if r.Header.Get("X-Custom") != "value" {
+			t.Errorf("expected configured header, got %q", r.Header.Get("X-Custom"))
+		}
+		if r.Header.Get(WebhookIDHeader) != "notify-1" {
+			t.Errorf("expected notify id header, got %q", r.Header.Get(WebhookIDHeader))
+		}
+
+		// получатель проверяет подпись по присланному timestamp
+		ts := r.Header.Get(WebhookTimestampHeader)
+		if want := "sha256=" + Sign(secret, ts, body); r.Header.Get(WebhookSignatureHeader) != want {
+			t.Errorf("signature mismatch: got %q, want %q", r.Header.Get(WebhookSignatureHeader), want)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSender(WebhookConfig{
+		Secret:  secret,
+		Headers: map[string]string{"X-Custom": "value"},
+	}, log.New())
+
+	_, err := s.Send(context.Background(), &domain.Notify{ID: "notify-1", Target: srv.URL, Payload: payload})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !called {
+		t.Fatal("expected webhook to be called")
+	}
+}
+
+func TestWebhookSender_Send_StatusClassification(t *testing.T) {
+	cases := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusNotFound, true},
+		{http.StatusRequestTimeout, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusBadGateway, false},
+	}
+
+	for _, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+		}))
+
+		s := NewWebhookSender(WebhookConfig{}, log.New())
+		_, err := s.Send(context.Background(), &domain.Notify{Target: srv.URL, Payload: []byte(`{}`)})
+		srv.Close()
+
+		if err == nil {
+			t.Errorf("status %d: expected error", tc.status)
+			continue
+		}
+		if got := errors.Is(err, domain.ErrPermanent); got != tc.permanent {
+			t.Errorf("status %d: expected permanent=%v, got %v (%v)", tc.status, tc.permanent, got, err)
+		}
+	}
+}
+
+func TestWebhookSender_Send_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSender(WebhookConfig{Timeout: 50 * time.Millisecond}, log.New())
+	_, err := s.Send(context.Background(), &domain.Notify{Target: srv.URL, Payload: []byte(`{}`)})
+
+	// таймаут - временная ошибка, отправку стоит повторить
+	if err == nil || errors.Is(err, domain.ErrPermanent) {
+		t.Errorf("expected transient error, got %v", err)
+	}
+}
+
+func TestWebhookSender_Send_InvalidURL(t *testing.T) {
+	s := NewWebhookSender(WebhookConfig{}, log.New())
+	_, err := s.Send(context.Background(), &domain.Notify{Target: "ftp://example.com", Payload: []byte(`{}`)})
+
+	if !errors.Is(err, domain.ErrPermanent) {
+		t.Errorf("expected permanent error, got %v", err)
+	}
+}
+
+func TestWebhookSender_Send_RetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSender(WebhookConfig{}, log.New())
+	_, err := s.Send(context.Background(), &domain.Notify{Target: srv.URL, Payload: []byte(`{}`)})
+
+	if after, ok := domain.RetryAfter(err); !ok || after != 2*time.Minute {
+		t.Errorf("expected retry after 2m, got %v (%v)", after, err)
+	}
+}
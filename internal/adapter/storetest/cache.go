@@ -0,0 +1,101 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+type NewNotifyRedis func(t *testing.T) domain.NotifyRedis
+
+func RunNotifyRedis(t *testing.T, newCache NewNotifyRedis) {
+	t.Run("SetAndGet", func(t *testing.T) { testCacheSetAndGet(t, newCache(t)) })
+	t.Run("UpdateStatus", func(t *testing.T) { testCacheUpdateStatus(t, newCache(t)) })
+	t.Run("Delete", func(t *testing.T) { testCacheDelete(t, newCache(t)) })
+}
+
+func testCacheSetAndGet(t *testing.T, r domain.NotifyRedis) {
+	ctx := context.Background()
+	n := newNotify(time.Now().Add(time.Hour))
+
+	// Act
+	if err := r.SetWithExpiration(ctx, n); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got, err := r.Get(ctx, n.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.ID != n.ID || string(got.Payload) != string(n.Payload) || got.Status != n.Status || !got.ScheduledAt.Equal(n.ScheduledAt) {
+		t.Errorf("cached notify mismatch: %+v", got)
+	}
+	if _, err := r.Get(ctx, domain.NewNotify().ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testCacheUpdateStatus(t *testing.T, r domain.NotifyRedis) {
+	ctx := context.Background()
+	n := newNotify(time.Now())
+	if err := r.SetWithExpiration(ctx, n); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	// Act
+	lastErr := "timeout"
+	next := truncate(time.Now().Add(time.Minute))
+	err := r.UpdateStatus(ctx, n.ID, domain.StatusPending, &next, 1, &lastErr)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err := r.Get(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.RetryCount != 1 || got.LastError == nil || *got.LastError != lastErr || !got.ScheduledAt.Equal(next) {
+		t.Errorf("update not applied: %+v", got)
+	}
+	if got.Version != n.Version+1 {
+		t.Errorf("expected version %d, got %d", n.Version+1, got.Version)
+	}
+	if string(got.Payload) != string(n.Payload) || got.Target != n.Target {
+		t.Errorf("expected other fields to be kept, got %+v", got)
+	}
+
+	// отсутствующий в кеше notify не создается
+	missing := domain.NewNotify().ID
+	if err := r.UpdateStatus(ctx, missing, domain.StatusSent, nil, 0, nil); err != nil {
+		t.Fatalf("update missing: %v", err)
+	}
+	if _, err := r.Get(ctx, missing); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for uncached notify, got %v", err)
+	}
+}
+
+func testCacheDelete(t *testing.T, r domain.NotifyRedis) {
+	ctx := context.Background()
+	n := newNotify(time.Now())
+	if err := r.SetWithExpiration(ctx, n); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	// Act
+	if err := r.Delete(ctx, n.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	// Assert
+	if _, err := r.Get(ctx, n.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := r.Delete(ctx, n.ID); err != nil {
+		t.Errorf("expected repeated delete to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,782 @@
+// Package storetest - контрактные тесты хранилищ notify. Их проходят и адаптеры
+// Postgres и Redis, и реализации в памяти.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// NewNotifyPostgres возвращает пустое хранилище: проверки опираются на то,
+// что в нем нет чужих notify
+type NewNotifyPostgres func(t *testing.T) domain.NotifyPostgres
+
+func RunNotifyPostgres(t *testing.T, newStore NewNotifyPostgres) {
+	t.Run("CreateAndGet", func(t *testing.T) { testCreateAndGet(t, newStore(t)) })
+	t.Run("IdempotencyKey", func(t *testing.T) { testIdempotencyKey(t, newStore(t)) })
+	t.Run("CreateBatch", func(t *testing.T) { testCreateBatch(t, newStore(t)) })
+	t.Run("CreateBatchAtomic", func(t *testing.T) { testCreateBatchAtomic(t, newStore(t)) })
+	t.Run("UpdateStatus", func(t *testing.T) { testUpdateStatus(t, newStore(t)) })
+	t.Run("Reschedule", func(t *testing.T) { testReschedule(t, newStore(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newStore(t)) })
+	t.Run("UpdateAfterLock", func(t *testing.T) { testUpdateAfterLock(t, newStore(t)) })
+	t.Run("Cancel", func(t *testing.T) { testCancel(t, newStore(t)) })
+	t.Run("CancelInProcess", func(t *testing.T) { testCancelInProcess(t, newStore(t)) })
+	t.Run("LockAndFetchReady", func(t *testing.T) { testLockAndFetchReady(t, newStore(t)) })
+	t.Run("LockAndFetchReadyConcurrent", func(t *testing.T) { testLockAndFetchReadyConcurrent(t, newStore(t)) })
+	t.Run("CountPending", func(t *testing.T) { testCountPending(t, newStore(t)) })
+	t.Run("ListPagination", func(t *testing.T) { testListPagination(t, newStore(t)) })
+	t.Run("DeadLettersAndRequeue", func(t *testing.T) { testDeadLettersAndRequeue(t, newStore(t)) })
+	t.Run("Fallback", func(t *testing.T) { testFallback(t, newStore(t)) })
+	t.Run("Attempts", func(t *testing.T) { testAttempts(t, newStore(t)) })
+	t.Run("TenantIsolation", func(t *testing.T) { testTenantIsolation(t, newStore(t)) })
+}
+
+func testCreateAndGet(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now().Add(time.Hour))
+	n.RetryPolicy = &domain.RetryPolicy{Kind: domain.RetryFixed, MaxAttempts: 3, Delay: time.Minute}
+	n.DeliveryWindow = &domain.DeliveryWindow{Timezone: "Europe/Moscow", Start: 9 * 60, End: 21 * 60, Weekdays: []time.Weekday{time.Monday}}
+	n.Category = "marketing"
+
+	// Act
+	mustCreate(t, p, n)
+	got, err := p.GetNotifyByID(ctx, n.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if string(got.Payload) != string(n.Payload) || got.Target != n.Target || got.Channel != n.Channel {
+		t.Errorf("notify mismatch: got %+v", got)
+	}
+	if got.Status != domain.StatusPending || got.RetryCount != 0 || got.LastError != nil {
+		t.Errorf("expected fresh pending notify, got status %v, retry_count %d", got.Status, got.RetryCount)
+	}
+	if !got.ScheduledAt.Equal(n.ScheduledAt) || !got.CreatedAt.Equal(n.CreatedAt) {
+		t.Errorf("expected times %v/%v, got %v/%v", n.ScheduledAt, n.CreatedAt, got.ScheduledAt, got.CreatedAt)
+	}
+	if got.RetryPolicy == nil || got.RetryPolicy.Kind != domain.RetryFixed || got.RetryPolicy.MaxAttempts != 3 {
+		t.Errorf("expected retry policy to be stored, got %+v", got.RetryPolicy)
+	}
+	if w := got.DeliveryWindow; w == nil || w.Timezone != "Europe/Moscow" || w.End != 21*60 || len(w.Weekdays) != 1 {
+		t.Errorf("expected delivery window to be stored, got %+v", got.DeliveryWindow)
+	}
+	if got.Category != "marketing" {
+		t.Errorf("expected category to be stored, got %q", got.Category)
+	}
+
+	if err := p.Create(ctx, n); !errors.Is(err, domain.ErrNotifyAlreadyExists) {
+		t.Errorf("expected ErrNotifyAlreadyExists for duplicate id, got %v", err)
+	}
+	if _, err := p.GetNotifyByID(ctx, domain.NewNotify().ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := p.DeleteByID(ctx, n.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := p.GetNotifyByID(ctx, n.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func testIdempotencyKey(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now())
+	n.IdempotencyKey = "key-" + n.ID
+	n.RequestHash = n.Fingerprint()
+	mustCreate(t, p, n)
+
+	// Act
+	got, err := p.GetByIdempotencyKey(ctx, n.IdempotencyKey)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("get by key: %v", err)
+	}
+	if got.ID != n.ID || got.RequestHash != n.RequestHash {
+		t.Errorf("expected %s with hash %s, got %s with %s", n.ID, n.RequestHash, got.ID, got.RequestHash)
+	}
+
+	other := newNotify(time.Now())
+	other.IdempotencyKey = n.IdempotencyKey
+	if err := p.Create(ctx, other); !errors.Is(err, domain.ErrNotifyAlreadyExists) {
+		t.Errorf("expected ErrNotifyAlreadyExists for reused key, got %v", err)
+	}
+	if _, err := p.GetByIdempotencyKey(ctx, "missing-"+n.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testCreateBatch(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	existing := newNotify(time.Now())
+	existing.IdempotencyKey = "key-" + existing.ID
+	mustCreate(t, p, existing)
+
+	fresh := newNotify(time.Now().Add(time.Hour))
+	reused := newNotify(time.Now())
+	reused.IdempotencyKey = existing.IdempotencyKey
+	keyed := newNotify(time.Now())
+	keyed.IdempotencyKey = "key-" + keyed.ID
+	dupKey := newNotify(time.Now())
+	dupKey.IdempotencyKey = keyed.IdempotencyKey
+
+	// Act
+	inserted, err := p.CreateBatch(ctx, []*domain.Notify{fresh, reused, keyed, dupKey, existing}, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	if len(inserted) != 2 || !slices.Contains(inserted, fresh.ID) || !slices.Contains(inserted, keyed.ID) {
+		t.Fatalf("expected %s and %s to be inserted, got %v", fresh.ID, keyed.ID, inserted)
+	}
+	got := mustGet(t, p, fresh.ID)
+	if got.Status != domain.StatusPending || !got.ScheduledAt.Equal(fresh.ScheduledAt) || got.Target != fresh.Target {
+		t.Errorf("batch notify mismatch: %+v", got)
+	}
+	for _, n := range []*domain.Notify{reused, dupKey} {
+		if _, err := p.GetNotifyByID(ctx, n.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("expected conflicting notify %s to be skipped, got %v", n.ID, err)
+		}
+	}
+}
+
+func testCreateBatchAtomic(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	existing := newNotify(time.Now())
+	mustCreate(t, p, existing)
+	fresh := newNotify(time.Now())
+
+	// Act
+	inserted, err := p.CreateBatch(ctx, []*domain.Notify{fresh, existing}, true)
+
+	// Assert
+	if !errors.Is(err, domain.ErrBatchConflict) {
+		t.Fatalf("expected ErrBatchConflict, got %v", err)
+	}
+	if len(inserted) != 1 || inserted[0] != fresh.ID {
+		t.Errorf("expected rolled back ids [%s], got %v", fresh.ID, inserted)
+	}
+	if _, err := p.GetNotifyByID(ctx, fresh.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected atomic batch to be rolled back, got %v", err)
+	}
+
+	inserted, err = p.CreateBatch(ctx, []*domain.Notify{fresh}, true)
+	if err != nil || len(inserted) != 1 {
+		t.Fatalf("expected clean atomic batch to be inserted, got %v, %v", inserted, err)
+	}
+	mustGet(t, p, fresh.ID)
+}
+
+func testUpdateStatus(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now())
+	mustCreate(t, p, n)
+
+	// Act
+	lastErr := "timeout"
+	next := truncate(time.Now().Add(time.Minute))
+	err := p.UpdateStatus(ctx, n.ID, domain.StatusPending, &next, 1, &lastErr)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got := mustGet(t, p, n.ID)
+	if got.RetryCount != 1 || got.LastError == nil || *got.LastError != lastErr || !got.ScheduledAt.Equal(next) {
+		t.Errorf("update not applied: %+v", got)
+	}
+
+	// scheduled_at nil сохраняет прежнее время
+	if err := p.UpdateStatus(ctx, n.ID, domain.StatusSent, nil, 1, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got = mustGet(t, p, n.ID)
+	if got.Status != domain.StatusSent || got.LastError != nil || !got.ScheduledAt.Equal(next) {
+		t.Errorf("expected sent notify with kept scheduled_at, got %+v", got)
+	}
+
+	missing := domain.NewNotify().ID
+	if err := p.UpdateStatus(ctx, missing, domain.StatusSent, nil, 0, nil); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testUpdate(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now().Add(time.Hour))
+	mustCreate(t, p, n)
+	created := mustGet(t, p, n.ID)
+	if created.Version != 1 {
+		t.Fatalf("expected version 1 after create, got %d", created.Version)
+	}
+
+	// Act
+	target := "other@example.com"
+	next := truncate(time.Now().Add(2 * time.Hour))
+	got, err := p.Update(ctx, n.ID, domain.NotifyPatch{Target: &target, ScheduledAt: &next}, created.Version)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got.Version != 2 || got.Target != target || !got.ScheduledAt.Equal(next) {
+		t.Errorf("update not applied: %+v", got)
+	}
+	if string(got.Payload) != string(n.Payload) || got.Channel != n.Channel || got.Status != domain.StatusPending {
+		t.Errorf("expected other fields to be kept, got %+v", got)
+	}
+	if stored := mustGet(t, p, n.ID); stored.Version != 2 || stored.Target != target {
+		t.Errorf("expected update to be stored, got %+v", stored)
+	}
+
+	if _, err := p.Update(ctx, n.ID, domain.NotifyPatch{Payload: []byte(`{"text":"stale"}`)}, created.Version); !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch for stale version, got %v", err)
+	}
+	got, err = p.Update(ctx, n.ID, domain.NotifyPatch{Payload: []byte(`{"text":"new"}`)}, 0)
+	if err != nil || got.Version != 3 || string(got.Payload) != `{"text":"new"}` {
+		t.Errorf("expected unconditional update, got %+v, %v", got, err)
+	}
+
+	if err := p.UpdateStatus(ctx, n.ID, domain.StatusPending, nil, 1, nil); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	if stored := mustGet(t, p, n.ID); stored.Version != 4 {
+		t.Errorf("expected status change to bump version to 4, got %d", stored.Version)
+	}
+	if _, err := p.Update(ctx, domain.NewNotify().ID, domain.NotifyPatch{Target: &target}, 0); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testUpdateAfterLock(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now().Add(-time.Minute))
+	mustCreate(t, p, n)
+	version := mustGet(t, p, n.ID).Version
+
+	// Act: scheduler забрал notify раньше, чем пришло редактирование
+	locked, err := p.LockAndFetchReady(ctx, 10, time.Hour)
+	if err != nil || len(locked) != 1 {
+		t.Fatalf("lock: %v, %d", err, len(locked))
+	}
+	target := "late@example.com"
+	_, err = p.Update(ctx, n.ID, domain.NotifyPatch{Target: &target}, version)
+
+	// Assert
+	if !errors.Is(err, domain.ErrNotifyNotEditable) {
+		t.Errorf("expected ErrNotifyNotEditable, got %v", err)
+	}
+	if locked[0].Version <= version {
+		t.Errorf("expected lock to bump version above %d, got %d", version, locked[0].Version)
+	}
+	if stored := mustGet(t, p, n.ID); stored.Target != n.Target {
+		t.Errorf("expected locked notify to stay unchanged, got target %s", stored.Target)
+	}
+}
+
+func testReschedule(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now().Add(-time.Minute))
+	mustCreate(t, p, n)
+	lastErr := "boom"
+	if err := p.UpdateStatus(ctx, n.ID, domain.StatusInProcess, nil, 1, &lastErr); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// Act: повторные переносы (лимиты, окно доставки) не являются попытками
+	next := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	for range 3 {
+		if err := p.Reschedule(ctx, n.ID, next); err != nil {
+			t.Fatalf("reschedule: %v", err)
+		}
+	}
+
+	// Assert
+	got := mustGet(t, p, n.ID)
+	if got.Status != domain.StatusPending || !got.ScheduledAt.Equal(next) {
+		t.Errorf("expected pending at %v, got %v at %v", next, got.Status, got.ScheduledAt)
+	}
+	if got.RetryCount != 1 || got.LastError == nil || *got.LastError != lastErr {
+		t.Errorf("expected retry_count and last_error to be kept, got %d, %v", got.RetryCount, got.LastError)
+	}
+	if err := p.UpdateStatus(ctx, n.ID, domain.StatusFailed, nil, 1, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	letters, err := p.ListDeadLetters(ctx, domain.NotifyFilter{Limit: 10})
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("expected one dead letter, got %d, %v", len(letters), err)
+	}
+	if len(letters[0].Errors) != 1 {
+		t.Errorf("expected single error in history, got %+v", letters[0].Errors)
+	}
+
+	if err := p.Reschedule(ctx, domain.NewNotify().ID, next); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testCancel(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	pending := newNotify(time.Now().Add(time.Hour))
+	sent := newNotify(time.Now())
+	mustCreate(t, p, pending)
+	mustCreate(t, p, sent)
+	if err := p.UpdateStatus(ctx, sent.ID, domain.StatusSent, nil, 0, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// Act
+	got, err := p.Cancel(ctx, pending.ID)
+
+	// Assert
+	if err != nil || got.Status != domain.StatusCanceled {
+		t.Fatalf("expected canceled notify, got %v, %v", got, err)
+	}
+	if _, err := p.Cancel(ctx, pending.ID); err != nil {
+		t.Errorf("expected repeated cancel to succeed, got %v", err)
+	}
+	if _, err := p.Cancel(ctx, sent.ID); !errors.Is(err, domain.ErrNotifyNotCancelable) {
+		t.Errorf("expected ErrNotifyNotCancelable, got %v", err)
+	}
+	if _, err := p.Cancel(ctx, domain.NewNotify().ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testCancelInProcess(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now().Add(-time.Minute))
+	mustCreate(t, p, n)
+	if _, err := p.LockAndFetchReady(ctx, 10, time.Minute); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if _, err := p.Cancel(ctx, n.ID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	// Act: воркер, захвативший notify до отмены, пишет повтор и итог отправки
+	next := time.Now().Add(time.Hour)
+	lastErr := "boom"
+	retryErr := p.UpdateStatus(ctx, n.ID, domain.StatusPending, &next, 1, &lastErr)
+	sentErr := p.UpdateStatus(ctx, n.ID, domain.StatusSent, nil, 1, nil)
+
+	// Assert
+	if !errors.Is(retryErr, domain.ErrNotifyCanceled) || !errors.Is(sentErr, domain.ErrNotifyCanceled) {
+		t.Fatalf("expected ErrNotifyCanceled, got %v and %v", retryErr, sentErr)
+	}
+	got := mustGet(t, p, n.ID)
+	if got.Status != domain.StatusCanceled || got.RetryCount != 0 || got.LastError != nil {
+		t.Errorf("expected notify to stay canceled, got status %v, retry_count %d", got.Status, got.RetryCount)
+	}
+	if ready, _ := p.LockAndFetchReady(ctx, 10, 0); len(ready) != 0 {
+		t.Errorf("expected canceled notify not to be rescheduled, got %d", len(ready))
+	}
+}
+
+func testLockAndFetchReady(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	now := time.Now()
+	late := newNotify(now.Add(-time.Minute))
+	early := newNotify(now.Add(-time.Hour))
+	middle := newNotify(now.Add(-30 * time.Minute))
+	future := newNotify(now.Add(time.Hour))
+	for _, n := range []*domain.Notify{late, early, middle, future} {
+		mustCreate(t, p, n)
+	}
+
+	// Act: лимит 2 - самые ранние по scheduled_at
+	got, err := p.LockAndFetchReady(ctx, 2, time.Hour)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if ids(got) != ids([]*domain.Notify{early, middle}) {
+		t.Fatalf("expected [early middle], got %v", ids(got))
+	}
+	for _, n := range got {
+		if n.Status != domain.StatusInProcess {
+			t.Errorf("expected InProcess, got %v", n.Status)
+		}
+	}
+
+	got, err = p.LockAndFetchReady(ctx, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if ids(got) != ids([]*domain.Notify{late}) {
+		t.Fatalf("expected only late notify, got %v", ids(got))
+	}
+
+	// захваченные не выдаются повторно, пока не истек visibility timeout
+	got, _ = p.LockAndFetchReady(ctx, 10, time.Hour)
+	if len(got) != 0 {
+		t.Errorf("expected nothing before visibility timeout, got %v", ids(got))
+	}
+	got, _ = p.LockAndFetchReady(ctx, 10, 0)
+	if len(got) != 3 {
+		t.Errorf("expected 3 stale notifies to be reclaimed, got %v", ids(got))
+	}
+}
+
+func testLockAndFetchReadyConcurrent(t *testing.T, p domain.NotifyPostgres) {
+	const (
+		count   = 50
+		workers = 8
+	)
+	ctx := context.Background()
+	for range count {
+		mustCreate(t, p, newNotify(time.Now().Add(-time.Minute)))
+	}
+
+	// Act: планировщики разбирают notify параллельно
+	var (
+		mu     sync.Mutex
+		locked = make(map[string]int)
+		wg     sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				batch, err := p.LockAndFetchReady(ctx, 3, time.Hour)
+				if err != nil {
+					t.Errorf("lock: %v", err)
+					return
+				}
+				if len(batch) == 0 {
+					return
+				}
+				mu.Lock()
+				for _, n := range batch {
+					locked[n.ID]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert: каждый notify захвачен ровно один раз
+	if len(locked) != count {
+		t.Errorf("expected %d notifies locked, got %d", count, len(locked))
+	}
+	for id, times := range locked {
+		if times != 1 {
+			t.Errorf("notify %s locked %d times", id, times)
+		}
+	}
+}
+
+func testCountPending(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	for range 3 {
+		mustCreate(t, p, newNotify(time.Now().Add(time.Hour)))
+	}
+	sent := newNotify(time.Now())
+	mustCreate(t, p, sent)
+	if err := p.UpdateStatus(ctx, sent.ID, domain.StatusSent, nil, 0, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// Act
+	count, err := p.CountPending(ctx)
+
+	// Assert
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 pending, got %d, %v", count, err)
+	}
+}
+
+func testListPagination(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	base := truncate(time.Now().Add(-time.Hour))
+
+	var want []string
+	for i := range 7 {
+		n := newNotify(time.Now())
+		n.CreatedAt = base.Add(time.Duration(i%3) * time.Minute) // одинаковые created_at упорядочиваются по ID
+		mustCreate(t, p, n)
+		want = append(want, n.ID)
+	}
+	other := newNotify(time.Now())
+	other.Channel = "telegram"
+	mustCreate(t, p, other)
+
+	// Act: обход страницами по курсору, created_at DESC
+	filter := domain.NotifyFilter{Channel: "email", SortBy: domain.SortByCreatedAt, Desc: true, Limit: 3}
+	var got []*domain.Notify
+	for {
+		page, err := p.List(ctx, filter)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		got = append(got, page...)
+		if len(page) < filter.Limit {
+			break
+		}
+		filter.Cursor = domain.NewCursor(page[len(page)-1], filter.SortBy, filter.Desc)
+	}
+
+	// Assert: все notify канала ровно по разу, в порядке убывания
+	if len(got) != len(want) {
+		t.Fatalf("expected %d notifies, got %d", len(want), len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		prev, cur := got[i-1], got[i]
+		if cur.CreatedAt.After(prev.CreatedAt) || (cur.CreatedAt.Equal(prev.CreatedAt) && cur.ID > prev.ID) {
+			t.Fatalf("order broken at %d: %v then %v", i, prev.ID, cur.ID)
+		}
+	}
+	slices.Sort(want)
+	gotIDs := make([]string, 0, len(got))
+	for _, n := range got {
+		gotIDs = append(gotIDs, n.ID)
+	}
+	slices.Sort(gotIDs)
+	if !slices.Equal(gotIDs, want) {
+		t.Errorf("expected %v, got %v", want, gotIDs)
+	}
+
+	// offset без курсора
+	page, err := p.List(ctx, domain.NotifyFilter{Channel: "email", Limit: 10, Offset: 5})
+	if err != nil || len(page) != 2 {
+		t.Errorf("expected 2 notifies after offset 5, got %d, %v", len(page), err)
+	}
+}
+
+func testDeadLettersAndRequeue(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	failed := newNotify(time.Now())
+	pending := newNotify(time.Now().Add(time.Hour))
+	mustCreate(t, p, failed)
+	mustCreate(t, p, pending)
+
+	first, second := "timeout", "connection refused"
+	if err := p.UpdateStatus(ctx, failed.ID, domain.StatusPending, nil, 1, &first); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := p.UpdateStatus(ctx, failed.ID, domain.StatusFailed, nil, 2, &second); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// Act
+	letters, err := p.ListDeadLetters(ctx, domain.NotifyFilter{Statuses: []domain.Status{domain.StatusPending}, Limit: 10})
+
+	// Assert: статусы фильтра игнорируются, история ошибок по порядку
+	if err != nil {
+		t.Fatalf("dead letters: %v", err)
+	}
+	if len(letters) != 1 || letters[0].ID != failed.ID {
+		t.Fatalf("expected only failed notify, got %d letters", len(letters))
+	}
+	if errs := letters[0].Errors; len(errs) != 2 || errs[0].Error != first || errs[1].Error != second || errs[1].Attempt != 2 {
+		t.Errorf("unexpected error history: %+v", errs)
+	}
+
+	if _, err := p.Requeue(ctx, pending.ID); !errors.Is(err, domain.ErrNotifyNotRetryable) {
+		t.Errorf("expected ErrNotifyNotRetryable, got %v", err)
+	}
+	got, err := p.Requeue(ctx, failed.ID)
+	if err != nil || got.Status != domain.StatusPending || got.RetryCount != 0 {
+		t.Fatalf("expected requeued notify, got %+v, %v", got, err)
+	}
+	if _, err := p.Requeue(ctx, domain.NewNotify().ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := p.UpdateStatus(ctx, failed.ID, domain.StatusFailed, nil, 3, &second); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	requeued, err := p.RequeueFailed(ctx, domain.NotifyFilter{Channel: failed.Channel})
+	if err != nil || !slices.Equal(requeued, []string{failed.ID}) {
+		t.Errorf("expected [%s] requeued, got %v, %v", failed.ID, requeued, err)
+	}
+}
+
+func testFallback(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now().Add(-time.Minute))
+	n.Channel, n.Target = "telegram", "42"
+	n.Fallbacks = []domain.Route{{Channel: "email", Target: "user@example.com"}, {Channel: "webhook", Target: "https://example.com/hook"}}
+	mustCreate(t, p, n)
+	if got := mustGet(t, p, n.ID); len(got.Fallbacks) != 2 || got.Fallbacks[1].Channel != "webhook" {
+		t.Fatalf("expected fallbacks to be stored, got %+v", got.Fallbacks)
+	}
+	lastErr := "chat not found"
+	if err := p.UpdateStatus(ctx, n.ID, domain.StatusInProcess, nil, 2, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// Act
+	got, err := p.Fallback(ctx, n.ID, &lastErr)
+
+	// Assert: первый запасной маршрут становится основным, попытки начинаются заново
+	if err != nil {
+		t.Fatalf("fallback: %v", err)
+	}
+	if got.Channel != "email" || got.Target != "user@example.com" {
+		t.Errorf("expected email route, got %s/%s", got.Channel, got.Target)
+	}
+	if got.Status != domain.StatusPending || got.RetryCount != 0 || got.LastError == nil || *got.LastError != lastErr {
+		t.Errorf("expected pending notify with reset retries, got %+v", got)
+	}
+	if len(got.Fallbacks) != 1 || got.Fallbacks[0].Channel != "webhook" {
+		t.Errorf("expected webhook fallback left, got %+v", got.Fallbacks)
+	}
+	if got.ScheduledAt.After(time.Now()) {
+		t.Errorf("expected immediate send, got %v", got.ScheduledAt)
+	}
+	if stored := mustGet(t, p, n.ID); stored.Channel != "email" || len(stored.Fallbacks) != 1 {
+		t.Errorf("expected switch to be persisted, got %+v", stored)
+	}
+
+	if _, err := p.Fallback(ctx, n.ID, &lastErr); err != nil {
+		t.Fatalf("second fallback: %v", err)
+	}
+	if _, err := p.Fallback(ctx, n.ID, &lastErr); !errors.Is(err, domain.ErrNoRoute) {
+		t.Errorf("expected ErrNoRoute when fallbacks are exhausted, got %v", err)
+	}
+	if _, err := p.Fallback(ctx, domain.NewNotify().ID, &lastErr); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testAttempts(t *testing.T, p domain.NotifyPostgres) {
+	ctx := context.Background()
+	n := newNotify(time.Now())
+	mustCreate(t, p, n)
+
+	base := truncate(time.Now())
+	second := domain.NewAttempt(n, domain.AttemptSend, "worker-1")
+	second.Outcome = domain.OutcomeSuccess
+	second.ProviderMessageID = "msg-1"
+	second.Latency = 150 * time.Millisecond
+	second.CreatedAt = base.Add(time.Second)
+	first := domain.NewAttempt(n, domain.AttemptPublish, "worker-1")
+	first.Outcome = domain.OutcomeSuccess
+	first.CreatedAt = base
+
+	// Act
+	for _, a := range []*domain.Attempt{second, first} {
+		if err := p.RecordAttempt(ctx, a); err != nil {
+			t.Fatalf("record attempt: %v", err)
+		}
+	}
+	got, err := p.ListAttempts(ctx, n.ID)
+
+	// Assert: хронологический порядок
+	if err != nil {
+		t.Fatalf("list attempts: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != first.ID || got[1].ID != second.ID {
+		t.Fatalf("expected [publish send], got %+v", got)
+	}
+	if got[1].ProviderMessageID != "msg-1" || got[1].Latency != second.Latency || got[1].Kind != domain.AttemptSend {
+		t.Errorf("attempt mismatch: %+v", got[1])
+	}
+
+	// попытки удаляются вместе с notify
+	if err := p.DeleteByID(ctx, n.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	got, err = p.ListAttempts(ctx, n.ID)
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected no attempts after delete, got %d, %v", len(got), err)
+	}
+}
+
+func testTenantIsolation(t *testing.T, p domain.NotifyPostgres) {
+	teamA := domain.WithTenant(context.Background(), "team-a")
+	teamB := domain.WithTenant(context.Background(), "team-b")
+
+	n := newNotify(time.Now().Add(time.Hour))
+	n.IdempotencyKey = "key-" + n.ID
+	if err := p.Create(teamA, n); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Act & Assert: другой арендатор не видит и не может изменить чужой notify
+	if _, err := p.GetNotifyByID(teamB, n.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for other tenant, got %v", err)
+	}
+	if page, err := p.List(teamB, domain.NotifyFilter{Limit: 10}); err != nil || len(page) != 0 {
+		t.Errorf("expected empty list for other tenant, got %d, %v", len(page), err)
+	}
+	if _, err := p.Cancel(teamB, n.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound on cancel by other tenant, got %v", err)
+	}
+	if _, err := p.GetByIdempotencyKey(teamB, n.IdempotencyKey); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for other tenant's key, got %v", err)
+	}
+	if err := p.DeleteByID(teamB, n.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	// ключи идемпотентности уникальны в пределах арендатора
+	other := newNotify(time.Now())
+	other.IdempotencyKey = n.IdempotencyKey
+	if err := p.Create(teamB, other); err != nil {
+		t.Errorf("expected same key to be accepted for other tenant, got %v", err)
+	}
+
+	got, err := p.GetNotifyByID(teamA, n.ID)
+	if err != nil || got.Status != domain.StatusPending || got.TenantID != "team-a" {
+		t.Fatalf("expected untouched notify of team-a, got %+v, %v", got, err)
+	}
+	// системный контекст планировщика видит всех
+	if page, err := p.List(context.Background(), domain.NotifyFilter{Limit: 10}); err != nil || len(page) != 2 {
+		t.Errorf("expected 2 notifies for system context, got %d, %v", len(page), err)
+	}
+}
+
+func newNotify(scheduledAt time.Time) *domain.Notify {
+	n := domain.NewNotify()
+	n.Payload = []byte(`{"text":"hello"}`)
+	n.Target = "user@example.com"
+	n.Channel = "email"
+	n.Status = domain.StatusPending
+	n.ScheduledAt = truncate(scheduledAt)
+	n.CreatedAt = truncate(n.CreatedAt)
+	return n
+}
+
+// truncate - Postgres хранит время с точностью до микросекунд
+func truncate(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+func mustCreate(t *testing.T, p domain.NotifyPostgres, n *domain.Notify) {
+	t.Helper()
+	if err := p.Create(context.Background(), n); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+}
+
+func mustGet(t *testing.T, p domain.NotifyPostgres, id string) *domain.Notify {
+	t.Helper()
+	n, err := p.GetNotifyByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	return n
+}
+
+func ids(notifies []*domain.Notify) string {
+	var s string
+	for _, n := range notifies {
+		s += n.ID + " "
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+type NewRateLimiter func(t *testing.T) domain.RateLimiter
+
+func RunRateLimiter(t *testing.T, newLimiter NewRateLimiter) {
+	t.Run("Burst", func(t *testing.T) { testRateLimiterBurst(t, newLimiter(t)) })
+	t.Run("AllOrNothing", func(t *testing.T) { testRateLimiterAllOrNothing(t, newLimiter(t)) })
+	t.Run("Refill", func(t *testing.T) { testRateLimiterRefill(t, newLimiter(t)) })
+}
+
+func testRateLimiterBurst(t *testing.T, l domain.RateLimiter) {
+	ctx := context.Background()
+	bucket := domain.RateBucket{Key: "channel:telegram", Limit: domain.RateLimit{Limit: 3, Period: time.Hour}}
+
+	// Act: емкость бакета доступна сразу
+	for i := range 3 {
+		if wait, err := l.Take(ctx, []domain.RateBucket{bucket}); err != nil || wait != 0 {
+			t.Fatalf("take %d: expected token, got wait %v, %v", i, wait, err)
+		}
+	}
+	wait, err := l.Take(ctx, []domain.RateBucket{bucket})
+
+	// Assert: следующий токен через Period / Limit
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if wait <= 0 || wait > bucket.Limit.Interval() {
+		t.Errorf("expected wait in (0, %v], got %v", bucket.Limit.Interval(), wait)
+	}
+}
+
+func testRateLimiterAllOrNothing(t *testing.T, l domain.RateLimiter) {
+	ctx := context.Background()
+	channel := domain.RateBucket{Key: "channel:email", Limit: domain.RateLimit{Limit: 10, Period: time.Hour}}
+	target := domain.RateBucket{Key: "target:email:user@example.com", Limit: domain.RateLimit{Limit: 1, Period: time.Hour}}
+
+	if wait, err := l.Take(ctx, []domain.RateBucket{channel, target}); err != nil || wait != 0 {
+		t.Fatalf("expected token, got wait %v, %v", wait, err)
+	}
+
+	// Act: получатель исчерпал лимит, токен канала не должен списаться
+	for range 5 {
+		if wait, err := l.Take(ctx, []domain.RateBucket{channel, target}); err != nil || wait == 0 {
+			t.Fatalf("expected throttling by target, got wait %v, %v", wait, err)
+		}
+	}
+
+	// Assert: в канале осталось 9 токенов
+	for i := range 9 {
+		if wait, err := l.Take(ctx, []domain.RateBucket{channel}); err != nil || wait != 0 {
+			t.Fatalf("take %d: expected channel token, got wait %v, %v", i, wait, err)
+		}
+	}
+	if wait, _ := l.Take(ctx, []domain.RateBucket{channel}); wait == 0 {
+		t.Error("expected channel bucket to be empty")
+	}
+}
+
+func testRateLimiterRefill(t *testing.T, l domain.RateLimiter) {
+	ctx := context.Background()
+	bucket := domain.RateBucket{Key: "tenant:team-a", Limit: domain.RateLimit{Limit: 1, Period: 50 * time.Millisecond}}
+
+	if wait, err := l.Take(ctx, []domain.RateBucket{bucket}); err != nil || wait != 0 {
+		t.Fatalf("expected token, got wait %v, %v", wait, err)
+	}
+	wait, err := l.Take(ctx, []domain.RateBucket{bucket})
+	if err != nil || wait == 0 {
+		t.Fatalf("expected throttling, got wait %v, %v", wait, err)
+	}
+
+	// Act
+	time.Sleep(wait)
+
+	// Assert
+	if wait, err := l.Take(ctx, []domain.RateBucket{bucket}); err != nil || wait != 0 {
+		t.Errorf("expected token after refill, got wait %v, %v", wait, err)
+	}
+}
@@ -0,0 +1,177 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// NewRecipientPostgres возвращает пустой реестр получателей
+type NewRecipientPostgres func(t *testing.T) domain.RecipientPostgres
+
+func RunRecipientPostgres(t *testing.T, newStore NewRecipientPostgres) {
+	t.Run("CreateAndGet", func(t *testing.T) { testRecipientCreateAndGet(t, newStore(t)) })
+	t.Run("Update", func(t *testing.T) { testRecipientUpdate(t, newStore(t)) })
+	t.Run("List", func(t *testing.T) { testRecipientList(t, newStore(t)) })
+	t.Run("TenantIsolation", func(t *testing.T) { testRecipientTenantIsolation(t, newStore(t)) })
+}
+
+func testRecipientCreateAndGet(t *testing.T, p domain.RecipientPostgres) {
+	ctx := context.Background()
+	r := newRecipient()
+	r.DeliveryWindow = &domain.DeliveryWindow{Timezone: "Europe/Moscow", Start: 9 * 60, End: 21 * 60}
+
+	// Act
+	if err := p.CreateRecipient(ctx, r); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got, err := p.GetRecipientByID(ctx, r.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != r.Name || got.Contacts["telegram"] != "42" || got.Contacts["email"] != "user@example.com" {
+		t.Errorf("recipient mismatch: got %+v", got)
+	}
+	if len(got.Channels) != 2 || got.Channels[0] != "telegram" || len(got.OptOuts) != 1 || got.OptOuts[0] != "webhook" {
+		t.Errorf("expected preferences to be stored, got channels %v, opt-outs %v", got.Channels, got.OptOuts)
+	}
+	if got.DeliveryWindow == nil || got.DeliveryWindow.End != 21*60 {
+		t.Errorf("expected delivery window to be stored, got %+v", got.DeliveryWindow)
+	}
+	if got.TenantID != domain.DefaultTenant {
+		t.Errorf("expected default tenant, got %q", got.TenantID)
+	}
+
+	if _, err := p.GetRecipientByID(ctx, domain.NewRecipient().ID); !errors.Is(err, domain.ErrRecipientNotFound) {
+		t.Errorf("expected ErrRecipientNotFound, got %v", err)
+	}
+
+	if err := p.DeleteRecipient(ctx, r.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := p.GetRecipientByID(ctx, r.ID); !errors.Is(err, domain.ErrRecipientNotFound) {
+		t.Errorf("expected ErrRecipientNotFound after delete, got %v", err)
+	}
+	if err := p.DeleteRecipient(ctx, r.ID); !errors.Is(err, domain.ErrRecipientNotFound) {
+		t.Errorf("expected ErrRecipientNotFound on second delete, got %v", err)
+	}
+}
+
+func testRecipientUpdate(t *testing.T, p domain.RecipientPostgres) {
+	ctx := context.Background()
+	r := newRecipient()
+	if err := p.CreateRecipient(ctx, r); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	update := domain.NewRecipient()
+	update.ID = r.ID
+	update.Contacts = map[string]string{"email": "new@example.com"}
+
+	// Act
+	err := p.UpdateRecipient(ctx, update)
+
+	// Assert: получатель заменяется целиком, время создания сохраняется
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err := p.GetRecipientByID(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(got.Contacts) != 1 || got.Contacts["email"] != "new@example.com" || len(got.Channels) != 0 || len(got.OptOuts) != 0 {
+		t.Errorf("expected replaced recipient, got %+v", got)
+	}
+	if !got.CreatedAt.Equal(r.CreatedAt) || !update.CreatedAt.Equal(r.CreatedAt) {
+		t.Errorf("expected created_at %v to be kept, got %v", r.CreatedAt, got.CreatedAt)
+	}
+
+	missing := newRecipient()
+	if err := p.UpdateRecipient(ctx, missing); !errors.Is(err, domain.ErrRecipientNotFound) {
+		t.Errorf("expected ErrRecipientNotFound, got %v", err)
+	}
+}
+
+func testRecipientList(t *testing.T, p domain.RecipientPostgres) {
+	ctx := context.Background()
+	base := truncate(time.Now())
+	var created []*domain.Recipient
+	for i := range 3 {
+		r := newRecipient()
+		r.CreatedAt = base.Add(time.Duration(i) * time.Second)
+		if err := p.CreateRecipient(ctx, r); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		created = append(created, r)
+	}
+
+	// Act
+	first, err := p.ListRecipients(ctx, 2, 0)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	second, err := p.ListRecipients(ctx, 2, 2)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+
+	// Assert: новые первыми
+	if len(first) != 2 || first[0].ID != created[2].ID || first[1].ID != created[1].ID {
+		t.Errorf("unexpected first page: %+v", first)
+	}
+	if len(second) != 1 || second[0].ID != created[0].ID {
+		t.Errorf("unexpected second page: %+v", second)
+	}
+}
+
+func testRecipientTenantIsolation(t *testing.T, p domain.RecipientPostgres) {
+	teamA := domain.WithTenant(context.Background(), "team-a")
+	teamB := domain.WithTenant(context.Background(), "team-b")
+
+	r := newRecipient()
+	if err := p.CreateRecipient(teamA, r); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Act & Assert: другой арендатор не видит и не может изменить чужого получателя
+	if _, err := p.GetRecipientByID(teamB, r.ID); !errors.Is(err, domain.ErrRecipientNotFound) {
+		t.Errorf("expected ErrRecipientNotFound for other tenant, got %v", err)
+	}
+	if list, err := p.ListRecipients(teamB, 10, 0); err != nil || len(list) != 0 {
+		t.Errorf("expected empty list for other tenant, got %d, %v", len(list), err)
+	}
+	if err := p.UpdateRecipient(teamB, newRecipientWithID(r.ID)); !errors.Is(err, domain.ErrRecipientNotFound) {
+		t.Errorf("expected ErrRecipientNotFound on update by other tenant, got %v", err)
+	}
+	if err := p.DeleteRecipient(teamB, r.ID); !errors.Is(err, domain.ErrRecipientNotFound) {
+		t.Errorf("expected ErrRecipientNotFound on delete by other tenant, got %v", err)
+	}
+
+	got, err := p.GetRecipientByID(teamA, r.ID)
+	if err != nil || got.TenantID != "team-a" || got.Contacts["telegram"] != "42" {
+		t.Fatalf("expected untouched recipient of team-a, got %+v, %v", got, err)
+	}
+}
+
+func newRecipient() *domain.Recipient {
+	r := domain.NewRecipient()
+	r.Name = "Ivan"
+	r.Contacts = map[string]string{"telegram": "42", "email": "user@example.com"}
+	r.Channels = []string{"telegram", "email"}
+	r.OptOuts = []string{"webhook"}
+	r.CreatedAt = truncate(r.CreatedAt)
+	r.UpdatedAt = r.CreatedAt
+	return r
+}
+
+func newRecipientWithID(id string) *domain.Recipient {
+	r := newRecipient()
+	r.ID = id
+	r.Contacts = map[string]string{"email": "other@example.com"}
+	return r
+}
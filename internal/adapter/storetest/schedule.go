@@ -0,0 +1,174 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// NewSchedulePostgres возвращает пустые хранилища серий и notify над одной базой:
+// серии создают notify и отменяют их при удалении
+type NewSchedulePostgres func(t *testing.T) (domain.SchedulePostgres, domain.NotifyPostgres)
+
+func RunSchedulePostgres(t *testing.T, newStore NewSchedulePostgres) {
+	run := func(name string, test func(*testing.T, domain.SchedulePostgres, domain.NotifyPostgres)) {
+		t.Run(name, func(t *testing.T) {
+			schedules, notifies := newStore(t)
+			test(t, schedules, notifies)
+		})
+	}
+	run("MaterializeAdvancesNextRun", testScheduleMaterialize)
+	run("MaterializeFinished", testScheduleFinished)
+	run("DeleteCancelsPending", testScheduleDelete)
+}
+
+func testScheduleMaterialize(t *testing.T, p domain.SchedulePostgres, notifies domain.NotifyPostgres) {
+	ctx := context.Background()
+	now := time.Now()
+	// пропущенные за простой срабатывания (start_at, +1m, +2m) схлопываются в одно
+	s := newSchedule(now.Add(-150*time.Second), now.Add(-150*time.Second))
+	mustCreateSchedule(t, p, s)
+	paused := newSchedule(now.Add(-90*time.Second), now.Add(-30*time.Second))
+	paused.Status = domain.SchedulePaused
+	mustCreateSchedule(t, p, paused)
+
+	// Act
+	created, err := p.MaterializeDue(ctx, 10)
+
+	// Assert: notify создан на next_run_at, серия сдвинута на срабатывание после текущего момента
+	if err != nil || created != 1 {
+		t.Fatalf("expected 1 materialized notify, got %d, %v", created, err)
+	}
+	got, err := p.GetScheduleByID(ctx, s.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != domain.ScheduleActive || got.NextRunAt == nil || !got.NextRunAt.After(now) {
+		t.Errorf("expected active schedule with next run after %v, got %+v", now, got)
+	}
+
+	list, err := notifies.List(ctx, domain.NotifyFilter{Limit: 10})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("expected 1 notify, got %d, %v", len(list), err)
+	}
+	n := list[0]
+	if n.ScheduleID != s.ID || n.Status != domain.StatusPending || n.Target != s.Target || n.TenantID != domain.DefaultTenant {
+		t.Errorf("unexpected materialized notify: %+v", n)
+	}
+	if !n.ScheduledAt.Equal(*s.NextRunAt) {
+		t.Errorf("expected notify at %v, got %v", *s.NextRunAt, n.ScheduledAt)
+	}
+
+	// повторный тик до следующего срабатывания ничего не создает
+	if created, err := p.MaterializeDue(ctx, 10); err != nil || created != 0 {
+		t.Errorf("expected nothing to materialize, got %d, %v", created, err)
+	}
+}
+
+func testScheduleFinished(t *testing.T, p domain.SchedulePostgres, notifies domain.NotifyPostgres) {
+	ctx := context.Background()
+	now := time.Now()
+	s := newSchedule(now.Add(-90*time.Second), now.Add(-30*time.Second))
+	endAt := truncate(now.Add(-20 * time.Second))
+	s.EndAt = &endAt
+	mustCreateSchedule(t, p, s)
+
+	// Act
+	created, err := p.MaterializeDue(ctx, 10)
+
+	// Assert: последнее срабатывание создано, следующее позже end_at - серия закончена
+	if err != nil || created != 1 {
+		t.Fatalf("expected 1 materialized notify, got %d, %v", created, err)
+	}
+	got, err := p.GetScheduleByID(ctx, s.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != domain.ScheduleFinished || got.NextRunAt != nil {
+		t.Errorf("expected finished schedule without next run, got %+v", got)
+	}
+	if created, err := p.MaterializeDue(ctx, 10); err != nil || created != 0 {
+		t.Errorf("expected finished schedule to stop, got %d, %v", created, err)
+	}
+	if list, err := notifies.List(ctx, domain.NotifyFilter{Limit: 10}); err != nil || len(list) != 1 {
+		t.Errorf("expected 1 notify, got %d, %v", len(list), err)
+	}
+}
+
+func testScheduleDelete(t *testing.T, p domain.SchedulePostgres, notifies domain.NotifyPostgres) {
+	ctx := context.Background()
+	now := time.Now()
+	s := newSchedule(now.Add(-90*time.Second), now.Add(-30*time.Second))
+	mustCreateSchedule(t, p, s)
+	if _, err := p.MaterializeDue(ctx, 10); err != nil {
+		t.Fatalf("materialize: %v", err)
+	}
+	list, err := notifies.List(ctx, domain.NotifyFilter{Limit: 10})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("expected 1 notify, got %d, %v", len(list), err)
+	}
+	pending := list[0]
+	// отправленный notify серии остается как есть
+	sent := newNotify(now.Add(-time.Hour))
+	sent.ScheduleID = s.ID
+	sent.Status = domain.StatusSent
+	mustCreate(t, notifies, sent)
+
+	// Act: чужой арендатор серию не видит
+	_, err = p.DeleteSchedule(domain.WithTenant(ctx, "team-b"), s.ID)
+
+	// Assert
+	if !errors.Is(err, domain.ErrScheduleNotFound) {
+		t.Fatalf("expected ErrScheduleNotFound for another tenant, got %v", err)
+	}
+	if n := mustGet(t, notifies, pending.ID); n.Status != domain.StatusPending {
+		t.Errorf("expected notify to stay pending, got %v", n.Status)
+	}
+
+	// Act
+	canceled, err := p.DeleteSchedule(ctx, s.ID)
+
+	// Assert: отменен только неотправленный notify, его ID возвращен для кеша
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(canceled) != 1 || canceled[0] != pending.ID {
+		t.Errorf("expected %s to be canceled, got %v", pending.ID, canceled)
+	}
+	if n := mustGet(t, notifies, pending.ID); n.Status != domain.StatusCanceled || n.Version != pending.Version+1 {
+		t.Errorf("expected canceled notify with bumped version, got %+v", n)
+	}
+	if n := mustGet(t, notifies, sent.ID); n.Status != domain.StatusSent {
+		t.Errorf("expected sent notify to stay sent, got %v", n.Status)
+	}
+	if _, err := p.GetScheduleByID(ctx, s.ID); !errors.Is(err, domain.ErrScheduleNotFound) {
+		t.Errorf("expected schedule to be deleted, got %v", err)
+	}
+	if _, err := p.DeleteSchedule(ctx, s.ID); !errors.Is(err, domain.ErrScheduleNotFound) {
+		t.Errorf("expected ErrScheduleNotFound on second delete, got %v", err)
+	}
+}
+
+// newSchedule - серия раз в минуту с наступившим срабатыванием nextRunAt
+func newSchedule(startAt, nextRunAt time.Time) *domain.Schedule {
+	s := domain.NewSchedule()
+	s.Payload = []byte(`{"text":"hello"}`)
+	s.Target = "user@example.com"
+	s.Channel = "email"
+	s.Interval = time.Minute
+	s.StartAt = truncate(startAt)
+	next := truncate(nextRunAt)
+	s.NextRunAt = &next
+	s.CreatedAt = truncate(s.CreatedAt)
+	return s
+}
+
+func mustCreateSchedule(t *testing.T, p domain.SchedulePostgres, s *domain.Schedule) {
+	t.Helper()
+	if err := p.CreateSchedule(context.Background(), s); err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// NewSuppressionPostgres возвращает пустой список подавления
+type NewSuppressionPostgres func(t *testing.T) domain.SuppressionPostgres
+
+func RunSuppressionPostgres(t *testing.T, newStore NewSuppressionPostgres) {
+	t.Run("Find", func(t *testing.T) { testSuppressionFind(t, newStore(t)) })
+	t.Run("Exists", func(t *testing.T) { testSuppressionExists(t, newStore(t)) })
+	t.Run("ListAndDelete", func(t *testing.T) { testSuppressionListAndDelete(t, newStore(t)) })
+}
+
+func testSuppressionFind(t *testing.T, p domain.SuppressionPostgres) {
+	ctx := context.Background()
+	marketing := newSuppression("team-a", "user@example.com")
+	marketing.Category = "marketing"
+	global := newSuppression("", "bounced@example.com")
+	global.Reason = domain.ReasonBounce
+	mustCreateSuppression(t, p, marketing)
+	mustCreateSuppression(t, p, global)
+
+	tests := []struct {
+		name     string
+		tenant   string
+		target   string
+		category string
+		want     string
+	}{
+		{name: "category", tenant: "team-a", target: "user@example.com", category: "marketing", want: marketing.ID},
+		{name: "target case", tenant: "team-a", target: "User@Example.com", category: "marketing", want: marketing.ID},
+		{name: "other category", tenant: "team-a", target: "user@example.com", category: "billing"},
+		{name: "no category", tenant: "team-a", target: "user@example.com"},
+		{name: "other tenant", tenant: "team-b", target: "user@example.com", category: "marketing"},
+		{name: "all tenants", tenant: "team-b", target: "bounced@example.com", category: "billing", want: global.ID},
+		{name: "legacy tenant", target: "bounced@example.com", want: global.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newNotify(time.Now())
+			n.TenantID = tt.tenant
+			n.Target = tt.target
+			n.Category = tt.category
+
+			// Act
+			got, err := p.FindSuppression(ctx, n)
+
+			// Assert
+			if tt.want == "" {
+				if !errors.Is(err, domain.ErrSuppressionNotFound) {
+					t.Errorf("expected ErrSuppressionNotFound, got %+v, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("find: %v", err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("expected suppression %s, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func testSuppressionExists(t *testing.T, p domain.SuppressionPostgres) {
+	ctx := context.Background()
+	s := newSuppression("team-a", "user@example.com")
+	mustCreateSuppression(t, p, s)
+
+	// Act
+	err := p.CreateSuppression(ctx, newSuppression("team-a", "user@example.com"))
+
+	// Assert
+	if !errors.Is(err, domain.ErrSuppressionExists) {
+		t.Errorf("expected ErrSuppressionExists, got %v", err)
+	}
+	// та же пара канал-адрес с другим охватом - отдельная запись
+	other := newSuppression("team-a", "user@example.com")
+	other.Category = "marketing"
+	mustCreateSuppression(t, p, other)
+	mustCreateSuppression(t, p, newSuppression("", "user@example.com"))
+}
+
+func testSuppressionListAndDelete(t *testing.T, p domain.SuppressionPostgres) {
+	teamA := domain.WithTenant(context.Background(), "team-a")
+	defaultTenant := domain.WithTenant(context.Background(), domain.DefaultTenant)
+	admin := domain.WithAllTenants(defaultTenant)
+
+	own := newSuppression("team-a", "a@example.com")
+	foreign := newSuppression("team-b", "b@example.com")
+	global := newSuppression("", "all@example.com")
+	for _, s := range []*domain.Suppression{own, foreign, global} {
+		mustCreateSuppression(t, p, s)
+	}
+
+	// Act
+	list, err := p.ListSuppressions(teamA, domain.SuppressionFilter{Limit: 10})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected own and global suppressions, got %+v", list)
+	}
+	filtered, err := p.ListSuppressions(teamA, domain.SuppressionFilter{Target: "a@example.com", Limit: 10})
+	if err != nil || len(filtered) != 1 || filtered[0].ID != own.ID {
+		t.Errorf("expected only %s by target, got %+v, %v", own.ID, filtered, err)
+	}
+
+	if err := p.DeleteSuppression(teamA, foreign.ID); !errors.Is(err, domain.ErrSuppressionNotFound) {
+		t.Errorf("expected foreign suppression to be invisible, got %v", err)
+	}
+	if err := p.DeleteSuppression(teamA, global.ID); !errors.Is(err, domain.ErrSuppressionNotFound) {
+		t.Errorf("expected global suppression to be protected from tenant, got %v", err)
+	}
+	if err := p.DeleteSuppression(teamA, own.ID); err != nil {
+		t.Errorf("delete own: %v", err)
+	}
+	if err := p.DeleteSuppression(defaultTenant, global.ID); !errors.Is(err, domain.ErrSuppressionNotFound) {
+		t.Errorf("expected global suppression to be protected from default tenant without api key, got %v", err)
+	}
+	if err := p.DeleteSuppression(admin, global.ID); err != nil {
+		t.Errorf("delete global by default tenant: %v", err)
+	}
+
+	rest, err := p.ListSuppressions(context.Background(), domain.SuppressionFilter{Limit: 10})
+	if err != nil || len(rest) != 1 || rest[0].ID != foreign.ID {
+		t.Errorf("expected only %s to remain, got %+v, %v", foreign.ID, rest, err)
+	}
+}
+
+func newSuppression(tenant, target string) *domain.Suppression {
+	s := domain.NewSuppression()
+	s.TenantID = tenant
+	s.Channel = "email"
+	s.Target = target
+	s.CreatedAt = truncate(s.CreatedAt)
+	return s
+}
+
+func mustCreateSuppression(t *testing.T, p domain.SuppressionPostgres, s *domain.Suppression) {
+	t.Helper()
+	if err := p.CreateSuppression(context.Background(), s); err != nil {
+		t.Fatalf("create suppression: %v", err)
+	}
+}
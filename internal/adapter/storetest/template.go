@@ -0,0 +1,98 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+)
+
+// NewTemplatePostgres возвращает пустое хранилище шаблонов
+type NewTemplatePostgres func(t *testing.T) domain.TemplatePostgres
+
+func RunTemplatePostgres(t *testing.T, newStore NewTemplatePostgres) {
+	t.Run("Versions", func(t *testing.T) { testTemplateVersions(t, newStore(t)) })
+	t.Run("TenantIsolation", func(t *testing.T) { testTemplateTenantIsolation(t, newStore(t)) })
+}
+
+func testTemplateVersions(t *testing.T, p domain.TemplatePostgres) {
+	ctx := context.Background()
+
+	for _, text := range []string{"v1", "v2"} {
+		tpl := newTemplate("welcome", text)
+		if err := p.CreateTemplateVersion(ctx, tpl); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	// Act
+	latest, err := p.GetTemplate(ctx, "welcome", "email", 0)
+
+	// Assert
+	if err != nil || latest.Version != 2 || latest.Text != "v2" {
+		t.Fatalf("expected version 2, got %+v, %v", latest, err)
+	}
+	if latest.TenantID != domain.DefaultTenant {
+		t.Errorf("expected default tenant, got %q", latest.TenantID)
+	}
+	first, err := p.GetTemplate(ctx, "welcome", "email", 1)
+	if err != nil || first.Text != "v1" {
+		t.Errorf("expected version 1, got %+v, %v", first, err)
+	}
+
+	if err := p.DeleteTemplate(ctx, "welcome", "email"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := p.ListTemplateVersions(ctx, "welcome", "email"); !errors.Is(err, domain.ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, got %v", err)
+	}
+}
+
+func testTemplateTenantIsolation(t *testing.T, p domain.TemplatePostgres) {
+	teamA := domain.WithTenant(context.Background(), "team-a")
+	teamB := domain.WithTenant(context.Background(), "team-b")
+
+	if err := p.CreateTemplateVersion(teamA, newTemplate("welcome", "a")); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Act: у второго арендатора свой шаблон с тем же именем и своей нумерацией версий
+	own := newTemplate("welcome", "b")
+	err := p.CreateTemplateVersion(teamB, own)
+
+	// Assert
+	if err != nil || own.Version != 1 {
+		t.Fatalf("expected first version for team-b, got %d, %v", own.Version, err)
+	}
+	if got, err := p.GetTemplate(teamA, "welcome", "email", 0); err != nil || got.Text != "a" {
+		t.Errorf("expected team-a template, got %+v, %v", got, err)
+	}
+	if got, err := p.GetTemplate(teamB, "welcome", "email", 0); err != nil || got.Text != "b" {
+		t.Errorf("expected team-b template, got %+v, %v", got, err)
+	}
+	if list, _ := p.ListTemplates(teamB); len(list) != 1 || list[0].TenantID != "team-b" {
+		t.Errorf("expected only team-b template in list, got %+v", list)
+	}
+	if versions, _ := p.ListTemplateVersions(teamA, "welcome", "email"); len(versions) != 1 || versions[0].Text != "a" {
+		t.Errorf("expected only team-a versions, got %+v", versions)
+	}
+
+	if err := p.DeleteTemplate(teamB, "welcome", "email"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := p.GetTemplate(teamA, "welcome", "email", 0); err != nil {
+		t.Errorf("expected team-a template to survive team-b delete, got %v", err)
+	}
+	if err := p.DeleteTemplate(teamB, "welcome", "email"); !errors.Is(err, domain.ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound for another tenant, got %v", err)
+	}
+}
+
+func newTemplate(name, text string) *domain.Template {
+	tpl := domain.NewTemplate()
+	tpl.Name = name
+	tpl.Channel = "email"
+	tpl.Text = text
+	return tpl
+}
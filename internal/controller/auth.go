@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+)
+
+const (
+	APIKeyHeader        = "X-API-Key"
+	AuthorizationHeader = "Authorization"
+
+	bearerPrefix = "Bearer "
+)
+
+var errInvalidAPIKey = errors.New("invalid api key")
+
+// APIKey - ключ доступа к API и арендатор, которому принадлежат созданные с ним notify
+type APIKey struct {
+	Key    string `mapstructure:"key"`
+	Tenant string `mapstructure:"tenant"`
+}
+
+// AuthConfig - аутентификация по API-ключам. При выключенной все запросы
+// выполняются от имени domain.DefaultTenant, но без права управлять записями
+// для всех арендаторов: его дает только ключ арендатора default
+type AuthConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Keys    []APIKey `mapstructure:"keys"`
+}
+
+func (c AuthConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.Keys) == 0 {
+		return errors.New("at least one key is required")
+	}
+	seen := make(map[string]struct{}, len(c.Keys))
+	for i, k := range c.Keys {
+		if k.Key == "" {
+			return fmt.Errorf("keys[%d]: empty key", i)
+		}
+		if k.Tenant == "" {
+			return fmt.Errorf("keys[%d]: empty tenant", i)
+		}
+		if _, ok := seen[k.Key]; ok {
+			return fmt.Errorf("keys[%d]: duplicate key", i)
+		}
+		seen[k.Key] = struct{}{}
+	}
+	return nil
+}
+
+// NewAuthMiddleware проверяет API-ключ из X-API-Key или Authorization: Bearer
+// и ограничивает контекст запроса арендатором ключа
+func NewAuthMiddleware(cfg AuthConfig, l log.Log) func(*router.Context) {
+	if !cfg.Enabled {
+		return func(c *router.Context) {
+			c.Request = c.Request.WithContext(domain.WithTenant(c.Request.Context(), domain.DefaultTenant))
+			c.Next()
+		}
+	}
+
+	// Ключи храним хешами: сравнение идет за постоянное время и не зависит от длины ключа
+	tenants := make(map[[sha256.Size]byte]string, len(cfg.Keys))
+	for _, k := range cfg.Keys {
+		tenants[sha256.Sum256([]byte(k.Key))] = k.Tenant
+	}
+
+	return func(c *router.Context) {
+		tenant, ok := lookupTenant(tenants, apiKeyFromRequest(c))
+		if !ok {
+			l.Warn().Str("path", c.FullPath()).Str("ip", c.ClientIP()).Msg("rejected request with invalid api key")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, router.H{
+				"error": errInvalidAPIKey.Error(),
+			})
+			return
+		}
+		ctx := domain.WithTenant(c.Request.Context(), tenant)
+		if tenant == domain.DefaultTenant {
+			ctx = domain.WithAllTenants(ctx)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func apiKeyFromRequest(c *router.Context) string {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return key
+	}
+	auth := c.GetHeader(AuthorizationHeader)
+	if strings.HasPrefix(auth, bearerPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(auth, bearerPrefix))
+	}
+	return ""
+}
+
+func lookupTenant(tenants map[[sha256.Size]byte]string, key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(key))
+	for hash, tenant := range tenants {
+		if subtle.ConstantTimeCompare(hash[:], sum[:]) == 1 {
+			return tenant, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+)
+
+// newAuthRouter возвращает роутер, который отвечает арендатором из контекста запроса
+func newAuthRouter(cfg AuthConfig) *router.Router {
+	r := router.New(router.Config{GinMode: "test"})
+	r.Use(NewAuthMiddleware(cfg, log.New()))
+	r.GET("/whoami", func(c *router.Context) {
+		tenant, _ := domain.TenantFrom(c)
+		c.String(http.StatusOK, tenant)
+	})
+	r.GET("/all-tenants", func(c *router.Context) {
+		c.String(http.StatusOK, strconv.FormatBool(domain.ManagesAllTenants(c.Request.Context())))
+	})
+	return r
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	cfg := AuthConfig{Enabled: true, Keys: []APIKey{
+		{Key: "key-a", Tenant: "team-a"},
+		{Key: "key-b", Tenant: "team-b"},
+	}}
+	r := newAuthRouter(cfg)
+
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "api key header", header: APIKeyHeader, value: "key-a", wantStatus: http.StatusOK, wantTenant: "team-a"},
+		{name: "bearer token", header: AuthorizationHeader, value: "Bearer key-b", wantStatus: http.StatusOK, wantTenant: "team-b"},
+		{name: "unknown key", header: APIKeyHeader, value: "key-c", wantStatus: http.StatusUnauthorized},
+		{name: "basic auth", header: AuthorizationHeader, value: "Basic a2V5LWE=", wantStatus: http.StatusUnauthorized},
+		{name: "no key", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/whoami", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			r.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != tt.wantTenant {
+				t.Errorf("expected tenant %q, got %q", tt.wantTenant, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_Disabled(t *testing.T) {
+	r := newAuthRouter(AuthConfig{})
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/whoami", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert: без аутентификации запросы идут от арендатора по умолчанию
+	if w.Code != http.StatusOK || w.Body.String() != domain.DefaultTenant {
+		t.Errorf("expected %d with tenant %q, got %d with %q", http.StatusOK, domain.DefaultTenant, w.Code, w.Body.String())
+	}
+}
+
+func TestAuthMiddleware_AllTenants(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  AuthConfig
+		key  string
+		want string
+	}{
+		{name: "default tenant key", cfg: AuthConfig{Enabled: true, Keys: []APIKey{{Key: "admin", Tenant: domain.DefaultTenant}}}, key: "admin", want: "true"},
+		{name: "other tenant key", cfg: AuthConfig{Enabled: true, Keys: []APIKey{{Key: "key-a", Tenant: "team-a"}}}, key: "key-a", want: "false"},
+		{name: "auth disabled", cfg: AuthConfig{}, want: "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newAuthRouter(tt.cfg)
+
+			// Act
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/all-tenants", nil)
+			req.Header.Set(APIKeyHeader, tt.key)
+			r.ServeHTTP(w, req)
+
+			// Assert: право на записи для всех арендаторов дает только ключ default
+			if w.Code != http.StatusOK || w.Body.String() != tt.want {
+				t.Errorf("expected %d with %s, got %d with %s", http.StatusOK, tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     AuthConfig
+		wantErr bool
+	}{
+		{name: "disabled", cfg: AuthConfig{}},
+		{name: "valid", cfg: AuthConfig{Enabled: true, Keys: []APIKey{{Key: "k", Tenant: "t"}}}},
+		{name: "no keys", cfg: AuthConfig{Enabled: true}, wantErr: true},
+		{name: "empty tenant", cfg: AuthConfig{Enabled: true, Keys: []APIKey{{Key: "k"}}}, wantErr: true},
+		{name: "duplicate key", cfg: AuthConfig{Enabled: true, Keys: []APIKey{{Key: "k", Tenant: "a"}, {Key: "k", Tenant: "b"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
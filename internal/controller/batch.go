@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+)
+
+// MaxBatchSize - максимум notify в одном запросе POST /notify/batch
+const MaxBatchSize = 1000
+
+// CreateBatch создает до MaxBatchSize notify одним запросом.
+// Каждый элемент проверяется отдельно, результаты возвращаются по индексам входа:
+// 201 - все notify созданы или уже были созданы теми же запросами,
+// 207 - создана часть (только без atomic), 422/409 - атомарная пачка отклонена.
+func (h *notifyHandler) CreateBatch(c *router.Context) {
+	var req CreateNotifyBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "invalid json",
+		})
+		return
+	}
+
+	if len(req.Items) == 0 {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "items is empty",
+		})
+		return
+	}
+	if len(req.Items) > MaxBatchSize {
+		c.JSON(http.StatusRequestEntityTooLarge, router.H{
+			"error": "too many items in batch",
+		})
+		return
+	}
+
+	now := time.Now()
+	results := make([]domain.BatchResult, len(req.Items))
+	notifies := make([]*domain.Notify, 0, len(req.Items))
+	valid := make([]int, 0, len(req.Items))
+	for i, item := range req.Items {
+		n, err := newNotifyFromRequest(item, item.ID, now)
+		if err != nil {
+			results[i] = domain.BatchResult{Status: domain.BatchInvalid, Err: err}
+			continue
+		}
+		notifies = append(notifies, n)
+		valid = append(valid, i)
+	}
+
+	if req.Atomic && len(valid) < len(req.Items) {
+		for _, i := range valid {
+			results[i] = domain.BatchResult{Status: domain.BatchRejected}
+		}
+		h.log.Info().Int("items", len(req.Items)).Msg("atomic batch rejected: invalid items")
+		c.JSON(http.StatusUnprocessableEntity, toBatchResponse(results))
+		return
+	}
+
+	var saved []domain.BatchResult
+	var err error
+	if len(notifies) > 0 {
+		saved, err = h.usecase.SaveBatch(c, notifies, req.Atomic)
+	}
+	if err != nil && !errors.Is(err, domain.ErrBatchConflict) {
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	for j, r := range saved {
+		results[valid[j]] = r
+	}
+
+	res := toBatchResponse(results)
+	switch {
+	case errors.Is(err, domain.ErrBatchConflict):
+		h.log.Info().Int("items", len(req.Items)).Msg("atomic batch rejected: conflicts")
+		c.JSON(http.StatusConflict, res)
+	case res.Failed > 0:
+		c.JSON(http.StatusMultiStatus, res)
+	default:
+		c.JSON(http.StatusCreated, res)
+	}
+}
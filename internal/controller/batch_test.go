@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"go.uber.org/mock/gomock"
+)
+
+func newBatchRouter(t *testing.T) (*router.Router, *mocks.MockNotifyUsecase) {
+	ctrl := gomock.NewController(t)
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	NewNotifyHandler(mockUsecase, log.New()).Register(r)
+	return r, mockUsecase
+}
+
+func postBatch(r *router.Router, req CreateNotifyBatchRequest) (*httptest.ResponseRecorder, NotifyBatchResponse) {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", NotifyBatch, bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, httpReq)
+
+	var res NotifyBatchResponse
+	json.Unmarshal(w.Body.Bytes(), &res)
+	return w, res
+}
+
+func batchItem(scheduledAt time.Time) CreateNotifyRequest {
+	return CreateNotifyRequest{
+		Payload:     json.RawMessage(`"hello"`),
+		Target:      "test@example.com",
+		Channel:     "email",
+		ScheduledAt: scheduledAt,
+	}
+}
+
+func TestNotifyHandler_CreateBatch_Success(t *testing.T) {
+	r, mockUsecase := newBatchRouter(t)
+	future := time.Now().Add(time.Hour)
+	keyed := batchItem(future)
+	keyed.ID = "key-1"
+
+	// Expect: обе записи уходят в usecase, ключ берется из id элемента
+	mockUsecase.EXPECT().
+		SaveBatch(gomock.Any(), gomock.Any(), false).
+		DoAndReturn(func(_ any, notifies []*domain.Notify, _ bool) ([]domain.BatchResult, error) {
+			if len(notifies) != 2 || notifies[1].IdempotencyKey != "key-1" {
+				t.Errorf("unexpected notifies %+v", notifies)
+			}
+			return []domain.BatchResult{
+				{ID: notifies[0].ID, Status: domain.BatchCreated},
+				{ID: "original-id", Status: domain.BatchReplayed},
+			}, nil
+		}).
+		Times(1)
+
+	// Act
+	w, res := postBatch(r, CreateNotifyBatchRequest{Items: []CreateNotifyRequest{batchItem(future), keyed}})
+
+	// Assert
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if res.Created != 1 || res.Failed != 0 || len(res.Items) != 2 {
+		t.Errorf("unexpected response %+v", res)
+	}
+	if res.Items[1].Index != 1 || res.Items[1].ID != "original-id" || res.Items[1].Status != domain.BatchReplayed {
+		t.Errorf("expected replayed second item, got %+v", res.Items[1])
+	}
+}
+
+func TestNotifyHandler_CreateBatch_PartialInvalid(t *testing.T) {
+	r, mockUsecase := newBatchRouter(t)
+	future := time.Now().Add(time.Hour)
+
+	// Expect: в usecase уходит только корректный элемент
+	mockUsecase.EXPECT().
+		SaveBatch(gomock.Any(), gomock.Len(1), false).
+		DoAndReturn(func(_ any, notifies []*domain.Notify, _ bool) ([]domain.BatchResult, error) {
+			return []domain.BatchResult{{ID: notifies[0].ID, Status: domain.BatchCreated}}, nil
+		}).
+		Times(1)
+
+	// Act
+	w, res := postBatch(r, CreateNotifyBatchRequest{
+		Items: []CreateNotifyRequest{batchItem(time.Now().Add(-time.Hour)), batchItem(future)},
+	})
+
+	// Assert
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("expected status %d, got %d", http.StatusMultiStatus, w.Code)
+	}
+	if res.Items[0].Status != domain.BatchInvalid || res.Items[0].Error != errScheduledInPast.Error() {
+		t.Errorf("expected invalid first item, got %+v", res.Items[0])
+	}
+	if res.Items[1].Status != domain.BatchCreated || res.Items[1].ID == "" {
+		t.Errorf("expected created second item, got %+v", res.Items[1])
+	}
+	if res.Created != 1 || res.Failed != 1 {
+		t.Errorf("expected 1 created and 1 failed, got %+v", res)
+	}
+}
+
+func TestNotifyHandler_CreateBatch_AtomicInvalid(t *testing.T) {
+	r, mockUsecase := newBatchRouter(t)
+
+	// Expect: атомарная пачка с ошибкой валидации в usecase не попадает
+	mockUsecase.EXPECT().SaveBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Act
+	w, res := postBatch(r, CreateNotifyBatchRequest{
+		Atomic: true,
+		Items:  []CreateNotifyRequest{batchItem(time.Now().Add(time.Hour)), batchItem(time.Now().Add(-time.Hour))},
+	})
+
+	// Assert
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+	if res.Items[0].Status != domain.BatchRejected || res.Items[1].Status != domain.BatchInvalid {
+		t.Errorf("expected rejected and invalid, got %+v", res.Items)
+	}
+}
+
+func TestNotifyHandler_CreateBatch_AtomicConflict(t *testing.T) {
+	r, mockUsecase := newBatchRouter(t)
+
+	// Expect: usecase отклоняет пачку из-за конфликта
+	mockUsecase.EXPECT().
+		SaveBatch(gomock.Any(), gomock.Any(), true).
+		Return([]domain.BatchResult{
+			{ID: "a", Status: domain.BatchRejected},
+			{ID: "b", Status: domain.BatchConflict, Err: domain.ErrIdempotencyConflict},
+		}, domain.ErrBatchConflict).
+		Times(1)
+
+	// Act
+	future := time.Now().Add(time.Hour)
+	w, res := postBatch(r, CreateNotifyBatchRequest{Atomic: true, Items: []CreateNotifyRequest{batchItem(future), batchItem(future)}})
+
+	// Assert
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	if res.Items[0].ID != "" || res.Items[1].ID != "b" || res.Items[1].Error == "" {
+		t.Errorf("unexpected items %+v", res.Items)
+	}
+	if res.Created != 0 || res.Failed != 2 {
+		t.Errorf("expected nothing created, got %+v", res)
+	}
+}
+
+func TestNotifyHandler_CreateBatch_Limits(t *testing.T) {
+	r, mockUsecase := newBatchRouter(t)
+	mockUsecase.EXPECT().SaveBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	tooMany := make([]CreateNotifyRequest, MaxBatchSize+1)
+	for _, tc := range []struct {
+		items []CreateNotifyRequest
+		code  int
+	}{
+		{nil, http.StatusBadRequest},
+		{tooMany, http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(fmt.Sprint(len(tc.items)), func(t *testing.T) {
+			// Act
+			w, _ := postBatch(r, CreateNotifyBatchRequest{Items: tc.items})
+
+			// Assert
+			if w.Code != tc.code {
+				t.Errorf("expected status %d, got %d", tc.code, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+)
+
+// ListDeadLetters принимает те же фильтры, что и GET /notify, кроме status
+func (h *notifyHandler) ListDeadLetters(c *router.Context) {
+	filter, err := parseListFilter(c)
+	if err != nil {
+		h.log.Info().Err(err).Msg("invalid dead letters filter")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	page, err := h.usecase.ListDeadLetters(c, filter)
+	if err != nil {
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeadLetterListResponse(page))
+}
+
+func (h *notifyHandler) Retry(c *router.Context) {
+	id := c.Param("id")
+	if err := uuid.Parse(id); err != nil {
+		h.log.Error().Err(err).Msg("wrong ID format")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "cannot parse ID",
+		})
+		return
+	}
+
+	notify, err := h.usecase.Retry(c, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.log.Error().Err(err).Msg("not found notify")
+			c.JSON(http.StatusNotFound, router.H{
+				"error": "not found notify",
+			})
+			return
+		}
+		if errors.Is(err, domain.ErrNotifyNotRetryable) {
+			h.log.Info().Str("id", id).Msg("notify is not failed")
+			c.JSON(http.StatusConflict, router.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(notify))
+}
+
+// ReplayDeadLetters возвращает в очередь все failed notify, подходящие под фильтр в query
+func (h *notifyHandler) ReplayDeadLetters(c *router.Context) {
+	filter, err := parseListFilter(c)
+	if err != nil {
+		h.log.Info().Err(err).Msg("invalid replay filter")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	count, err := h.usecase.Replay(c, filter)
+	if err != nil {
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, router.H{"replayed": count})
+}
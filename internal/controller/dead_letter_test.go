@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNotifyHandler_ListDeadLetters_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	lastErr := "smtp: 421 service not available"
+	page := &domain.DeadLetterPage{
+		Items: []*domain.DeadLetter{{
+			Notify: &domain.Notify{ID: "id-1", Channel: "email", Status: domain.StatusFailed, LastError: &lastErr},
+			Errors: []domain.ErrorRecord{
+				{At: time.Now().Add(-time.Minute), Attempt: 1, Error: "timeout"},
+				{At: time.Now(), Attempt: 2, Error: lastErr},
+			},
+		}},
+	}
+
+	// Expect: фильтр по каналу передан в usecase
+	mockUsecase.EXPECT().
+		ListDeadLetters(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, f domain.NotifyFilter) (*domain.DeadLetterPage, error) {
+			if f.Channel != "email" {
+				t.Errorf("expected channel filter email, got %q", f.Channel)
+			}
+			return page, nil
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/notify/dead-letters?channel=email", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response DeadLetterListResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response.Items) != 1 || len(response.Items[0].Errors) != 2 {
+		t.Errorf("expected 1 dead letter with 2 errors, got %+v", response.Items)
+	}
+}
+
+func TestNotifyHandler_Retry_NotFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	notifyID := "550e8400-e29b-41d4-a716-446655440000"
+
+	// Expect: notify еще не в Failed
+	mockUsecase.EXPECT().
+		Retry(gomock.Any(), notifyID).
+		Return(nil, domain.ErrNotifyNotRetryable).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify/"+notifyID+"/retry", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestNotifyHandler_ReplayDeadLetters_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	// Expect: массовый повтор по каналу
+	mockUsecase.EXPECT().
+		Replay(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, f domain.NotifyFilter) (int, error) {
+			if f.Channel != "telegram" {
+				t.Errorf("expected channel filter telegram, got %q", f.Channel)
+			}
+			return 7, nil
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify/dead-letters/replay?channel=telegram", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string]int
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response["replayed"] != 7 {
+		t.Errorf("expected 7 replayed, got %v", response)
+	}
+}
@@ -2,9 +2,14 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
 )
 
 type NotifyControllerDTO struct {
@@ -26,26 +31,313 @@ type CreateNotifyRequest struct {
 	Target      string          `json:"target"`
 	Channel     string          `json:"channel"`
 	ScheduledAt time.Time       `json:"scheduled_at"`
+
+	RetryPolicy    *RetryPolicyRequest    `json:"retry_policy,omitempty"`
+	DeliveryWindow *DeliveryWindowRequest `json:"delivery_window,omitempty"`
+
+	// RecipientID - адресация получателя из реестра вместо target и channel.
+	// Channels - порядок перебора каналов, пусто - предпочтения получателя
+	RecipientID string   `json:"recipient_id,omitempty"`
+	Channels    []string `json:"channels,omitempty"`
+
+	// Category - категория рассылки для списка подавления и ссылки отписки
+	Category string `json:"category,omitempty"`
+}
+
+// PatchNotifyRequest - изменение Pending notify, отсутствующие поля не меняются.
+// Version - ожидаемая версия, если клиент не передал заголовок If-Match
+type PatchNotifyRequest struct {
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Target      *string         `json:"target,omitempty"`
+	Channel     *string         `json:"channel,omitempty"`
+	ScheduledAt *time.Time      `json:"scheduled_at,omitempty"`
+	Version     *int            `json:"version,omitempty"`
+}
+
+// CreateNotifyBatchRequest - пакетное создание. Ключ идемпотентности элемента - его поле id,
+// Atomic - создать все notify или ни одного
+type CreateNotifyBatchRequest struct {
+	Items  []CreateNotifyRequest `json:"items"`
+	Atomic bool                  `json:"atomic"`
+}
+
+type BatchItemResponse struct {
+	Index  int                    `json:"index"`
+	ID     string                 `json:"id,omitempty"`
+	Status domain.BatchItemStatus `json:"status"`
+	Error  string                 `json:"error,omitempty"`
 }
 
+type NotifyBatchResponse struct {
+	Items   []BatchItemResponse `json:"items"`
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+}
+
+// RetryPolicyRequest - политика повторов notify, задержки в формате Go duration ("30s", "5m")
+type RetryPolicyRequest struct {
+	Kind        string   `json:"kind"`
+	MaxAttempts int      `json:"max_attempts,omitempty"`
+	Delay       string   `json:"delay,omitempty"`
+	MaxDelay    string   `json:"max_delay,omitempty"`
+	Multiplier  float64  `json:"multiplier,omitempty"`
+	Jitter      float64  `json:"jitter,omitempty"`
+	Schedule    []string `json:"schedule,omitempty"`
+	MaxAge      string   `json:"max_age,omitempty"`
+}
+
+// DeliveryWindowRequest - окно доставки: время HH:MM по часовому поясу IANA,
+// дни недели - mon..sun (пусто - каждый день). Так же окно отдается в ответах
+type DeliveryWindowRequest struct {
+	Timezone string   `json:"timezone"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Weekdays []string `json:"weekdays,omitempty"`
+}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
 type NotifyResponse struct {
 	ID          string        `json:"id"`
+	Target      string        `json:"target"`
+	Channel     string        `json:"channel"`
 	Status      domain.Status `json:"status"`
 	ScheduledAt time.Time     `json:"scheduled_at"`
 	CreatedAt   time.Time     `json:"created_at"`
 	RetryCount  int           `json:"retry_count"`
 	LastError   *string       `json:"last_error,omitempty"`
+	Version     int           `json:"version"`
+
+	// SendAt - когда notify будет отправлен с учетом окна доставки
+	SendAt         time.Time              `json:"send_at"`
+	DeliveryWindow *DeliveryWindowRequest `json:"delivery_window,omitempty"`
+
+	RecipientID string         `json:"recipient_id,omitempty"`
+	Fallbacks   []RouteRequest `json:"fallbacks,omitempty"`
+	Category    string         `json:"category,omitempty"`
+}
+
+// RouteRequest - канал и адрес доставки
+type RouteRequest struct {
+	Channel string `json:"channel"`
+	Target  string `json:"target"`
+}
+
+type NotifyListResponse struct {
+	Items      []NotifyResponse `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
 }
 
 func toResponse(n *domain.Notify) NotifyResponse {
 	return NotifyResponse{
 		ID:          n.ID,
+		Target:      n.Target,
+		Channel:     n.Channel,
 		Status:      n.Status,
 		ScheduledAt: n.ScheduledAt,
 		CreatedAt:   n.CreatedAt,
 		RetryCount:  n.RetryCount,
 		LastError:   n.LastError,
+		Version:     n.Version,
+
+		SendAt:         n.SendAt(),
+		DeliveryWindow: toDeliveryWindowResponse(n.DeliveryWindow),
+
+		RecipientID: n.RecipientID,
+		Fallbacks:   toRoutesResponse(n.Fallbacks),
+		Category:    n.Category,
+	}
+}
+
+func toRoutesResponse(routes []domain.Route) []RouteRequest {
+	var res []RouteRequest
+	for _, r := range routes {
+		res = append(res, RouteRequest{Channel: r.Channel, Target: r.Target})
 	}
+	return res
+}
+
+func toDeliveryWindowResponse(w *domain.DeliveryWindow) *DeliveryWindowRequest {
+	if w == nil {
+		return nil
+	}
+	res := &DeliveryWindowRequest{Timezone: w.Timezone, Start: w.Start.String(), End: w.End.String()}
+	for _, d := range w.Weekdays {
+		res.Weekdays = append(res.Weekdays, weekdayNames[d])
+	}
+	return res
+}
+
+func toListResponse(page *domain.NotifyPage) NotifyListResponse {
+	items := make([]NotifyResponse, 0, len(page.Items))
+	for _, n := range page.Items {
+		items = append(items, toResponse(n))
+	}
+	return NotifyListResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+	}
+}
+
+func patchRequestToDomain(req PatchNotifyRequest, now time.Time) (domain.NotifyPatch, error) {
+	patch := domain.NotifyPatch{
+		Target:      req.Target,
+		Channel:     req.Channel,
+		ScheduledAt: req.ScheduledAt,
+	}
+	if req.Payload != nil {
+		if string(req.Payload) == "null" {
+			return patch, errors.New("payload cannot be null")
+		}
+		patch.Payload = req.Payload
+	}
+
+	if patch.Empty() {
+		return patch, errors.New("nothing to update")
+	}
+	if req.Target != nil && *req.Target == "" {
+		return patch, errors.New("target cannot be empty")
+	}
+	if req.Channel != nil && *req.Channel == "" {
+		return patch, errors.New("channel cannot be empty")
+	}
+	if req.ScheduledAt != nil && req.ScheduledAt.Before(now) {
+		return patch, errScheduledInPast
+	}
+	return patch, nil
+}
+
+func toBatchResponse(results []domain.BatchResult) NotifyBatchResponse {
+	res := NotifyBatchResponse{Items: make([]BatchItemResponse, 0, len(results))}
+	for i, r := range results {
+		item := BatchItemResponse{Index: i, Status: r.Status}
+		if r.OK() || r.Status == domain.BatchConflict {
+			item.ID = r.ID
+		}
+		if r.Err != nil {
+			item.Error = r.Err.Error()
+		}
+		switch {
+		case r.Status == domain.BatchCreated:
+			res.Created++
+		case !r.OK():
+			res.Failed++
+		}
+		res.Items = append(res.Items, item)
+	}
+	return res
+}
+
+func createRequestToDomain(req CreateNotifyRequest, idempotencyKey string) (*domain.Notify, error) {
+	n := domain.NewNotify()
+	n.Payload = req.Payload
+	n.Target = req.Target
+	n.Channel = req.Channel
+	n.Status = domain.StatusPending
+	n.ScheduledAt = req.ScheduledAt
+	n.IdempotencyKey = idempotencyKey
+
+	if err := domain.ValidateCategory(req.Category); err != nil {
+		return nil, err
+	}
+	n.Category = req.Category
+
+	if req.RetryPolicy != nil {
+		policy, err := retryPolicyRequestToDomain(*req.RetryPolicy)
+		if err != nil {
+			return nil, err
+		}
+		n.RetryPolicy = policy
+	}
+
+	if req.DeliveryWindow != nil {
+		window, err := deliveryWindowRequestToDomain(*req.DeliveryWindow)
+		if err != nil {
+			return nil, err
+		}
+		n.DeliveryWindow = window
+	}
+
+	if req.RecipientID != "" {
+		if req.Target != "" || req.Channel != "" {
+			return nil, errRecipientWithTarget
+		}
+		if err := uuid.Parse(req.RecipientID); err != nil {
+			return nil, fmt.Errorf("%w: cannot parse recipient_id", domain.ErrInvalidRecipient)
+		}
+		n.RecipientID = req.RecipientID
+		n.Channels = req.Channels
+	} else if len(req.Channels) > 0 {
+		return nil, errChannelsWithoutRecipient
+	}
+
+	return n, nil
+}
+
+func deliveryWindowRequestToDomain(req DeliveryWindowRequest) (*domain.DeliveryWindow, error) {
+	w := &domain.DeliveryWindow{Timezone: req.Timezone}
+
+	var err error
+	if w.Start, err = domain.ParseTimeOfDay(req.Start); err != nil {
+		return nil, err
+	}
+	if w.End, err = domain.ParseTimeOfDay(req.End); err != nil {
+		return nil, err
+	}
+
+	for _, name := range req.Weekdays {
+		day := slices.Index(weekdayNames, strings.ToLower(name))
+		if day < 0 {
+			return nil, fmt.Errorf("%w: unknown weekday %q", domain.ErrInvalidDeliveryWindow, name)
+		}
+		w.Weekdays = append(w.Weekdays, time.Weekday(day))
+	}
+
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func retryPolicyRequestToDomain(req RetryPolicyRequest) (*domain.RetryPolicy, error) {
+	p := &domain.RetryPolicy{
+		Kind:        domain.RetryKind(req.Kind),
+		MaxAttempts: req.MaxAttempts,
+		Multiplier:  req.Multiplier,
+		Jitter:      req.Jitter,
+	}
+
+	for _, d := range []struct {
+		field string
+		raw   string
+		dst   *time.Duration
+	}{
+		{"delay", req.Delay, &p.Delay},
+		{"max_delay", req.MaxDelay, &p.MaxDelay},
+		{"max_age", req.MaxAge, &p.MaxAge},
+	} {
+		if d.raw == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s %q", domain.ErrInvalidRetryPolicy, d.field, d.raw)
+		}
+		*d.dst = v
+	}
+
+	for _, raw := range req.Schedule {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid schedule delay %q", domain.ErrInvalidRetryPolicy, raw)
+		}
+		p.Schedule = append(p.Schedule, d)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 func toDomain(dto NotifyControllerDTO) *domain.Notify {
@@ -62,3 +354,285 @@ func toDomain(dto NotifyControllerDTO) *domain.Notify {
 		LastError:   dto.LastError,
 	}
 }
+
+type CreateScheduleRequest struct {
+	Payload  json.RawMessage `json:"payload"`
+	Target   string          `json:"target"`
+	Channel  string          `json:"channel"`
+	Cron     string          `json:"cron,omitempty"`
+	Interval string          `json:"interval,omitempty"` // Go duration, например "24h"
+	Timezone string          `json:"timezone,omitempty"`
+	StartAt  time.Time       `json:"start_at,omitempty"`
+	EndAt    *time.Time      `json:"end_at,omitempty"`
+}
+
+type ScheduleResponse struct {
+	ID        string                `json:"id"`
+	Target    string                `json:"target"`
+	Channel   string                `json:"channel"`
+	Cron      string                `json:"cron,omitempty"`
+	Interval  string                `json:"interval,omitempty"`
+	Timezone  string                `json:"timezone"`
+	StartAt   time.Time             `json:"start_at"`
+	EndAt     *time.Time            `json:"end_at,omitempty"`
+	NextRunAt *time.Time            `json:"next_run_at,omitempty"`
+	Status    domain.ScheduleStatus `json:"status"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+func createScheduleRequestToDomain(req CreateScheduleRequest) (*domain.Schedule, error) {
+	s := domain.NewSchedule()
+	s.Payload = req.Payload
+	s.Target = req.Target
+	s.Channel = req.Channel
+	s.CronExpr = req.Cron
+	s.Timezone = req.Timezone
+	s.StartAt = req.StartAt
+	s.EndAt = req.EndAt
+
+	if req.Interval != "" {
+		interval, err := time.ParseDuration(req.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid interval %q", domain.ErrInvalidSchedule, req.Interval)
+		}
+		s.Interval = interval
+	}
+
+	return s, nil
+}
+
+func toScheduleResponse(s *domain.Schedule) ScheduleResponse {
+	res := ScheduleResponse{
+		ID:        s.ID,
+		Target:    s.Target,
+		Channel:   s.Channel,
+		Cron:      s.CronExpr,
+		Timezone:  s.Timezone,
+		StartAt:   s.StartAt,
+		EndAt:     s.EndAt,
+		NextRunAt: s.NextRunAt,
+		Status:    s.Status,
+		CreatedAt: s.CreatedAt,
+	}
+	if s.Interval > 0 {
+		res.Interval = s.Interval.String()
+	}
+	return res
+}
+
+// RecipientRequest - получатель: контакты по каналам, предпочтительный порядок каналов,
+// каналы, от которых он отказался, и окно доставки по умолчанию
+type RecipientRequest struct {
+	Name           string                 `json:"name,omitempty"`
+	Contacts       map[string]string      `json:"contacts"`
+	Channels       []string               `json:"channels,omitempty"`
+	OptOuts        []string               `json:"opt_outs,omitempty"`
+	DeliveryWindow *DeliveryWindowRequest `json:"delivery_window,omitempty"`
+}
+
+type RecipientResponse struct {
+	ID             string                 `json:"id"`
+	Name           string                 `json:"name,omitempty"`
+	Contacts       map[string]string      `json:"contacts"`
+	Channels       []string               `json:"channels,omitempty"`
+	OptOuts        []string               `json:"opt_outs,omitempty"`
+	DeliveryWindow *DeliveryWindowRequest `json:"delivery_window,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+func recipientRequestToDomain(req RecipientRequest) (*domain.Recipient, error) {
+	r := domain.NewRecipient()
+	r.Name = req.Name
+	r.Contacts = req.Contacts
+	r.Channels = req.Channels
+	r.OptOuts = req.OptOuts
+
+	if req.DeliveryWindow != nil {
+		window, err := deliveryWindowRequestToDomain(*req.DeliveryWindow)
+		if err != nil {
+			return nil, err
+		}
+		r.DeliveryWindow = window
+	}
+	return r, nil
+}
+
+func toRecipientResponse(r *domain.Recipient) RecipientResponse {
+	return RecipientResponse{
+		ID:             r.ID,
+		Name:           r.Name,
+		Contacts:       r.Contacts,
+		Channels:       r.Channels,
+		OptOuts:        r.OptOuts,
+		DeliveryWindow: toDeliveryWindowResponse(r.DeliveryWindow),
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
+
+// SuppressionRequest - запрет отправки на адрес. AllTenants - для всех арендаторов
+// (только арендатор default), иначе для арендатора ключа
+type SuppressionRequest struct {
+	Channel    string `json:"channel"`
+	Target     string `json:"target"`
+	Category   string `json:"category,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	AllTenants bool   `json:"all_tenants,omitempty"`
+}
+
+type SuppressionResponse struct {
+	ID         string    `json:"id"`
+	Channel    string    `json:"channel"`
+	Target     string    `json:"target"`
+	Category   string    `json:"category,omitempty"`
+	Reason     string    `json:"reason"`
+	AllTenants bool      `json:"all_tenants"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func suppressionRequestToDomain(req SuppressionRequest) *domain.Suppression {
+	s := domain.NewSuppression()
+	s.Channel = req.Channel
+	s.Target = req.Target
+	s.Category = req.Category
+	if req.Reason != "" {
+		s.Reason = domain.SuppressionReason(req.Reason)
+	}
+	return s
+}
+
+func toSuppressionResponse(s *domain.Suppression) SuppressionResponse {
+	return SuppressionResponse{
+		ID:         s.ID,
+		Channel:    s.Channel,
+		Target:     s.Target,
+		Category:   s.Category,
+		Reason:     string(s.Reason),
+		AllTenants: s.TenantID == "",
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+type TemplateRequest struct {
+	Name    string `json:"name"`
+	Channel string `json:"channel"`
+	Subject string `json:"subject,omitempty"`
+	Text    string `json:"text,omitempty"`
+	HTML    string `json:"html,omitempty"`
+}
+
+type TemplateResponse struct {
+	Name      string    `json:"name"`
+	Channel   string    `json:"channel"`
+	Version   int       `json:"version"`
+	Subject   string    `json:"subject,omitempty"`
+	Text      string    `json:"text,omitempty"`
+	HTML      string    `json:"html,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func templateRequestToDomain(req TemplateRequest) *domain.Template {
+	t := domain.NewTemplate()
+	t.Name = req.Name
+	t.Channel = req.Channel
+	t.Subject = req.Subject
+	t.Text = req.Text
+	t.HTML = req.HTML
+	return t
+}
+
+func toTemplateResponse(t *domain.Template) TemplateResponse {
+	return TemplateResponse{
+		Name:      t.Name,
+		Channel:   t.Channel,
+		Version:   t.Version,
+		Subject:   t.Subject,
+		Text:      t.Text,
+		HTML:      t.HTML,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func toTemplateListResponse(templates []*domain.Template) []TemplateResponse {
+	res := make([]TemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		res = append(res, toTemplateResponse(t))
+	}
+	return res
+}
+
+type DeadLetterResponse struct {
+	NotifyResponse
+	Errors []domain.ErrorRecord `json:"errors"`
+}
+
+type DeadLetterListResponse struct {
+	Items      []DeadLetterResponse `json:"items"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+func toDeadLetterListResponse(page *domain.DeadLetterPage) DeadLetterListResponse {
+	items := make([]DeadLetterResponse, 0, len(page.Items))
+	for _, dl := range page.Items {
+		errs := dl.Errors
+		if errs == nil {
+			errs = []domain.ErrorRecord{}
+		}
+		items = append(items, DeadLetterResponse{
+			NotifyResponse: toResponse(dl.Notify),
+			Errors:         errs,
+		})
+	}
+	return DeadLetterListResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+	}
+}
+
+type AttemptResponse struct {
+	ID                string    `json:"id"`
+	Kind              string    `json:"kind"`
+	WorkerID          string    `json:"worker_id"`
+	Channel           string    `json:"channel"`
+	Outcome           string    `json:"outcome"`
+	Error             string    `json:"error,omitempty"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	ProviderResponse  string    `json:"provider_response,omitempty"`
+	LatencyMs         int64     `json:"latency_ms"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type AttemptListResponse struct {
+	Items []AttemptResponse `json:"items"`
+}
+
+func toAttemptListResponse(attempts []*domain.Attempt) AttemptListResponse {
+	items := make([]AttemptResponse, 0, len(attempts))
+	for _, a := range attempts {
+		items = append(items, AttemptResponse{
+			ID:                a.ID,
+			Kind:              string(a.Kind),
+			WorkerID:          a.WorkerID,
+			Channel:           a.Channel,
+			Outcome:           string(a.Outcome),
+			Error:             a.Error,
+			ProviderMessageID: a.ProviderMessageID,
+			ProviderResponse:  a.ProviderResponse,
+			LatencyMs:         a.Latency.Milliseconds(),
+			CreatedAt:         a.CreatedAt,
+		})
+	}
+	return AttemptListResponse{Items: items}
+}
+
+type HealthCheckResult struct {
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+type HealthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks"`
+}
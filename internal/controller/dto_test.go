@@ -58,6 +58,37 @@ func TestDTOConversion(t *testing.T) {
 	}
 }
 
+func TestToResponse_SendAt(t *testing.T) {
+	scheduledAt := time.Date(2026, time.March, 7, 12, 0, 0, 0, time.UTC) // суббота
+	n := &domain.Notify{
+		ID:          "test-id",
+		ScheduledAt: scheduledAt,
+		DeliveryWindow: &domain.DeliveryWindow{
+			Timezone: "UTC",
+			Start:    9 * 60,
+			End:      18 * 60,
+			Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		},
+	}
+
+	// Act
+	resp := toResponse(n)
+
+	// Assert: отправка переносится на понедельник, scheduled_at не меняется
+	want := time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC)
+	if !resp.SendAt.Equal(want) || !resp.ScheduledAt.Equal(scheduledAt) {
+		t.Errorf("expected send_at %v and scheduled_at %v, got %v and %v", want, scheduledAt, resp.SendAt, resp.ScheduledAt)
+	}
+	if w := resp.DeliveryWindow; w == nil || w.Start != "09:00" || w.End != "18:00" || len(w.Weekdays) != 5 || w.Weekdays[0] != "mon" {
+		t.Errorf("unexpected delivery window in response: %+v", w)
+	}
+
+	// без окна send_at совпадает с scheduled_at
+	if resp := toResponse(&domain.Notify{ScheduledAt: scheduledAt}); !resp.SendAt.Equal(scheduledAt) || resp.DeliveryWindow != nil {
+		t.Errorf("expected send_at = scheduled_at without window, got %+v", resp)
+	}
+}
+
 func TestDTO_JSONMarshaling(t *testing.T) {
 	// Проверка JSON тегов
 	req := CreateNotifyRequest{
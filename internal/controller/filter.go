@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+var statusNames = map[string]domain.Status{
+	"pending":    domain.StatusPending,
+	"in_process": domain.StatusInProcess,
+	"sent":       domain.StatusSent,
+	"failed":     domain.StatusFailed,
+	"canceled":   domain.StatusCanceled,
+	"suppressed": domain.StatusSuppressed,
+}
+
+// parseListFilter разбирает query-параметры GET /notify:
+// status (через запятую, число или имя), channel, target,
+// scheduled_from/scheduled_to, created_from/created_to (RFC 3339),
+// sort (created_at|scheduled_at), order (asc|desc), limit, offset, cursor.
+func parseListFilter(c *router.Context) (domain.NotifyFilter, error) {
+	f := domain.NotifyFilter{
+		Channel: c.Query("channel"),
+		Target:  c.Query("target"),
+		SortBy:  domain.SortByCreatedAt,
+		Desc:    true,
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultListLimit
+	}
+	f.Limit = min(limit, maxListLimit)
+
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	f.Offset = offset
+
+	if raw := c.Query("status"); raw != "" {
+		for _, s := range strings.Split(raw, ",") {
+			status, err := parseStatus(strings.TrimSpace(s))
+			if err != nil {
+				return f, err
+			}
+			f.Statuses = append(f.Statuses, status)
+		}
+	}
+
+	for param, dst := range map[string]**time.Time{
+		"scheduled_from": &f.ScheduledFrom,
+		"scheduled_to":   &f.ScheduledTo,
+		"created_from":   &f.CreatedFrom,
+		"created_to":     &f.CreatedTo,
+	} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return f, fmt.Errorf("invalid %s: expected RFC 3339 time", param)
+		}
+		*dst = &t
+	}
+
+	sort, hasSort := c.GetQuery("sort")
+	if hasSort {
+		switch domain.SortField(sort) {
+		case domain.SortByCreatedAt, domain.SortByScheduledAt:
+			f.SortBy = domain.SortField(sort)
+		default:
+			return f, fmt.Errorf("invalid sort: %s", sort)
+		}
+	}
+
+	order, hasOrder := c.GetQuery("order")
+	if hasOrder {
+		switch order {
+		case "asc":
+			f.Desc = false
+		case "desc":
+			f.Desc = true
+		default:
+			return f, fmt.Errorf("invalid order: %s", order)
+		}
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := domain.DecodeCursor(raw)
+		if err != nil {
+			return f, err
+		}
+		// курсор выдан для конкретной сортировки, смешивать их нельзя
+		if (hasSort && cursor.SortBy != f.SortBy) || (hasOrder && cursor.Desc != f.Desc) {
+			return f, fmt.Errorf("cursor does not match sort order")
+		}
+		f.SortBy = cursor.SortBy
+		f.Desc = cursor.Desc
+		f.Cursor = cursor
+	}
+
+	return f, nil
+}
+
+func parseStatus(s string) (domain.Status, error) {
+	if status, ok := statusNames[strings.ToLower(s)]; ok {
+		return status, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < int(domain.StatusPending) || n > int(domain.StatusSuppressed) {
+		return 0, fmt.Errorf("invalid status: %s", s)
+	}
+	return domain.Status(n), nil
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+)
+
+const (
+	Healthz = "/healthz" // GET
+	Readyz  = "/readyz"  // GET
+)
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+
+	healthCheckTimeout = 2 * time.Second
+)
+
+var errShuttingDown = errors.New("service is shutting down")
+
+// HealthHandler отдает liveness и readiness. Liveness проверяет внутренние циклы
+// сервиса (планировщик, консьюмер), readiness - внешние зависимости.
+// После Shutdown readiness всегда падает, чтобы оркестратор снял трафик до остановки сервера.
+type HealthHandler struct {
+	liveness     domain.HealthChecks
+	readiness    domain.HealthChecks
+	shuttingDown atomic.Bool
+	log          log.Log
+}
+
+func NewHealthHandler(liveness, readiness domain.HealthChecks, l log.Log) *HealthHandler {
+	return &HealthHandler{liveness: liveness, readiness: readiness, log: l}
+}
+
+func (h *HealthHandler) Register(router *router.Router) {
+	router.GET(Healthz, h.Healthz)
+	router.GET(Readyz, h.Readyz)
+}
+
+// Shutdown переводит readiness в failing
+func (h *HealthHandler) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *HealthHandler) Healthz(c *router.Context) {
+	h.respond(c, runHealthChecks(c, h.liveness))
+}
+
+func (h *HealthHandler) Readyz(c *router.Context) {
+	res := runHealthChecks(c, h.readiness)
+	if h.shuttingDown.Load() {
+		res.Status = healthStatusFail
+		res.Checks["shutdown"] = HealthCheckResult{Status: healthStatusFail, Error: errShuttingDown.Error()}
+	}
+	h.respond(c, res)
+}
+
+func (h *HealthHandler) respond(c *router.Context, res HealthResponse) {
+	if res.Status != healthStatusOK {
+		h.log.Warn().Any("checks", res.Checks).Str("path", c.FullPath()).Msg("health check failed")
+		c.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// runHealthChecks выполняет проверки параллельно, каждую со своим таймаутом
+func runHealthChecks(ctx context.Context, checks domain.HealthChecks) HealthResponse {
+	res := HealthResponse{
+		Status: healthStatusOK,
+		Checks: make(map[string]HealthCheckResult, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(checkCtx)
+			result := HealthCheckResult{Status: healthStatusOK, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = healthStatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			res.Checks[name] = result
+			if err != nil {
+				res.Status = healthStatusFail
+			}
+		}()
+	}
+	wg.Wait()
+
+	return res
+}
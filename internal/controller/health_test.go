@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+)
+
+func okCheck(context.Context) error { return nil }
+
+func TestHealthHandler_Readyz_OK(t *testing.T) {
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewHealthHandler(nil, domain.HealthChecks{
+		"postgres": okCheck,
+		"redis":    okCheck,
+	}, log.New())
+	handler.Register(r)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", Readyz, nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response HealthResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Status != "ok" || len(response.Checks) != 2 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestHealthHandler_Readyz_DependencyDown(t *testing.T) {
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewHealthHandler(nil, domain.HealthChecks{
+		"postgres": okCheck,
+		"redis":    func(context.Context) error { return errors.New("connection refused") },
+	}, log.New())
+	handler.Register(r)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", Readyz, nil)
+	r.ServeHTTP(w, req)
+
+	// Assert: 503 и причина в деталях упавшей проверки
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	var response HealthResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Status != "fail" {
+		t.Errorf("expected fail status, got %q", response.Status)
+	}
+	if response.Checks["postgres"].Status != "ok" {
+		t.Errorf("expected postgres ok, got %+v", response.Checks["postgres"])
+	}
+	if c := response.Checks["redis"]; c.Status != "fail" || c.Error != "connection refused" {
+		t.Errorf("expected redis failure with error, got %+v", c)
+	}
+}
+
+func TestHealthHandler_Readyz_ShuttingDown(t *testing.T) {
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewHealthHandler(
+		domain.HealthChecks{"consumer": okCheck},
+		domain.HealthChecks{"postgres": okCheck},
+		log.New(),
+	)
+	handler.Register(r)
+
+	handler.Shutdown()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", Readyz, nil)
+	r.ServeHTTP(w, req)
+
+	// Assert: readiness падает, хотя зависимости в порядке
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	// Assert: liveness при этом не затрагивается
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", Healthz, nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected liveness status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestHealthHandler_Healthz_Fail(t *testing.T) {
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewHealthHandler(domain.HealthChecks{
+		"consumer": func(context.Context) error { return errors.New("consumer is not running") },
+	}, nil, log.New())
+	handler.Register(r)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", Healthz, nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/internal/domain"
@@ -13,8 +14,29 @@ import (
 )
 
 const (
-	Notify   = "/notify"     // POST, GET
-	NotifyID = "/notify/:id" // GET, DELETE
+	Notify            = "/notify"                     // POST, GET
+	NotifyBatch       = "/notify/batch"               // POST
+	NotifyID          = "/notify/:id"                 // GET, PATCH, DELETE
+	NotifyCancel      = "/notify/:id/cancel"          // POST
+	NotifyRetry       = "/notify/:id/retry"           // POST
+	NotifyAttempts    = "/notify/:id/attempts"        // GET
+	DeadLetters       = "/notify/dead-letters"        // GET
+	DeadLettersReplay = "/notify/dead-letters/replay" // POST
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	ETagHeader           = "ETag"
+	IfMatchHeader        = "If-Match"
+	maxIdempotencyKeyLen = 255
+)
+
+var (
+	errIdempotencyKeyTooLong = errors.New("idempotency key is too long")
+	errScheduledInPast       = errors.New("scheduled_at in the past")
+
+	errRecipientWithTarget      = errors.New("recipient_id cannot be combined with target or channel")
+	errChannelsWithoutRecipient = errors.New("channels require recipient_id")
 )
 
 type notifyHandler struct {
@@ -28,33 +50,74 @@ func NewNotifyHandler(u domain.NotifyUsecase, l log.Log) router.Handler {
 
 func (h *notifyHandler) Register(router *router.Router) {
 	router.POST(Notify, h.Create)
+	router.POST(NotifyBatch, h.CreateBatch)
 	router.GET(NotifyID, h.Get)
+	router.PATCH(NotifyID, h.Update)
 	router.DELETE(NotifyID, h.Delete)
+	router.POST(NotifyCancel, h.Cancel)
 	router.GET(Notify, h.List)
+	router.GET(DeadLetters, h.ListDeadLetters)
+	router.POST(DeadLettersReplay, h.ReplayDeadLetters)
+	router.POST(NotifyRetry, h.Retry)
+	router.GET(NotifyAttempts, h.Attempts)
 }
 
 func (h *notifyHandler) Create(c *router.Context) {
-	var dto NotifyControllerDTO
-	if err := c.ShouldBindJSON(&dto); err != nil {
+	var req CreateNotifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, router.H{
 			"error": "invalid json",
 		})
 		return
 	}
 
-	dto.ID = uuid.New()
+	// ключ идемпотентности берем из заголовка, иначе из поля id тела запроса
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" {
+		key = req.ID
+	}
 
-	if dto.ScheduledAt.Before(time.Now()) {
-		h.log.Info().Time("scheduled_at", dto.ScheduledAt).Msg("wrong time in scheduled at")
-		c.JSON(http.StatusUnprocessableEntity, router.H{
-			"error": "scheduled_at in the past",
+	n, err := newNotifyFromRequest(req, key, time.Now())
+	if err != nil {
+		h.log.Info().Err(err).Msg("invalid create request")
+		status := http.StatusUnprocessableEntity
+		if errors.Is(err, errIdempotencyKeyTooLong) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, router.H{
+			"error": err.Error(),
 		})
 		return
 	}
 
-	n := toDomain(dto)
 	id, err := h.usecase.Save(c, n)
 	if err != nil {
+		if errors.Is(err, domain.ErrIdempotentReplay) {
+			h.log.Info().Str("id", id).Str("idempotency_key", key).Msg("idempotent replay of create request")
+			c.JSON(http.StatusOK, router.H{"id": id})
+			return
+		}
+		if errors.Is(err, domain.ErrIdempotencyConflict) {
+			h.log.Info().Str("idempotency_key", key).Msg("idempotency key reused with different body")
+			c.JSON(http.StatusUnprocessableEntity, router.H{
+				"error": "idempotency key already used with different request",
+			})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidEmailPayload) {
+			h.log.Info().Err(err).Msg("invalid create request")
+			c.JSON(http.StatusUnprocessableEntity, router.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, domain.ErrRecipientNotFound) || errors.Is(err, domain.ErrNoRoute) {
+			h.log.Info().Err(err).Msg("cannot route notify to recipient")
+			c.JSON(http.StatusUnprocessableEntity, router.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		if errors.Is(err, domain.ErrNotifyAlreadyExists) {
 			h.log.Error().Err(err).Msg("notify already exists")
 			c.JSON(http.StatusConflict, router.H{
@@ -72,6 +135,17 @@ func (h *notifyHandler) Create(c *router.Context) {
 	c.JSON(http.StatusCreated, router.H{"id": id})
 }
 
+// newNotifyFromRequest проверяет запрос создания и собирает из него notify
+func newNotifyFromRequest(req CreateNotifyRequest, key string, now time.Time) (*domain.Notify, error) {
+	if len(key) > maxIdempotencyKeyLen {
+		return nil, errIdempotencyKeyTooLong
+	}
+	if req.ScheduledAt.Before(now) {
+		return nil, errScheduledInPast
+	}
+	return createRequestToDomain(req, key)
+}
+
 func (h *notifyHandler) Get(c *router.Context) {
 	id := c.Param("id")
 	if err := uuid.Parse(id); err != nil {
@@ -100,9 +174,177 @@ func (h *notifyHandler) Get(c *router.Context) {
 
 	res := toResponse(notify)
 
+	c.Header(ETagHeader, etag(notify.Version))
 	c.JSON(http.StatusOK, res)
 }
 
+// Update меняет scheduled_at, payload, target или channel, пока notify в статусе Pending.
+// Ожидаемая версия берется из If-Match (ETag из GET) или поля version тела
+func (h *notifyHandler) Update(c *router.Context) {
+	id := c.Param("id")
+	if err := uuid.Parse(id); err != nil {
+		h.log.Error().Err(err).Msg("wrong ID format")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "cannot parse ID",
+		})
+		return
+	}
+
+	var req PatchNotifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "invalid json",
+		})
+		return
+	}
+
+	version, err := parseIfMatch(c.GetHeader(IfMatchHeader))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if req.Version != nil {
+		if version != 0 && version != *req.Version {
+			c.JSON(http.StatusBadRequest, router.H{
+				"error": "If-Match and version do not match",
+			})
+			return
+		}
+		version = *req.Version
+	}
+
+	patch, err := patchRequestToDomain(req, time.Now())
+	if err != nil {
+		h.log.Info().Err(err).Msg("invalid update request")
+		c.JSON(http.StatusUnprocessableEntity, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	n, err := h.usecase.Update(c, id, patch, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.JSON(http.StatusNotFound, router.H{
+				"error": "not found notify",
+			})
+		case errors.Is(err, domain.ErrInvalidEmailPayload):
+			c.JSON(http.StatusUnprocessableEntity, router.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrNotifyNotEditable):
+			c.JSON(http.StatusConflict, router.H{
+				"error": "only pending notify can be edited",
+			})
+		case errors.Is(err, domain.ErrVersionMismatch):
+			c.JSON(http.StatusPreconditionFailed, router.H{
+				"error": "notify was modified, fetch it again",
+			})
+		default:
+			h.log.Error().Err(err).Msg("internal server error")
+			c.JSON(http.StatusInternalServerError, router.H{
+				"error": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Header(ETagHeader, etag(n.Version))
+	c.JSON(http.StatusOK, toResponse(n))
+}
+
+// etag - сильный ETag notify по его версии
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// parseIfMatch разбирает If-Match с ETag из etag. Пустой заголовок и "*" - без проверки версии (0)
+func parseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	raw, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, errors.New("invalid If-Match header")
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil || version <= 0 {
+		return 0, errors.New("invalid If-Match header")
+	}
+	return version, nil
+}
+
+// Attempts возвращает историю попыток публикации и отправки notify
+func (h *notifyHandler) Attempts(c *router.Context) {
+	id := c.Param("id")
+	if err := uuid.Parse(id); err != nil {
+		h.log.Error().Err(err).Msg("wrong ID format")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "cannot parse ID",
+		})
+		return
+	}
+
+	attempts, err := h.usecase.Attempts(c, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.log.Error().Err(err).Msg("not found notify")
+			c.JSON(http.StatusNotFound, router.H{
+				"error": "not found notify",
+			})
+			return
+		}
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toAttemptListResponse(attempts))
+}
+
+func (h *notifyHandler) Cancel(c *router.Context) {
+	id := c.Param("id")
+	if err := uuid.Parse(id); err != nil {
+		h.log.Error().Err(err).Msg("wrong ID format")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "cannot parse ID",
+		})
+		return
+	}
+
+	notify, err := h.usecase.Cancel(c, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.log.Error().Err(err).Msg("not found notify")
+			c.JSON(http.StatusNotFound, router.H{
+				"error": "not found notify",
+			})
+			return
+		}
+		if errors.Is(err, domain.ErrNotifyNotCancelable) {
+			h.log.Info().Str("id", id).Msg("notify already in final status")
+			c.JSON(http.StatusConflict, router.H{
+				"error": "notify already sent or failed",
+			})
+			return
+		}
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(notify))
+}
+
 func (h *notifyHandler) Delete(c *router.Context) {
 	id := c.Param("id")
 	if err := uuid.Parse(id); err != nil {
@@ -129,15 +371,16 @@ func (h *notifyHandler) Delete(c *router.Context) {
 }
 
 func (h *notifyHandler) List(c *router.Context) {
-	limit, err := strconv.Atoi(c.Query("limit"))
+	filter, err := parseListFilter(c)
 	if err != nil {
-		limit = 50
-	}
-	offset, err := strconv.Atoi(c.Query("offset"))
-	if err != nil {
-		offset = 0
+		h.log.Info().Err(err).Msg("invalid list filter")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": err.Error(),
+		})
+		return
 	}
-	notifies, err := h.usecase.List(c, limit, offset)
+
+	page, err := h.usecase.List(c, filter)
 	if err != nil {
 		h.log.Error().Err(err).Msg("internal server error")
 		c.JSON(http.StatusInternalServerError, router.H{
@@ -146,5 +389,5 @@ func (h *notifyHandler) List(c *router.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, notifies)
+	c.JSON(http.StatusOK, toListResponse(page))
 }
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -173,6 +174,7 @@ func TestNotifyHandler_Get_Success(t *testing.T) {
 		ScheduledAt: time.Now(),
 		CreatedAt:   time.Now(),
 		RetryCount:  0,
+		Version:     3,
 	}
 
 	// Expect: получение notify
@@ -196,6 +198,9 @@ func TestNotifyHandler_Get_Success(t *testing.T) {
 	if response.ID != notifyID {
 		t.Errorf("expected notify ID %s, got %s", notifyID, response.ID)
 	}
+	if response.Version != 3 || w.Header().Get(ETagHeader) != `"3"` {
+		t.Errorf("expected version 3 and ETag \"3\", got %d and %s", response.Version, w.Header().Get(ETagHeader))
+	}
 }
 
 func TestNotifyHandler_Get_InvalidID(t *testing.T) {
@@ -323,10 +328,14 @@ func TestNotifyHandler_List_Success(t *testing.T) {
 		{ID: "id-2", Target: "user2@example.com", Status: domain.StatusSent},
 	}
 
-	// Expect: получение списка
+	// Expect: получение списка, по умолчанию limit=50, offset=0, created_at DESC
 	mockUsecase.EXPECT().
-		List(gomock.Any(), 50, 0). // Default limit=50, offset=0
-		Return(expectedNotifies, nil).
+		List(gomock.Any(), domain.NotifyFilter{
+			SortBy: domain.SortByCreatedAt,
+			Desc:   true,
+			Limit:  50,
+		}).
+		Return(&domain.NotifyPage{Items: expectedNotifies, NextCursor: "next"}, nil).
 		Times(1)
 
 	// Act
@@ -339,10 +348,13 @@ func TestNotifyHandler_List_Success(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 
-	var response []*domain.Notify
+	var response NotifyListResponse
 	json.Unmarshal(w.Body.Bytes(), &response)
-	if len(response) != len(expectedNotifies) {
-		t.Errorf("expected %d notifies, got %d", len(expectedNotifies), len(response))
+	if len(response.Items) != len(expectedNotifies) {
+		t.Errorf("expected %d notifies, got %d", len(expectedNotifies), len(response.Items))
+	}
+	if response.NextCursor != "next" {
+		t.Errorf("expected next_cursor %q, got %q", "next", response.NextCursor)
 	}
 }
 
@@ -362,8 +374,13 @@ func TestNotifyHandler_List_WithPagination(t *testing.T) {
 
 	// Expect: получение списка с параметрами
 	mockUsecase.EXPECT().
-		List(gomock.Any(), 10, 20).
-		Return(expectedNotifies, nil).
+		List(gomock.Any(), domain.NotifyFilter{
+			SortBy: domain.SortByCreatedAt,
+			Desc:   true,
+			Limit:  10,
+			Offset: 20,
+		}).
+		Return(&domain.NotifyPage{Items: expectedNotifies}, nil).
 		Times(1)
 
 	// Act
@@ -389,7 +406,7 @@ func TestNotifyHandler_List_InternalError(t *testing.T) {
 
 	// Expect: ошибка БД
 	mockUsecase.EXPECT().
-		List(gomock.Any(), 50, 0).
+		List(gomock.Any(), gomock.Any()).
 		Return(nil, errors.New("database error")).
 		Times(1)
 
@@ -403,3 +420,537 @@ func TestNotifyHandler_List_InternalError(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
 	}
 }
+
+func TestNotifyHandler_List_WithFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := &domain.Cursor{
+		SortBy: domain.SortByScheduledAt,
+		Value:  from.Add(time.Hour),
+		ID:     "id-9",
+	}
+
+	// Expect: фильтры и сортировка берутся из курсора
+	mockUsecase.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, f domain.NotifyFilter) (*domain.NotifyPage, error) {
+			if len(f.Statuses) != 2 || f.Statuses[0] != domain.StatusPending || f.Statuses[1] != domain.StatusFailed {
+				t.Errorf("unexpected statuses %v", f.Statuses)
+			}
+			if f.Channel != "email" {
+				t.Errorf("expected channel email, got %s", f.Channel)
+			}
+			if f.ScheduledFrom == nil || !f.ScheduledFrom.Equal(from) {
+				t.Errorf("unexpected scheduled_from %v", f.ScheduledFrom)
+			}
+			if f.SortBy != domain.SortByScheduledAt || f.Desc {
+				t.Errorf("expected scheduled_at ASC, got %s desc=%v", f.SortBy, f.Desc)
+			}
+			if f.Cursor == nil || f.Cursor.ID != "id-9" {
+				t.Errorf("expected cursor for id-9, got %+v", f.Cursor)
+			}
+			return &domain.NotifyPage{}, nil
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	url := "/notify?status=pending,3&channel=email&scheduled_from=" + from.Format(time.RFC3339) +
+		"&cursor=" + cursor.Encode()
+	req, _ := http.NewRequest("GET", url, nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestNotifyHandler_List_InvalidFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	for _, query := range []string{
+		"status=unknown",
+		"created_from=yesterday",
+		"sort=target",
+		"cursor=not-a-cursor",
+	} {
+		// Act
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/notify?"+query, nil)
+		r.ServeHTTP(w, req)
+
+		// Assert
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestNotifyHandler_Cancel_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	notifyID := "550e8400-e29b-41d4-a716-446655440000"
+
+	// Expect: успешная отмена
+	mockUsecase.EXPECT().
+		Cancel(gomock.Any(), notifyID).
+		Return(&domain.Notify{ID: notifyID, Status: domain.StatusCanceled}, nil).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify/"+notifyID+"/cancel", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response NotifyResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Status != domain.StatusCanceled {
+		t.Errorf("expected status %v, got %v", domain.StatusCanceled, response.Status)
+	}
+}
+
+func TestNotifyHandler_Cancel_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	notifyID := "550e8400-e29b-41d4-a716-446655440000"
+
+	// Expect: notify уже отправлено
+	mockUsecase.EXPECT().
+		Cancel(gomock.Any(), notifyID).
+		Return(nil, domain.ErrNotifyNotCancelable).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify/"+notifyID+"/cancel", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestNotifyHandler_Cancel_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	notifyID := "550e8400-e29b-41d4-a716-446655440000"
+
+	// Expect: notify не найдено
+	mockUsecase.EXPECT().
+		Cancel(gomock.Any(), notifyID).
+		Return(nil, domain.ErrNotFound).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify/"+notifyID+"/cancel", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestNotifyHandler_Create_IdempotentReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	requestBody := CreateNotifyRequest{
+		Payload:     json.RawMessage(`"test message"`),
+		Target:      "test@example.com",
+		Channel:     "email",
+		ScheduledAt: time.Now().Add(10 * time.Minute),
+	}
+
+	body, _ := json.Marshal(requestBody)
+
+	// Expect: ключ из заголовка передан в usecase, запрос уже обработан
+	mockUsecase.EXPECT().
+		Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, n *domain.Notify) (string, error) {
+			if n.IdempotencyKey != "retry-key" {
+				t.Errorf("expected idempotency key %q, got %q", "retry-key", n.IdempotencyKey)
+			}
+			return "original-id", domain.ErrIdempotentReplay
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, "retry-key")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response["id"] != "original-id" {
+		t.Errorf("expected id %s, got %s", "original-id", response["id"])
+	}
+}
+
+func TestNotifyHandler_Create_IdempotencyConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	requestBody := CreateNotifyRequest{
+		ID:          "body-key",
+		Payload:     json.RawMessage(`"another message"`),
+		Target:      "test@example.com",
+		Channel:     "email",
+		ScheduledAt: time.Now().Add(10 * time.Minute),
+	}
+
+	body, _ := json.Marshal(requestBody)
+
+	// Expect: ключ из поля id уже использован с другим телом
+	mockUsecase.EXPECT().
+		Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, n *domain.Notify) (string, error) {
+			if n.IdempotencyKey != "body-key" {
+				t.Errorf("expected idempotency key %q, got %q", "body-key", n.IdempotencyKey)
+			}
+			return "original-id", domain.ErrIdempotencyConflict
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestNotifyHandler_Create_InvalidRetryPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	reqBody := CreateNotifyRequest{
+		Payload:     json.RawMessage(`"hello"`),
+		Target:      "test@example.com",
+		Channel:     "email",
+		ScheduledAt: time.Now().Add(time.Hour),
+		RetryPolicy: &RetryPolicyRequest{Kind: "custom", Schedule: []string{"30s", "soon"}},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	// Act - usecase не вызывается
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestNotifyHandler_Create_DeliveryWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		window     DeliveryWindowRequest
+		wantStatus int
+	}{
+		{
+			name:       "valid",
+			window:     DeliveryWindowRequest{Timezone: "Europe/Moscow", Start: "09:00", End: "21:00", Weekdays: []string{"mon", "fri"}},
+			wantStatus: http.StatusCreated,
+		},
+		{name: "unknown timezone", window: DeliveryWindowRequest{Timezone: "Moscow", Start: "09:00", End: "21:00"}, wantStatus: http.StatusUnprocessableEntity},
+		{name: "bad time", window: DeliveryWindowRequest{Timezone: "UTC", Start: "9am", End: "21:00"}, wantStatus: http.StatusUnprocessableEntity},
+		{name: "bad weekday", window: DeliveryWindowRequest{Timezone: "UTC", Start: "09:00", End: "21:00", Weekdays: []string{"funday"}}, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+			r := router.New(router.Config{GinMode: "test"})
+			handler := NewNotifyHandler(mockUsecase, log.New())
+			handler.Register(r)
+
+			window := tt.window
+			body, _ := json.Marshal(CreateNotifyRequest{
+				Payload:        json.RawMessage(`"hello"`),
+				Target:         "test@example.com",
+				Channel:        "email",
+				ScheduledAt:    time.Now().Add(time.Hour),
+				DeliveryWindow: &window,
+			})
+
+			// Expect: в usecase попадает разобранное окно
+			if tt.wantStatus == http.StatusCreated {
+				mockUsecase.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, n *domain.Notify) (string, error) {
+						w := n.DeliveryWindow
+						if w == nil || w.Start != 9*60 || w.End != 21*60 || len(w.Weekdays) != 2 || w.Weekdays[1] != time.Friday {
+							t.Errorf("unexpected delivery window: %+v", w)
+						}
+						return n.ID, nil
+					}).
+					Times(1)
+			}
+
+			// Act
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/notify", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNotifyHandler_Attempts_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	notifyID := "550e8400-e29b-41d4-a716-446655440000"
+	attempts := []*domain.Attempt{
+		{ID: "a1", NotifyID: notifyID, Kind: domain.AttemptPublish, Outcome: domain.OutcomeSuccess, Latency: 3 * time.Millisecond},
+		{
+			ID:                "a2",
+			NotifyID:          notifyID,
+			Kind:              domain.AttemptSend,
+			WorkerID:          "worker-1",
+			Channel:           "email",
+			Outcome:           domain.OutcomeSuccess,
+			ProviderMessageID: "<msg-1@example.com>",
+			Latency:           250 * time.Millisecond,
+		},
+	}
+
+	// Expect: история попыток из usecase
+	mockUsecase.EXPECT().
+		Attempts(gomock.Any(), notifyID).
+		Return(attempts, nil).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/notify/"+notifyID+"/attempts", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response AttemptListResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response.Items) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(response.Items))
+	}
+	if response.Items[1].LatencyMs != 250 || response.Items[1].ProviderMessageID != "<msg-1@example.com>" {
+		t.Errorf("unexpected attempt: %+v", response.Items[1])
+	}
+}
+
+func TestNotifyHandler_Attempts_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	notifyID := "550e8400-e29b-41d4-a716-446655440000"
+
+	mockUsecase.EXPECT().
+		Attempts(gomock.Any(), notifyID).
+		Return(nil, domain.ErrNotFound).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/notify/"+notifyID+"/attempts", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func patchNotify(r *router.Router, id, ifMatch, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/notify/"+id, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set(IfMatchHeader, ifMatch)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestNotifyHandler_Update_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	notifyID := "550e8400-e29b-41d4-a716-446655440000"
+	scheduledAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	// Expect: версия из If-Match, в patch только переданные поля
+	mockUsecase.EXPECT().
+		Update(gomock.Any(), notifyID, gomock.Any(), 2).
+		DoAndReturn(func(_ any, _ string, patch domain.NotifyPatch, _ int) (*domain.Notify, error) {
+			if patch.ScheduledAt == nil || !patch.ScheduledAt.Equal(scheduledAt) || patch.Target != nil || patch.Payload != nil {
+				t.Errorf("unexpected patch %+v", patch)
+			}
+			return &domain.Notify{ID: notifyID, Status: domain.StatusPending, ScheduledAt: scheduledAt, Version: 3}, nil
+		}).
+		Times(1)
+
+	// Act
+	w := patchNotify(r, notifyID, `"2"`, `{"scheduled_at":"`+scheduledAt.Format(time.RFC3339)+`"}`)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w.Header().Get(ETagHeader) != `"3"` {
+		t.Errorf("expected new ETag \"3\", got %s", w.Header().Get(ETagHeader))
+	}
+}
+
+func TestNotifyHandler_Update_Errors(t *testing.T) {
+	notifyID := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		body    string
+		err     error
+		code    int
+	}{
+		{"version mismatch", `"1"`, `{"target":"a@b.c"}`, domain.ErrVersionMismatch, http.StatusPreconditionFailed},
+		{"not pending", "", `{"target":"a@b.c"}`, domain.ErrNotifyNotEditable, http.StatusConflict},
+		{"not found", "", `{"target":"a@b.c"}`, domain.ErrNotFound, http.StatusNotFound},
+		{"empty patch", "", `{}`, nil, http.StatusUnprocessableEntity},
+		{"empty target", "", `{"target":""}`, nil, http.StatusUnprocessableEntity},
+		{"past scheduled_at", "", `{"scheduled_at":"2000-01-01T00:00:00Z"}`, nil, http.StatusUnprocessableEntity},
+		{"bad If-Match", "W/1", `{"target":"a@b.c"}`, nil, http.StatusBadRequest},
+		{"If-Match and version differ", `"1"`, `{"target":"a@b.c","version":2}`, nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+			r := router.New(router.Config{GinMode: "test"})
+			NewNotifyHandler(mockUsecase, log.New()).Register(r)
+
+			// Expect: usecase вызывается только для корректных запросов
+			if tt.err != nil {
+				mockUsecase.EXPECT().
+					Update(gomock.Any(), notifyID, gomock.Any(), gomock.Any()).
+					Return(nil, tt.err).
+					Times(1)
+			}
+
+			// Act
+			w := patchNotify(r, notifyID, tt.ifMatch, tt.body)
+
+			// Assert
+			if w.Code != tt.code {
+				t.Errorf("expected status %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+)
+
+const (
+	Recipients  = "/recipients"     // POST, GET
+	RecipientID = "/recipients/:id" // GET, PUT, DELETE
+)
+
+type recipientHandler struct {
+	usecase domain.RecipientUsecase
+	log     log.Log
+}
+
+func NewRecipientHandler(u domain.RecipientUsecase, l log.Log) router.Handler {
+	return &recipientHandler{usecase: u, log: l}
+}
+
+func (h *recipientHandler) Register(router *router.Router) {
+	router.POST(Recipients, h.Create)
+	router.GET(Recipients, h.List)
+	router.GET(RecipientID, h.Get)
+	router.PUT(RecipientID, h.Update)
+	router.DELETE(RecipientID, h.Delete)
+}
+
+func (h *recipientHandler) Create(c *router.Context) {
+	var req RecipientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "invalid json",
+		})
+		return
+	}
+
+	r, err := recipientRequestToDomain(req)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	r, err = h.usecase.Create(c, r)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toRecipientResponse(r))
+}
+
+func (h *recipientHandler) Get(c *router.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	r, err := h.usecase.GetByID(c, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRecipientResponse(r))
+}
+
+func (h *recipientHandler) List(c *router.Context) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	recipients, err := h.usecase.List(c, min(limit, maxListLimit), offset)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	res := make([]RecipientResponse, 0, len(recipients))
+	for _, r := range recipients {
+		res = append(res, toRecipientResponse(r))
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Update заменяет получателя целиком, ID берется из пути
+func (h *recipientHandler) Update(c *router.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req RecipientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "invalid json",
+		})
+		return
+	}
+
+	r, err := recipientRequestToDomain(req)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	r.ID = id
+
+	r, err = h.usecase.Update(c, r)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRecipientResponse(r))
+}
+
+func (h *recipientHandler) Delete(c *router.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Delete(c, id); err != nil && !errors.Is(err, domain.ErrRecipientNotFound) {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *recipientHandler) parseID(c *router.Context) (string, bool) {
+	id := c.Param("id")
+	if err := uuid.Parse(id); err != nil {
+		h.log.Error().Err(err).Msg("wrong ID format")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "cannot parse ID",
+		})
+		return "", false
+	}
+	return id, true
+}
+
+func (h *recipientHandler) writeError(c *router.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrRecipientNotFound):
+		c.JSON(http.StatusNotFound, router.H{
+			"error": "not found recipient",
+		})
+	case errors.Is(err, domain.ErrInvalidRecipient):
+		c.JSON(http.StatusUnprocessableEntity, router.H{
+			"error": err.Error(),
+		})
+	default:
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+	}
+}
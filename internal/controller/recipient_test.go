@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecipientHandler_Create_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockRecipientUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewRecipientHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	body, _ := json.Marshal(RecipientRequest{
+		Name:           "Ivan",
+		Contacts:       map[string]string{"telegram": "42", "email": "user@example.com"},
+		Channels:       []string{"telegram", "email"},
+		OptOuts:        []string{"webhook"},
+		DeliveryWindow: &DeliveryWindowRequest{Timezone: "Europe/Moscow", Start: "09:00", End: "21:00"},
+	})
+
+	// Expect: в usecase попадают контакты, предпочтения и разобранное окно
+	mockUsecase.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, rec *domain.Recipient) (*domain.Recipient, error) {
+			if rec.Contacts["telegram"] != "42" || len(rec.Channels) != 2 || rec.OptOuts[0] != "webhook" {
+				t.Errorf("unexpected recipient: %+v", rec)
+			}
+			if rec.DeliveryWindow == nil || rec.DeliveryWindow.Start != 9*60 {
+				t.Errorf("unexpected delivery window: %+v", rec.DeliveryWindow)
+			}
+			return rec, nil
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/recipients", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var resp RecipientResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.ID == "" || resp.Contacts["email"] != "user@example.com" || resp.DeliveryWindow == nil {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestRecipientHandler_Create_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockRecipientUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewRecipientHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	body, _ := json.Marshal(RecipientRequest{Contacts: map[string]string{"email": "user@example.com"}, Channels: []string{"telegram"}})
+
+	mockUsecase.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil, domain.ErrInvalidRecipient).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/recipients", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestRecipientHandler_Update_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockRecipientUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewRecipientHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	id := domain.NewRecipient().ID
+	body, _ := json.Marshal(RecipientRequest{Contacts: map[string]string{"email": "user@example.com"}})
+
+	// Expect: ID берется из пути
+	mockUsecase.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, rec *domain.Recipient) (*domain.Recipient, error) {
+			if rec.ID != id {
+				t.Errorf("expected id %s, got %s", id, rec.ID)
+			}
+			return nil, domain.ErrRecipientNotFound
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/recipients/"+id, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestNotifyHandler_Create_Recipient(t *testing.T) {
+	recipientID := domain.NewRecipient().ID
+
+	tests := []struct {
+		name       string
+		req        CreateNotifyRequest
+		saveErr    error
+		wantStatus int
+	}{
+		{
+			name:       "recipient with channels",
+			req:        CreateNotifyRequest{RecipientID: recipientID, Channels: []string{"telegram", "email"}},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "recipient with target",
+			req:        CreateNotifyRequest{RecipientID: recipientID, Target: "user@example.com"},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "channels without recipient",
+			req:        CreateNotifyRequest{Target: "user@example.com", Channel: "email", Channels: []string{"email"}},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "bad recipient id",
+			req:        CreateNotifyRequest{RecipientID: "ivan"},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "no route",
+			req:        CreateNotifyRequest{RecipientID: recipientID, Channels: []string{"sms"}},
+			saveErr:    domain.ErrNoRoute,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+			r := router.New(router.Config{GinMode: "test"})
+			handler := NewNotifyHandler(mockUsecase, log.New())
+			handler.Register(r)
+
+			req := tt.req
+			req.Payload = json.RawMessage(`"hello"`)
+			req.ScheduledAt = time.Now().Add(time.Hour)
+			body, _ := json.Marshal(req)
+
+			// Expect: в usecase попадают получатель и порядок каналов
+			if tt.wantStatus == http.StatusCreated || tt.saveErr != nil {
+				mockUsecase.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, n *domain.Notify) (string, error) {
+						if n.RecipientID != recipientID || len(n.Channels) != len(tt.req.Channels) {
+							t.Errorf("unexpected recipient addressing: %s %v", n.RecipientID, n.Channels)
+						}
+						return n.ID, tt.saveErr
+					}).
+					Times(1)
+			}
+
+			// Act
+			w := httptest.NewRecorder()
+			httpReq, _ := http.NewRequest("POST", "/notify", bytes.NewBuffer(body))
+			httpReq.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, httpReq)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+)
+
+const (
+	Schedules      = "/schedules"            // POST, GET
+	ScheduleID     = "/schedules/:id"        // GET, DELETE
+	SchedulePause  = "/schedules/:id/pause"  // POST
+	ScheduleResume = "/schedules/:id/resume" // POST
+)
+
+type scheduleHandler struct {
+	usecase domain.ScheduleUsecase
+	log     log.Log
+}
+
+func NewScheduleHandler(u domain.ScheduleUsecase, l log.Log) router.Handler {
+	return &scheduleHandler{usecase: u, log: l}
+}
+
+func (h *scheduleHandler) Register(router *router.Router) {
+	router.POST(Schedules, h.Create)
+	router.GET(Schedules, h.List)
+	router.GET(ScheduleID, h.Get)
+	router.DELETE(ScheduleID, h.Delete)
+	router.POST(SchedulePause, h.Pause)
+	router.POST(ScheduleResume, h.Resume)
+}
+
+func (h *scheduleHandler) Create(c *router.Context) {
+	var req CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "invalid json",
+		})
+		return
+	}
+
+	s, err := createScheduleRequestToDomain(req)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s, err = h.usecase.Create(c, s)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toScheduleResponse(s))
+}
+
+func (h *scheduleHandler) Get(c *router.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	s, err := h.usecase.GetByID(c, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toScheduleResponse(s))
+}
+
+func (h *scheduleHandler) List(c *router.Context) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	schedules, err := h.usecase.List(c, min(limit, maxListLimit), offset)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	res := make([]ScheduleResponse, 0, len(schedules))
+	for _, s := range schedules {
+		res = append(res, toScheduleResponse(s))
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *scheduleHandler) Pause(c *router.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	s, err := h.usecase.Pause(c, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toScheduleResponse(s))
+}
+
+func (h *scheduleHandler) Resume(c *router.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	s, err := h.usecase.Resume(c, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toScheduleResponse(s))
+}
+
+func (h *scheduleHandler) Delete(c *router.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Delete(c, id); err != nil && !errors.Is(err, domain.ErrScheduleNotFound) {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *scheduleHandler) parseID(c *router.Context) (string, bool) {
+	id := c.Param("id")
+	if err := uuid.Parse(id); err != nil {
+		h.log.Error().Err(err).Msg("wrong ID format")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "cannot parse ID",
+		})
+		return "", false
+	}
+	return id, true
+}
+
+func (h *scheduleHandler) writeError(c *router.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrScheduleNotFound):
+		c.JSON(http.StatusNotFound, router.H{
+			"error": "not found schedule",
+		})
+	case errors.Is(err, domain.ErrInvalidSchedule):
+		c.JSON(http.StatusUnprocessableEntity, router.H{
+			"error": err.Error(),
+		})
+	default:
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+	}
+}
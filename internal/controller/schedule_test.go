@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScheduleHandler_Create_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockScheduleUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewScheduleHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	body, _ := json.Marshal(CreateScheduleRequest{
+		Payload:  json.RawMessage(`"weekly reminder"`),
+		Target:   "test@example.com",
+		Channel:  "email",
+		Interval: "168h",
+	})
+
+	// Expect: интервал распарсен и передан в usecase
+	mockUsecase.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, s *domain.Schedule) (*domain.Schedule, error) {
+			if s.Interval != 168*time.Hour {
+				t.Errorf("expected interval 168h, got %v", s.Interval)
+			}
+			return s, nil
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+}
+
+func TestScheduleHandler_Create_InvalidRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockScheduleUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewScheduleHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	body, _ := json.Marshal(CreateScheduleRequest{
+		Target:  "test@example.com",
+		Channel: "email",
+		Cron:    "not a cron",
+	})
+
+	// Expect: usecase отклоняет правило
+	mockUsecase.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil, domain.ErrInvalidSchedule).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestScheduleHandler_Pause_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockScheduleUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewScheduleHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	scheduleID := "550e8400-e29b-41d4-a716-446655440000"
+
+	// Expect: серия не найдена
+	mockUsecase.EXPECT().
+		Pause(gomock.Any(), scheduleID).
+		Return(nil, domain.ErrScheduleNotFound).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/schedules/"+scheduleID+"/pause", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestScheduleHandler_Delete_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockScheduleUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewScheduleHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	scheduleID := "550e8400-e29b-41d4-a716-446655440000"
+
+	// Expect: удаление серии
+	mockUsecase.EXPECT().
+		Delete(gomock.Any(), scheduleID).
+		Return(nil).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/schedules/"+scheduleID, nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+}
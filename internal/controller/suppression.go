@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+)
+
+const (
+	Suppressions  = "/suppressions"       // POST, GET
+	SuppressionID = "/suppressions/:id"   // DELETE
+	Unsubscribe   = "/unsubscribe/:token" // GET, POST - без аутентификации
+)
+
+// unsubscribePage - страница для перехода по ссылке из письма: GET не должен отписывать
+// (RFC 8058), поэтому отписка идет только по кнопке
+const unsubscribePage = `<!DOCTYPE html>
+<html><body>
+<form method="post"><input type="hidden" name="List-Unsubscribe" value="One-Click">
+<button type="submit">Unsubscribe</button></form>
+</body></html>`
+
+type suppressionHandler struct {
+	usecase domain.SuppressionUsecase
+	log     log.Log
+}
+
+func NewSuppressionHandler(u domain.SuppressionUsecase, l log.Log) router.Handler {
+	return &suppressionHandler{usecase: u, log: l}
+}
+
+func (h *suppressionHandler) Register(router *router.Router) {
+	router.POST(Suppressions, h.Create)
+	router.GET(Suppressions, h.List)
+	router.DELETE(SuppressionID, h.Delete)
+}
+
+func (h *suppressionHandler) Create(c *router.Context) {
+	var req SuppressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "invalid json",
+		})
+		return
+	}
+
+	s, err := h.usecase.Create(c, suppressionRequestToDomain(req), req.AllTenants)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toSuppressionResponse(s))
+}
+
+// List - записи арендатора и записи для всех арендаторов, фильтры channel и target
+func (h *suppressionHandler) List(c *router.Context) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	suppressions, err := h.usecase.List(c, domain.SuppressionFilter{
+		Channel: c.Query("channel"),
+		Target:  c.Query("target"),
+		Limit:   min(limit, maxListLimit),
+		Offset:  offset,
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	res := make([]SuppressionResponse, 0, len(suppressions))
+	for _, s := range suppressions {
+		res = append(res, toSuppressionResponse(s))
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *suppressionHandler) Delete(c *router.Context) {
+	id := c.Param("id")
+	if err := uuid.Parse(id); err != nil {
+		h.log.Error().Err(err).Msg("wrong ID format")
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "cannot parse ID",
+		})
+		return
+	}
+
+	if err := h.usecase.Delete(c, id); err != nil && !errors.Is(err, domain.ErrSuppressionNotFound) {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *suppressionHandler) writeError(c *router.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidSuppression):
+		c.JSON(http.StatusUnprocessableEntity, router.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrSuppressionScope):
+		c.JSON(http.StatusForbidden, router.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrSuppressionExists):
+		c.JSON(http.StatusConflict, router.H{
+			"error": err.Error(),
+		})
+	default:
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+	}
+}
+
+type unsubscribeHandler struct {
+	usecase domain.SuppressionUsecase
+	log     log.Log
+}
+
+// NewUnsubscribeHandler - отписка по ссылке из письма. Регистрируется до аутентификации:
+// ссылку открывает получатель, доступ дает подпись токена
+func NewUnsubscribeHandler(u domain.SuppressionUsecase, l log.Log) router.Handler {
+	return &unsubscribeHandler{usecase: u, log: l}
+}
+
+func (h *unsubscribeHandler) Register(router *router.Router) {
+	router.GET(Unsubscribe, h.Page)
+	router.POST(Unsubscribe, h.Unsubscribe)
+}
+
+func (h *unsubscribeHandler) Page(c *router.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(unsubscribePage))
+}
+
+// Unsubscribe принимает POST почтового клиента (List-Unsubscribe=One-Click) или кнопки со страницы
+func (h *unsubscribeHandler) Unsubscribe(c *router.Context) {
+	if err := h.usecase.Unsubscribe(c, c.Param("token")); err != nil {
+		if errors.Is(err, domain.ErrInvalidUnsubscribeToken) {
+			h.log.Warn().Str("ip", c.ClientIP()).Msg("rejected unsubscribe with invalid token")
+			c.JSON(http.StatusBadRequest, router.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, router.H{"status": "unsubscribed"})
+}
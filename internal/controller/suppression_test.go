@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSuppressionHandler_Create(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        SuppressionRequest
+		createErr  error
+		wantStatus int
+	}{
+		{
+			name:       "created",
+			req:        SuppressionRequest{Channel: "email", Target: "user@example.com", Category: "marketing", Reason: "complaint"},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "all tenants forbidden",
+			req:        SuppressionRequest{Channel: "email", Target: "user@example.com", AllTenants: true},
+			createErr:  domain.ErrSuppressionScope,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "exists",
+			req:        SuppressionRequest{Channel: "email", Target: "user@example.com"},
+			createErr:  domain.ErrSuppressionExists,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "invalid",
+			req:        SuppressionRequest{Channel: "email"},
+			createErr:  domain.ErrInvalidSuppression,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsecase := mocks.NewMockSuppressionUsecase(ctrl)
+
+			r := router.New(router.Config{GinMode: "test"})
+			handler := NewSuppressionHandler(mockUsecase, log.New())
+			handler.Register(r)
+
+			body, _ := json.Marshal(tt.req)
+
+			// Expect: поля запроса и охват передаются в usecase
+			mockUsecase.EXPECT().
+				Create(gomock.Any(), gomock.Any(), tt.req.AllTenants).
+				DoAndReturn(func(_ context.Context, s *domain.Suppression, _ bool) (*domain.Suppression, error) {
+					if s.Channel != tt.req.Channel || s.Target != tt.req.Target || s.Category != tt.req.Category {
+						t.Errorf("unexpected suppression: %+v", s)
+					}
+					if tt.req.Reason != "" && string(s.Reason) != tt.req.Reason {
+						t.Errorf("expected reason %s, got %s", tt.req.Reason, s.Reason)
+					}
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return s, nil
+				}).
+				Times(1)
+
+			// Act
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/suppressions", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUnsubscribeHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockSuppressionUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewUnsubscribeHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	// Expect: отписывает только POST, GET отдает страницу с кнопкой
+	mockUsecase.EXPECT().Unsubscribe(gomock.Any(), "good-token").Return(nil).Times(1)
+	mockUsecase.EXPECT().Unsubscribe(gomock.Any(), "bad-token").Return(domain.ErrInvalidUnsubscribeToken).Times(1)
+
+	// Act
+	page := httptest.NewRecorder()
+	r.ServeHTTP(page, httptest.NewRequest("GET", "/unsubscribe/good-token", nil))
+
+	oneClick := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/unsubscribe/good-token", strings.NewReader("List-Unsubscribe=One-Click"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(oneClick, req)
+
+	forged := httptest.NewRecorder()
+	r.ServeHTTP(forged, httptest.NewRequest("POST", "/unsubscribe/bad-token", nil))
+
+	// Assert
+	if page.Code != http.StatusOK || !strings.Contains(page.Body.String(), `method="post"`) {
+		t.Errorf("expected unsubscribe page, got %d: %s", page.Code, page.Body.String())
+	}
+	if oneClick.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, oneClick.Code)
+	}
+	if forged.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, forged.Code)
+	}
+}
+
+func TestNotifyHandler_Create_InvalidCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Expect: notify с невалидной категорией не доходит до usecase
+	mockUsecase := mocks.NewMockNotifyUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewNotifyHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	body, _ := json.Marshal(CreateNotifyRequest{
+		Payload:     json.RawMessage(`"hello"`),
+		Target:      "user@example.com",
+		Channel:     "email",
+		ScheduledAt: time.Now().Add(time.Hour),
+		Category:    "Marketing News",
+	})
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notify", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
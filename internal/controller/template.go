@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+)
+
+const (
+	Templates        = "/templates"                         // POST, GET
+	TemplateByName   = "/templates/:name/:channel"          // GET, PUT, DELETE
+	TemplateVersions = "/templates/:name/:channel/versions" // GET
+)
+
+type templateHandler struct {
+	usecase domain.TemplateUsecase
+	log     log.Log
+}
+
+func NewTemplateHandler(u domain.TemplateUsecase, l log.Log) router.Handler {
+	return &templateHandler{usecase: u, log: l}
+}
+
+func (h *templateHandler) Register(router *router.Router) {
+	router.POST(Templates, h.Create)
+	router.GET(Templates, h.List)
+	router.GET(TemplateByName, h.Get)
+	router.PUT(TemplateByName, h.Update)
+	router.DELETE(TemplateByName, h.Delete)
+	router.GET(TemplateVersions, h.Versions)
+}
+
+func (h *templateHandler) Create(c *router.Context) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "invalid json",
+		})
+		return
+	}
+
+	t, err := h.usecase.Create(c, templateRequestToDomain(req))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTemplateResponse(t))
+}
+
+// Update создает новую версию шаблона, имя и канал берутся из пути
+func (h *templateHandler) Update(c *router.Context) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router.H{
+			"error": "invalid json",
+		})
+		return
+	}
+	req.Name = c.Param("name")
+	req.Channel = c.Param("channel")
+
+	t, err := h.usecase.Update(c, templateRequestToDomain(req))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTemplateResponse(t))
+}
+
+func (h *templateHandler) Get(c *router.Context) {
+	version := 0
+	if raw := c.Query("version"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			c.JSON(http.StatusBadRequest, router.H{
+				"error": "invalid version",
+			})
+			return
+		}
+		version = v
+	}
+
+	t, err := h.usecase.Get(c, c.Param("name"), c.Param("channel"), version)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTemplateResponse(t))
+}
+
+func (h *templateHandler) List(c *router.Context) {
+	templates, err := h.usecase.List(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTemplateListResponse(templates))
+}
+
+func (h *templateHandler) Versions(c *router.Context) {
+	templates, err := h.usecase.Versions(c, c.Param("name"), c.Param("channel"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTemplateListResponse(templates))
+}
+
+func (h *templateHandler) Delete(c *router.Context) {
+	err := h.usecase.Delete(c, c.Param("name"), c.Param("channel"))
+	if err != nil && !errors.Is(err, domain.ErrTemplateNotFound) {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *templateHandler) writeError(c *router.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, router.H{
+			"error": "not found template",
+		})
+	case errors.Is(err, domain.ErrTemplateAlreadyExists):
+		c.JSON(http.StatusConflict, router.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidTemplate):
+		c.JSON(http.StatusUnprocessableEntity, router.H{
+			"error": err.Error(),
+		})
+	default:
+		h.log.Error().Err(err).Msg("internal server error")
+		c.JSON(http.StatusInternalServerError, router.H{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/router"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTemplateHandler_Update_NewVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockTemplateUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewTemplateHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	body, _ := json.Marshal(TemplateRequest{Subject: "Hi", Text: "Hello, {{.name}}"})
+
+	// Expect: имя и канал берутся из пути
+	mockUsecase.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, tmpl *domain.Template) (*domain.Template, error) {
+			if tmpl.Name != "welcome" || tmpl.Channel != "email" {
+				t.Errorf("unexpected template key %s/%s", tmpl.Name, tmpl.Channel)
+			}
+			tmpl.Version = 2
+			return tmpl, nil
+		}).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/templates/welcome/email", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp TemplateResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Version != 2 {
+		t.Errorf("expected version 2, got %d", resp.Version)
+	}
+}
+
+func TestTemplateHandler_Create_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockTemplateUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewTemplateHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	body, _ := json.Marshal(TemplateRequest{Name: "welcome", Channel: "email", Text: "Hi"})
+
+	mockUsecase.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil, domain.ErrTemplateAlreadyExists).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/templates", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestTemplateHandler_Get_Version(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockTemplateUsecase(ctrl)
+
+	r := router.New(router.Config{GinMode: "test"})
+	handler := NewTemplateHandler(mockUsecase, log.New())
+	handler.Register(r)
+
+	// Expect: запрошена конкретная версия
+	mockUsecase.EXPECT().
+		Get(gomock.Any(), "welcome", "telegram", 1).
+		Return(nil, domain.ErrTemplateNotFound).
+		Times(1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/templates/welcome/telegram?version=1", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+)
+
+type AttemptKind string
+
+const (
+	AttemptPublish AttemptKind = "publish" // публикация в очередь планировщиком
+	AttemptSend    AttemptKind = "send"    // отправка провайдеру воркером
+)
+
+type AttemptOutcome string
+
+const (
+	OutcomeSuccess    AttemptOutcome = "success"
+	OutcomeRetry      AttemptOutcome = "retry"      // неудача, следующая попытка запланирована
+	OutcomeFailed     AttemptOutcome = "failed"     // неудача, notify переведен в StatusFailed
+	OutcomeFallback   AttemptOutcome = "fallback"   // неудача, notify переключен на запасной маршрут
+	OutcomeSuppressed AttemptOutcome = "suppressed" // не отправлено: адресат в списке подавления
+)
+
+// Attempt - одна попытка публикации или отправки notify
+type Attempt struct {
+	ID                string
+	NotifyID          string
+	Kind              AttemptKind
+	WorkerID          string
+	Channel           string
+	Outcome           AttemptOutcome
+	Error             string
+	ProviderMessageID string
+	ProviderResponse  string
+	Latency           time.Duration
+	CreatedAt         time.Time
+}
+
+func NewAttempt(n *Notify, kind AttemptKind, workerID string) *Attempt {
+	return &Attempt{
+		ID:        uuid.New(),
+		NotifyID:  n.ID,
+		Kind:      kind,
+		WorkerID:  workerID,
+		Channel:   n.Channel,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Delivery - ответ провайдера на успешную отправку
+type Delivery struct {
+	MessageID string
+	Response  string
+}
@@ -0,0 +1,25 @@
+package domain
+
+// BatchItemStatus - итог обработки одного notify из пакетного создания
+type BatchItemStatus string
+
+const (
+	BatchCreated  BatchItemStatus = "created"
+	BatchReplayed BatchItemStatus = "replayed" // повтор запроса с тем же ключом идемпотентности
+	BatchConflict BatchItemStatus = "conflict" // ключ уже использован с другим запросом или notify существует
+	BatchInvalid  BatchItemStatus = "invalid"  // не прошел валидацию
+	BatchRejected BatchItemStatus = "rejected" // корректен, но не создан: атомарная пачка отклонена целиком
+	BatchFailed   BatchItemStatus = "failed"   // внутренняя ошибка
+)
+
+// BatchResult - результат по одному элементу пачки, ID - созданный или ранее созданный notify
+type BatchResult struct {
+	ID     string
+	Status BatchItemStatus
+	Err    error
+}
+
+// OK сообщает, что notify по элементу существует: создан сейчас или ранее тем же запросом
+func (r BatchResult) OK() bool {
+	return r.Status == BatchCreated || r.Status == BatchReplayed
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CacheMode - как кеш notify узнает о смене статуса
+type CacheMode string
+
+const (
+	// CacheWriteThrough - статус в кеше обновляется вместе с БД
+	CacheWriteThrough CacheMode = "write_through"
+	// CacheInvalidate - запись удаляется из кеша, следующее чтение идет в БД
+	CacheInvalidate CacheMode = "invalidate"
+)
+
+func (m CacheMode) Validate() error {
+	switch m {
+	case CacheWriteThrough, CacheInvalidate:
+		return nil
+	default:
+		return fmt.Errorf("unknown cache mode %q", m)
+	}
+}
+
+// SyncStatus отражает смену статуса notify в кеше согласно режиму.
+// Вызывается после успешного обновления в БД
+func (m CacheMode) SyncStatus(
+	ctx context.Context,
+	cache NotifyRedis,
+	id string,
+	status Status,
+	scheduledAt *time.Time,
+	retryCount int,
+	lastErr *string,
+) error {
+	if m == CacheInvalidate {
+		return cache.Delete(ctx, id)
+	}
+	return cache.UpdateStatus(ctx, id, status, scheduledAt, retryCount, lastErr)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// ErrorRecord - одна неудачная попытка доставки из истории notify
+type ErrorRecord struct {
+	At      time.Time `json:"at"`
+	Attempt int       `json:"attempt"`
+	Error   string    `json:"error"`
+}
+
+// DeadLetter - notify в StatusFailed вместе с историей ошибок
+type DeadLetter struct {
+	*Notify
+	Errors []ErrorRecord
+}
+
+type DeadLetterPage struct {
+	Items      []*DeadLetter
+	NextCursor string
+}
@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+const ChannelEmail = "email"
+
+const (
+	maxEmailRecipients  = 50
+	maxEmailAttachments = 10
+	// MaxEmailAttachmentsSize - суммарный размер вложений письма после декодирования,
+	// для ссылок проверяется при скачивании
+	MaxEmailAttachmentsSize = 10 << 20
+)
+
+// reservedEmailHeaders задает сам сервис, в headers их переопределить нельзя
+var reservedEmailHeaders = map[string]bool{
+	"From": true, "To": true, "Cc": true, "Bcc": true, "Reply-To": true, "Subject": true,
+	"Date": true, "Message-Id": true, "Mime-Version": true, "Content-Type": true,
+	"Content-Transfer-Encoding": true, "List-Unsubscribe": true, "List-Unsubscribe-Post": true,
+}
+
+// EmailPayload - структурированный payload канала email:
+// {"subject": "...", "text": "...", "html": "...", "cc": [...], "attachments": [...]}.
+// Если заданы и text, и html, письмо уходит как multipart/alternative
+type EmailPayload struct {
+	Subject     string            `json:"subject,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	HTML        string            `json:"html,omitempty"`
+	CC          []string          `json:"cc,omitempty"`
+	BCC         []string          `json:"bcc,omitempty"`
+	ReplyTo     string            `json:"reply_to,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Attachments []EmailAttachment `json:"attachments,omitempty"`
+}
+
+// EmailAttachment - вложение: содержимое в base64 (content) или ссылка на объект
+// в хранилище (url, например presigned URL S3), которую отправитель скачивает перед отправкой
+type EmailAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type,omitempty"`
+	Content     string `json:"content,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// ParseEmailPayload разбирает payload письма. ok=false для payload, который отправляется
+// как есть: не JSON-объект, ссылка на шаблон или объект без text и html - так отправлялись
+// любые payload до появления структурированного формата. Объект с text или html разбирается
+// строго: неизвестное поле или неверный тип - ошибка, чтобы опечатка в имени поля не
+// превратилась в молча потерянную копию или вложение
+func ParseEmailPayload(payload []byte) (*EmailPayload, bool, error) {
+	trimmed := bytes.TrimSpace(payload)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, false, nil
+	}
+	if _, ok := ParseTemplatePayload(trimmed); ok {
+		return nil, false, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return nil, false, nil
+	}
+	_, hasText := fields["text"]
+	_, hasHTML := fields["html"]
+	if !hasText && !hasHTML {
+		return nil, false, nil
+	}
+
+	var p EmailPayload
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, true, fmt.Errorf("%w: %v", ErrInvalidEmailPayload, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, true, err
+	}
+	return &p, true, nil
+}
+
+// ValidatePayload проверяет payload для канала. Структуру payload задает только email,
+// остальные каналы отправляют его как есть
+func ValidatePayload(channel string, payload []byte) error {
+	if channel != ChannelEmail {
+		return nil
+	}
+	_, _, err := ParseEmailPayload(payload)
+	return err
+}
+
+func (p *EmailPayload) Validate() error {
+	if p.Text == "" && p.HTML == "" {
+		return fmt.Errorf("%w: text or html body is required", ErrInvalidEmailPayload)
+	}
+	if strings.ContainsAny(p.Subject, "\r\n") {
+		return fmt.Errorf("%w: subject must be a single line", ErrInvalidEmailPayload)
+	}
+
+	if len(p.CC)+len(p.BCC) > maxEmailRecipients {
+		return fmt.Errorf("%w: more than %d cc and bcc recipients", ErrInvalidEmailPayload, maxEmailRecipients)
+	}
+	for _, addr := range append(append([]string{}, p.CC...), p.BCC...) {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("%w: invalid address %q", ErrInvalidEmailPayload, addr)
+		}
+	}
+	if p.ReplyTo != "" {
+		if _, err := mail.ParseAddress(p.ReplyTo); err != nil {
+			return fmt.Errorf("%w: invalid reply_to %q", ErrInvalidEmailPayload, p.ReplyTo)
+		}
+	}
+
+	for name, value := range p.Headers {
+		if !validHeaderName(name) {
+			return fmt.Errorf("%w: invalid header name %q", ErrInvalidEmailPayload, name)
+		}
+		if reservedEmailHeaders[textproto.CanonicalMIMEHeaderKey(name)] {
+			return fmt.Errorf("%w: header %s cannot be overridden", ErrInvalidEmailPayload, name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("%w: header %s must be a single line", ErrInvalidEmailPayload, name)
+		}
+	}
+
+	if len(p.Attachments) > maxEmailAttachments {
+		return fmt.Errorf("%w: more than %d attachments", ErrInvalidEmailPayload, maxEmailAttachments)
+	}
+	size := 0
+	for i := range p.Attachments {
+		data, err := p.Attachments[i].validate()
+		if err != nil {
+			return err
+		}
+		size += len(data)
+	}
+	if size > MaxEmailAttachmentsSize {
+		return fmt.Errorf("%w: attachments larger than %d bytes", ErrInvalidEmailPayload, MaxEmailAttachmentsSize)
+	}
+	return nil
+}
+
+// Data возвращает декодированное содержимое вложения, заданного в base64
+func (a *EmailAttachment) Data() ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(a.Content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: attachment %s is not valid base64", ErrInvalidEmailPayload, a.Filename)
+	}
+	return data, nil
+}
+
+func (a *EmailAttachment) validate() ([]byte, error) {
+	if a.Filename == "" || strings.ContainsAny(a.Filename, "/\\\r\n") {
+		return nil, fmt.Errorf("%w: invalid attachment filename %q", ErrInvalidEmailPayload, a.Filename)
+	}
+	if a.ContentType != "" {
+		if _, _, err := mime.ParseMediaType(a.ContentType); err != nil {
+			return nil, fmt.Errorf("%w: invalid content_type of attachment %s", ErrInvalidEmailPayload, a.Filename)
+		}
+	}
+	if (a.Content == "") == (a.URL == "") {
+		return nil, fmt.Errorf("%w: attachment %s needs exactly one of content or url", ErrInvalidEmailPayload, a.Filename)
+	}
+	if a.URL != "" {
+		u, err := url.Parse(a.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: attachment %s url must be absolute http(s)", ErrInvalidEmailPayload, a.Filename)
+		}
+		if host := u.Hostname(); strings.EqualFold(host, "localhost") || !PublicHost(host) {
+			return nil, fmt.Errorf("%w: attachment %s url must point to a public host", ErrInvalidEmailPayload, a.Filename)
+		}
+		return nil, nil
+	}
+	return a.Data()
+}
+
+// cgnat - общее адресное пространство провайдеров (RFC 6598), из интернета недоступно
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicIP сообщает, что адрес маршрутизируется в интернете: не loopback, не частная
+// сеть (RFC 1918, ULA), не link-local (в том числе metadata 169.254.169.254) и т.п.
+// Вложения по ссылке скачиваются только с таких адресов, чтобы через них нельзя было
+// прочитать внутренние сервисы
+func PublicIP(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !cgnat.Contains(addr)
+}
+
+// PublicHost проверяет host из URL: IP-адрес должен быть публичным, имя
+// проверяется после разрешения в DNS при скачивании
+func PublicHost(host string) bool {
+	ip := net.ParseIP(host)
+	return ip == nil || PublicIP(ip)
+}
+
+// validHeaderName - имя заголовка из букв, цифр и '-' (RFC 5322 допускает больше,
+// но почтовые серверы надежно принимают только такие)
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -6,4 +6,45 @@ var (
 	// notify errors
 	ErrNotFound            = errors.New("not found notify")
 	ErrNotifyAlreadyExists = errors.New("notify already exists")
+	ErrNotifyNotCancelable = errors.New("notify cannot be canceled")
+	ErrNotifyCanceled      = errors.New("notify is canceled")
+	ErrNotifyNotRetryable  = errors.New("only failed notify can be retried")
+	ErrNotifyNotEditable   = errors.New("only pending notify can be edited")
+	ErrVersionMismatch     = errors.New("notify version mismatch")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrBatchConflict       = errors.New("batch contains conflicting notifies")
+	ErrInvalidEmailPayload = errors.New("invalid email payload")
+
+	// delivery errors
+	// ErrPermanent - ошибка отправки, которую повтор не исправит (неверный адрес, 4xx и т.п.)
+	ErrPermanent          = errors.New("permanent delivery failure")
+	ErrInvalidRetryPolicy = errors.New("invalid retry policy")
+
+	// schedule errors
+	ErrScheduleNotFound = errors.New("not found schedule")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+
+	// recipient errors
+	ErrRecipientNotFound = errors.New("not found recipient")
+	ErrInvalidRecipient  = errors.New("invalid recipient")
+	ErrNoRoute           = errors.New("no available route to recipient")
+
+	// suppression errors
+	ErrSuppressionNotFound     = errors.New("not found suppression")
+	ErrSuppressionExists       = errors.New("suppression already exists")
+	ErrInvalidSuppression      = errors.New("invalid suppression")
+	ErrSuppressionScope        = errors.New("only default tenant can manage suppressions for all tenants")
+	ErrSuppressed              = errors.New("target is suppressed")
+	ErrInvalidCategory         = errors.New("invalid category")
+	ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+	// template errors
+	ErrTemplateNotFound      = errors.New("not found template")
+	ErrTemplateAlreadyExists = errors.New("template already exists")
+	ErrInvalidTemplate       = errors.New("invalid template")
+	ErrTemplateRender        = errors.New("failed to render template")
+
+	// idempotency errors
+	ErrIdempotentReplay    = errors.New("request with this idempotency key already processed")
+	ErrIdempotencyConflict = errors.New("idempotency key already used with different request")
 )
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+type SortField string
+
+const (
+	SortByCreatedAt   SortField = "created_at"
+	SortByScheduledAt SortField = "scheduled_at"
+)
+
+// NotifyFilter - параметры выборки списка notify.
+// Если задан Cursor, используется keyset-пагинация и Offset игнорируется.
+type NotifyFilter struct {
+	Statuses      []Status
+	Channel       string
+	Target        string
+	ScheduledFrom *time.Time
+	ScheduledTo   *time.Time
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	SortBy        SortField
+	Desc          bool
+	Limit         int
+	Offset        int
+	Cursor        *Cursor
+}
+
+// Cursor - позиция последнего отданного элемента в отсортированной выборке.
+// Привязан к полю и направлению сортировки, с которыми был выдан.
+type Cursor struct {
+	SortBy SortField `json:"s"`
+	Desc   bool      `json:"d"`
+	Value  time.Time `json:"v"`
+	ID     string    `json:"id"`
+}
+
+type NotifyPage struct {
+	Items      []*Notify
+	NextCursor string
+}
+
+func NewCursor(n *Notify, sortBy SortField, desc bool) *Cursor {
+	value := n.CreatedAt
+	if sortBy == SortByScheduledAt {
+		value = n.ScheduledAt
+	}
+	return &Cursor{
+		SortBy: sortBy,
+		Desc:   desc,
+		Value:  value,
+		ID:     n.ID,
+	}
+}
+
+func (c *Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if c.SortBy != SortByCreatedAt && c.SortBy != SortByScheduledAt {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// HealthCheck проверяет одну зависимость сервиса, nil - зависимость в порядке
+type HealthCheck func(ctx context.Context) error
+
+// HealthChecks - именованные проверки для /healthz и /readyz
+type HealthChecks map[string]HealthCheck
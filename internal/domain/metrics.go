@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Metrics - метрики доставки. Реализация живет в адаптере, чтобы usecase и воркер
+// не зависели от Prometheus
+type Metrics interface {
+	NotifyCreated(channel string)
+	NotifySent(channel string)
+	NotifyFailed(channel string)
+	// NotifyThrottled - отправка отложена лимитом доставки
+	NotifyThrottled(channel string)
+	// NotifySuppressed - отправка не выполнена: адресат в списке подавления
+	NotifySuppressed(channel string)
+
+	// ObservePublish - длительность публикации notify в очередь
+	ObservePublish(channel string, latency time.Duration, err error)
+	// ObserveSend - длительность вызова Sender.Send
+	ObserveSend(channel string, latency time.Duration, err error)
+	// ObserveBatch - размер пачки, забранной LockAndFetchReady
+	ObserveBatch(size int)
+	// ObserveSchedulingLag - насколько фактическая отправка опоздала относительно ScheduledAt
+	ObserveSchedulingLag(channel string, lag time.Duration)
+	// SetBacklog - число notify в статусе Pending
+	SetBacklog(count int)
+}
@@ -2,6 +2,9 @@ package domain
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
@@ -10,16 +13,18 @@ import (
 type Status int
 
 const (
-	StatusPending   Status = iota // 0 - ожидает отправки
-	StatusInProcess               // 1 - передано в очередь на отправку
-	StatusSent                    // 2 - отправлено
-	StatusFailed                  // 3 - ошибка после всех попыток
-	StatusCanceled                // 4 - отменено пользователем
+	StatusPending    Status = iota // 0 - ожидает отправки
+	StatusInProcess                // 1 - передано в очередь на отправку
+	StatusSent                     // 2 - отправлено
+	StatusFailed                   // 3 - ошибка после всех попыток
+	StatusCanceled                 // 4 - отменено пользователем
+	StatusSuppressed               // 5 - не отправлено: адресат в списке подавления
 )
 
 // Формат ID - uuid.UUID из пакета "github.com/google/uuid" приведенный в формат string
 type Notify struct {
 	ID          string
+	TenantID    string
 	Payload     []byte
 	Target      string
 	Channel     string
@@ -29,6 +34,42 @@ type Notify struct {
 	UpdatedAt   time.Time
 	RetryCount  int
 	LastError   *string
+
+	// Version растет при каждом изменении notify в БД, используется как ETag
+	Version int
+
+	// IdempotencyKey - ключ клиента для безопасных повторов запроса создания,
+	// RequestHash - отпечаток тела запроса, с которым ключ был использован впервые
+	IdempotencyKey string
+	RequestHash    string
+
+	// ScheduleID - серия, срабатыванием которой является notify (пусто для разовых)
+	ScheduleID string
+
+	// RetryPolicy - политика повторов этого notify, nil - политика канала
+	RetryPolicy *RetryPolicy
+
+	// DeliveryWindow - когда получателю можно доставлять notify, nil - в любое время.
+	// Планировщик сдвигает notify вне окна на ближайшее разрешенное время
+	DeliveryWindow *DeliveryWindow
+
+	// RecipientID - получатель из реестра, которому адресован notify (пусто - задан прямой адрес).
+	// Channels - запрошенный порядок каналов получателя (не хранится): при создании usecase
+	// превращает первый доступный канал в Channel/Target, остальные - в Fallbacks
+	RecipientID string
+	Channels    []string
+
+	// Category - категория рассылки (например, marketing), по ней действуют записи
+	// списка подавления. Пусто - без категории
+	Category string
+
+	// Fallbacks - запасные маршруты по порядку: при окончательной неудаче отправки
+	// notify переключается на следующий из них
+	Fallbacks []Route
+
+	// Message - содержимое, отрендеренное по шаблону перед отправкой (не хранится).
+	// nil, если payload не ссылается на шаблон: тогда отправляется сам payload
+	Message *Message
 }
 
 func NewNotify() *Notify {
@@ -38,12 +79,100 @@ func NewNotify() *Notify {
 		UpdatedAt:  time.Now().UTC(),
 		RetryCount: 0,
 		LastError:  nil,
+		Version:    1,
 	}
 }
 
+// NotifyPatch - изменение Pending notify, nil-поля не меняются
+type NotifyPatch struct {
+	Payload     []byte
+	Target      *string
+	Channel     *string
+	ScheduledAt *time.Time
+}
+
+func (p NotifyPatch) Empty() bool {
+	return p.Payload == nil && p.Target == nil && p.Channel == nil && p.ScheduledAt == nil
+}
+
+// Fingerprint считает отпечаток полей, задаваемых клиентом при создании notify.
+// Используется для сравнения повторных запросов с одним и тем же ключом идемпотентности.
+// Необязательные поля входят в отпечаток, только если заданы, чтобы отпечатки
+// запросов, сохраненных до их появления, не изменились.
+func (n *Notify) Fingerprint() string {
+	h := sha256.New()
+	h.Write(n.Payload)
+	h.Write([]byte{0})
+	h.Write([]byte(n.Target))
+	h.Write([]byte{0})
+	h.Write([]byte(n.Channel))
+	h.Write([]byte{0})
+	h.Write([]byte(n.ScheduledAt.UTC().Format(time.RFC3339Nano)))
+	if n.Category != "" {
+		h.Write([]byte{0})
+		h.Write([]byte(n.Category))
+	}
+	if n.DeliveryWindow != nil {
+		raw, _ := json.Marshal(n.DeliveryWindow)
+		h.Write([]byte{0})
+		h.Write(raw)
+	}
+	if n.RetryPolicy != nil {
+		raw, _ := json.Marshal(n.RetryPolicy)
+		h.Write([]byte{0})
+		h.Write(raw)
+	}
+	if len(n.Fallbacks) > 0 {
+		raw, _ := json.Marshal(n.Fallbacks)
+		h.Write([]byte{0})
+		h.Write(raw)
+	}
+	// считается до разрешения получателя в маршруты, поэтому учитывает запрос, а не адреса
+	if n.RecipientID != "" {
+		h.Write([]byte{0})
+		h.Write([]byte(n.RecipientID))
+		for _, channel := range n.Channels {
+			h.Write([]byte{0})
+			h.Write([]byte(channel))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ValidatePayload проверяет payload для основного и всех запасных маршрутов:
+// при переключении notify уходит в другой канал с тем же payload
+func (n *Notify) ValidatePayload() error {
+	if err := ValidatePayload(n.Channel, n.Payload); err != nil {
+		return err
+	}
+	for _, r := range n.Fallbacks {
+		if err := ValidatePayload(r.Channel, n.Payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendAt - фактическое время отправки: ScheduledAt, сдвинутое в окно доставки
+func (n *Notify) SendAt() time.Time {
+	if n.DeliveryWindow == nil {
+		return n.ScheduledAt
+	}
+	return n.DeliveryWindow.Next(n.ScheduledAt)
+}
+
+// NotifyPostgres ограничивает все запросы арендатором из контекста (TenantFrom):
+// чужие notify для него не существуют
 type NotifyPostgres interface {
 	Create(ctx context.Context, n *Notify) error
+	// CreateBatch вставляет notify одним запросом в транзакции и возвращает ID вставленных.
+	// Notify, конфликтующие по ID или ключу идемпотентности, пропускаются; при atomic
+	// любой пропуск откатывает всю пачку с ErrBatchConflict (ID вставленных до отката возвращаются)
+	CreateBatch(ctx context.Context, notifies []*Notify, atomic bool) ([]string, error)
 	GetNotifyByID(ctx context.Context, id string) (*Notify, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (*Notify, error)
+	// UpdateStatus - переходы планировщика и воркеров. Отмененный notify не меняется:
+	// его отменили, пока он был InProcess, и возвращается ErrNotifyCanceled
 	UpdateStatus(
 		ctx context.Context,
 		id string,
@@ -52,27 +181,74 @@ type NotifyPostgres interface {
 		retryCount int,
 		lastErr *string,
 	) error
+	// Reschedule возвращает notify в StatusPending на scheduledAt без попытки доставки
+	// (лимиты, окно доставки): retry_count, last_error и история ошибок не меняются.
+	// Отмененный notify не меняется, возвращается ErrNotifyCanceled
+	Reschedule(ctx context.Context, id string, scheduledAt time.Time) error
+	// Update применяет patch к notify, пока он в StatusPending. version - ожидаемая
+	// версия (0 - без проверки); при несовпадении ErrVersionMismatch, не Pending - ErrNotifyNotEditable
+	Update(ctx context.Context, id string, patch NotifyPatch, version int) (*Notify, error)
+	// Cancel атомарно переводит Pending/InProcess notify в StatusCanceled
+	Cancel(ctx context.Context, id string) (*Notify, error)
 	DeleteByID(ctx context.Context, id string) error
 	LockAndFetchReady(ctx context.Context, limit int, visibilityTimeout time.Duration) ([]*Notify, error)
-	List(ctx context.Context, limit, offset int) ([]*Notify, error)
+	// CountPending возвращает число notify, ожидающих отправки
+	CountPending(ctx context.Context) (int, error)
+	List(ctx context.Context, filter NotifyFilter) ([]*Notify, error)
+	// ListDeadLetters возвращает notify в StatusFailed по фильтру (статусы фильтра игнорируются)
+	ListDeadLetters(ctx context.Context, filter NotifyFilter) ([]*DeadLetter, error)
+	// Requeue возвращает StatusFailed notify в очередь: StatusPending, retry_count = 0, отправка сейчас
+	Requeue(ctx context.Context, id string) (*Notify, error)
+	// Fallback переключает notify на первый запасной маршрут: он становится Channel/Target
+	// и убирается из Fallbacks, повторы обнуляются, отправка - сейчас. Без запасных маршрутов - ErrNoRoute
+	Fallback(ctx context.Context, id string, lastErr *string) (*Notify, error)
+	// RequeueFailed делает Requeue для всех failed notify по фильтру и возвращает их ID
+	RequeueFailed(ctx context.Context, filter NotifyFilter) ([]string, error)
+	RecordAttempt(ctx context.Context, a *Attempt) error
+	// ListAttempts возвращает попытки notify в хронологическом порядке
+	ListAttempts(ctx context.Context, notifyID string) ([]*Attempt, error)
+	Ping(ctx context.Context) error
 	Close() error
 }
 
 type NotifyUsecase interface {
 	Save(ctx context.Context, n *Notify) (string, error)
+	// SaveBatch создает пачку notify, результаты идут в порядке входа.
+	// При atomic пачка создается целиком или не создается вовсе (ErrBatchConflict)
+	SaveBatch(ctx context.Context, notifies []*Notify, atomic bool) ([]BatchResult, error)
 	GetByID(ctx context.Context, id string) (*Notify, error)
+	Update(ctx context.Context, id string, patch NotifyPatch, version int) (*Notify, error)
+	Cancel(ctx context.Context, id string) (*Notify, error)
 	Delete(ctx context.Context, id string) error
-	List(ctx context.Context, limit, offset int) ([]*Notify, error)
+	List(ctx context.Context, filter NotifyFilter) (*NotifyPage, error)
+	ListDeadLetters(ctx context.Context, filter NotifyFilter) (*DeadLetterPage, error)
+	Retry(ctx context.Context, id string) (*Notify, error)
+	Replay(ctx context.Context, filter NotifyFilter) (int, error)
+	Attempts(ctx context.Context, id string) ([]*Attempt, error)
 }
 
 type NotifyRedis interface {
 	SetWithExpiration(ctx context.Context, n *Notify) error
 	Get(ctx context.Context, id string) (*Notify, error)
+	// UpdateStatus обновляет статус закешированного notify, сохраняя TTL, и поднимает версию, как БД.
+	// Если notify нет в кеше, ничего не делает
+	UpdateStatus(
+		ctx context.Context,
+		id string,
+		status Status,
+		scheduledAt *time.Time,
+		retryCount int,
+		lastErr *string,
+	) error
+	Delete(ctx context.Context, id string) error
+	Ping(ctx context.Context) error
 	Close() error
 }
 
 type Scheduler interface {
 	Run(ctx context.Context)
+	// LastTick - время последнего завершенного тика, нулевое до первого тика
+	LastTick() time.Time
 }
 
 type MessageHandler func(ctx context.Context, payload []byte) error
@@ -81,9 +257,13 @@ type QueueProvider interface {
 	Init() error
 	Publish(ctx context.Context, n *Notify) error
 	Consume(ctx context.Context, handler MessageHandler) error
+	// Ping проверяет, что соединение с брокером живо и канал открывается
+	Ping(ctx context.Context) error
 	Close() error
 }
 
+// Sender отправляет notify провайдеру. Delivery может быть nil,
+// если провайдер не возвращает идентификатор сообщения.
 type Sender interface {
-	Send(ctx context.Context, n *Notify) error
+	Send(ctx context.Context, n *Notify) (*Delivery, error)
 }
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidRateLimit = errors.New("invalid rate limit")
+
+// RateLimit - token bucket: Limit отправок за Period с запасом Burst (по умолчанию Limit).
+// Нулевой Limit - без ограничения
+type RateLimit struct {
+	Limit  int           `mapstructure:"limit"`
+	Period time.Duration `mapstructure:"period"`
+	Burst  int           `mapstructure:"burst"`
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Limit > 0
+}
+
+func (l RateLimit) Validate() error {
+	if l.Limit < 0 || l.Burst < 0 {
+		return fmt.Errorf("%w: negative limit", ErrInvalidRateLimit)
+	}
+	if !l.Enabled() {
+		return nil
+	}
+	if l.Period <= 0 {
+		return fmt.Errorf("%w: period must be positive", ErrInvalidRateLimit)
+	}
+	// бакеты считают время в микросекундах
+	if l.Interval() < time.Microsecond {
+		return fmt.Errorf("%w: limit is too high for period %v", ErrInvalidRateLimit, l.Period)
+	}
+	return nil
+}
+
+// Capacity - емкость бакета
+func (l RateLimit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Limit
+}
+
+// Interval - за сколько в бакете появляется один токен
+func (l RateLimit) Interval() time.Duration {
+	return l.Period / time.Duration(l.Limit)
+}
+
+// RateBucket - бакет, из которого берется токен перед отправкой
+type RateBucket struct {
+	Key   string
+	Limit RateLimit
+}
+
+// RateLimits - лимиты доставки по каналу, получателю и арендатору.
+// Tenant - лимит каждого арендатора, Tenants - переопределения для отдельных арендаторов
+type RateLimits struct {
+	Channels map[string]RateLimit `mapstructure:"channels"`
+	Target   RateLimit            `mapstructure:"target"`
+	Tenant   RateLimit            `mapstructure:"tenant"`
+	Tenants  map[string]RateLimit `mapstructure:"tenants"`
+}
+
+func (r RateLimits) Validate() error {
+	for channel, l := range r.Channels {
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("channels.%s: %w", channel, err)
+		}
+	}
+	if err := r.Target.Validate(); err != nil {
+		return fmt.Errorf("target: %w", err)
+	}
+	if err := r.Tenant.Validate(); err != nil {
+		return fmt.Errorf("tenant: %w", err)
+	}
+	for tenant, l := range r.Tenants {
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("tenants.%s: %w", tenant, err)
+		}
+	}
+	return nil
+}
+
+// Buckets возвращает бакеты, через которые должна пройти отправка notify.
+// Пустой результат - notify не ограничен
+func (r RateLimits) Buckets(n *Notify) []RateBucket {
+	var buckets []RateBucket
+	if l, ok := r.Channels[n.Channel]; ok && l.Enabled() {
+		buckets = append(buckets, RateBucket{Key: "channel:" + n.Channel, Limit: l})
+	}
+	if r.Target.Enabled() {
+		buckets = append(buckets, RateBucket{Key: "target:" + n.Channel + ":" + n.Target, Limit: r.Target})
+	}
+
+	tenant := n.TenantID
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+	l, ok := r.Tenants[tenant]
+	if !ok {
+		l = r.Tenant
+	}
+	if l.Enabled() {
+		buckets = append(buckets, RateBucket{Key: "tenant:" + tenant, Limit: l})
+	}
+	return buckets
+}
+
+// RateLimiter - общее для всех экземпляров хранилище бакетов
+type RateLimiter interface {
+	// Take атомарно берет по токену из каждого бакета. Если хотя бы в одном токенов нет,
+	// ничего не списывает и возвращает, через сколько стоит повторить
+	Take(ctx context.Context, buckets []RateBucket) (wait time.Duration, err error)
+	Close() error
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+)
+
+// Recipient - получатель из реестра: адреса в каналах и предпочтения доставки.
+// Notify может адресовать получателя по ID вместо конкретных канала и адреса
+type Recipient struct {
+	ID       string
+	TenantID string
+	Name     string
+	// Contacts - адрес получателя в каждом канале: email, chat id Telegram, URL вебхука
+	Contacts map[string]string
+	// Channels - предпочтительный порядок каналов, пусто - каналы с контактами по алфавиту
+	Channels []string
+	// OptOuts - каналы, от которых получатель отказался
+	OptOuts []string
+	// DeliveryWindow - окно доставки notify получателя, если у notify нет своего
+	DeliveryWindow *DeliveryWindow
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Route - канал и адрес, по которым отправляется notify
+type Route struct {
+	Channel string `json:"channel"`
+	Target  string `json:"target"`
+}
+
+func NewRecipient() *Recipient {
+	return &Recipient{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+func (r *Recipient) Validate() error {
+	if len(r.Contacts) == 0 {
+		return fmt.Errorf("%w: at least one contact is required", ErrInvalidRecipient)
+	}
+	for channel, target := range r.Contacts {
+		if channel == "" || target == "" {
+			return fmt.Errorf("%w: contact channel and target are required", ErrInvalidRecipient)
+		}
+	}
+	for i, channel := range r.Channels {
+		if _, ok := r.Contacts[channel]; !ok {
+			return fmt.Errorf("%w: no contact for preferred channel %q", ErrInvalidRecipient, channel)
+		}
+		if slices.Contains(r.Channels[:i], channel) {
+			return fmt.Errorf("%w: duplicate preferred channel %q", ErrInvalidRecipient, channel)
+		}
+	}
+	for _, channel := range r.OptOuts {
+		if channel == "" {
+			return fmt.Errorf("%w: empty opt-out channel", ErrInvalidRecipient)
+		}
+	}
+	if r.DeliveryWindow != nil {
+		if err := r.DeliveryWindow.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRecipient, err)
+		}
+	}
+	return nil
+}
+
+// Routes возвращает маршруты доставки в порядке channels (пусто - порядок получателя).
+// Каналы без контакта и каналы, от которых получатель отказался, пропускаются
+func (r *Recipient) Routes(channels []string) ([]Route, error) {
+	if len(channels) == 0 {
+		channels = r.Channels
+	}
+	if len(channels) == 0 {
+		for channel := range r.Contacts {
+			channels = append(channels, channel)
+		}
+		slices.Sort(channels)
+	}
+
+	var routes []Route
+	for i, channel := range channels {
+		target, ok := r.Contacts[channel]
+		if !ok || slices.Contains(r.OptOuts, channel) || slices.Contains(channels[:i], channel) {
+			continue
+		}
+		routes = append(routes, Route{Channel: channel, Target: target})
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("%w: recipient %s", ErrNoRoute, r.ID)
+	}
+	return routes, nil
+}
+
+// RecipientPostgres, как и NotifyPostgres, ограничивает запросы арендатором из контекста
+type RecipientPostgres interface {
+	CreateRecipient(ctx context.Context, r *Recipient) error
+	GetRecipientByID(ctx context.Context, id string) (*Recipient, error)
+	ListRecipients(ctx context.Context, limit, offset int) ([]*Recipient, error)
+	// UpdateRecipient заменяет контакты и предпочтения получателя
+	UpdateRecipient(ctx context.Context, r *Recipient) error
+	// DeleteRecipient удаляет получателя. Созданные для него notify остаются
+	// со своими маршрутами, но теряют ссылку на получателя
+	DeleteRecipient(ctx context.Context, id string) error
+}
+
+type RecipientUsecase interface {
+	Create(ctx context.Context, r *Recipient) (*Recipient, error)
+	GetByID(ctx context.Context, id string) (*Recipient, error)
+	List(ctx context.Context, limit, offset int) ([]*Recipient, error)
+	Update(ctx context.Context, r *Recipient) (*Recipient, error)
+	Delete(ctx context.Context, id string) error
+}
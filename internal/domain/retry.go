@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryKind string
+
+const (
+	RetryFixed       RetryKind = "fixed"       // одна и та же задержка Delay
+	RetryExponential RetryKind = "exponential" // Delay * Multiplier^(attempt-1) с разбросом Jitter
+	RetryCustom      RetryKind = "custom"      // задержки по списку Schedule
+)
+
+const defaultRetryMultiplier = 2
+
+// RetryPolicy описывает, когда повторять неудачную отправку.
+// MaxAttempts - общее число попыток, включая первую. MaxAge ограничивает возраст
+// notify: попытку позже CreatedAt + MaxAge не планируем, даже если попытки остались.
+type RetryPolicy struct {
+	Kind        RetryKind       `mapstructure:"kind" json:"kind"`
+	MaxAttempts int             `mapstructure:"max_attempts" json:"max_attempts,omitempty"`
+	Delay       time.Duration   `mapstructure:"delay" json:"delay,omitempty"`
+	MaxDelay    time.Duration   `mapstructure:"max_delay" json:"max_delay,omitempty"`
+	Multiplier  float64         `mapstructure:"multiplier" json:"multiplier,omitempty"`
+	Jitter      float64         `mapstructure:"jitter" json:"jitter,omitempty"`
+	Schedule    []time.Duration `mapstructure:"schedule" json:"schedule,omitempty"`
+	MaxAge      time.Duration   `mapstructure:"max_age" json:"max_age,omitempty"`
+}
+
+// DefaultRetryPolicy - экспоненциальная задержка от минуты до часа
+func DefaultRetryPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{
+		Kind:        RetryExponential,
+		MaxAttempts: maxAttempts,
+		Delay:       time.Minute,
+		MaxDelay:    time.Hour,
+		Multiplier:  defaultRetryMultiplier,
+		Jitter:      0.1,
+	}
+}
+
+func (p RetryPolicy) Validate() error {
+	switch p.Kind {
+	case RetryFixed, RetryExponential:
+		if p.Delay <= 0 {
+			return fmt.Errorf("%w: delay must be positive", ErrInvalidRetryPolicy)
+		}
+	case RetryCustom:
+		if len(p.Schedule) == 0 {
+			return fmt.Errorf("%w: schedule must not be empty", ErrInvalidRetryPolicy)
+		}
+		for _, d := range p.Schedule {
+			if d <= 0 {
+				return fmt.Errorf("%w: schedule delays must be positive", ErrInvalidRetryPolicy)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidRetryPolicy, p.Kind)
+	}
+	if p.MaxAttempts < 0 || p.MaxDelay < 0 || p.MaxAge < 0 || p.Multiplier < 0 {
+		return fmt.Errorf("%w: negative limits", ErrInvalidRetryPolicy)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("%w: jitter must be in [0, 1]", ErrInvalidRetryPolicy)
+	}
+	return nil
+}
+
+// Next возвращает время следующей попытки после неудачной попытки номер attempt (с 1).
+// Второе значение false, если попытки исчерпаны или notify старше MaxAge.
+func (p RetryPolicy) Next(attempt int, createdAt, now time.Time) (time.Time, bool) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts == 0 && p.Kind == RetryCustom {
+		maxAttempts = len(p.Schedule) + 1
+	}
+	if attempt >= maxAttempts {
+		return time.Time{}, false
+	}
+
+	next := now.Add(p.delay(attempt))
+	if p.MaxAge > 0 && !createdAt.IsZero() && next.After(createdAt.Add(p.MaxAge)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	switch p.Kind {
+	case RetryCustom:
+		// после конца списка повторяем последнюю задержку
+		return p.Schedule[min(attempt, len(p.Schedule))-1]
+	case RetryExponential:
+		multiplier := p.Multiplier
+		if multiplier == 0 {
+			multiplier = defaultRetryMultiplier
+		}
+		d := float64(p.Delay) * math.Pow(multiplier, float64(attempt-1))
+		if p.MaxDelay > 0 {
+			d = math.Min(d, float64(p.MaxDelay))
+		}
+		// разброс ±Jitter, чтобы повторы после сбоя провайдера не шли одной волной
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+		return time.Duration(d)
+	default:
+		return p.Delay
+	}
+}
+
+// RetryPolicies выбирает политику для notify: собственная политика notify,
+// затем политика канала, затем политика по умолчанию.
+// MaxAttempts - число попыток для политик notify без max_attempts (кроме custom,
+// где число попыток задает список задержек)
+type RetryPolicies struct {
+	Default     RetryPolicy
+	Channels    map[string]RetryPolicy
+	MaxAttempts int
+}
+
+func (p RetryPolicies) For(n *Notify) RetryPolicy {
+	if n.RetryPolicy != nil {
+		policy := *n.RetryPolicy
+		if policy.MaxAttempts == 0 && policy.Kind != RetryCustom {
+			policy.MaxAttempts = p.MaxAttempts
+		}
+		return policy
+	}
+	if policy, ok := p.Channels[n.Channel]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// RetryAfterError - временная ошибка, для которой провайдер сообщил,
+// что повторять стоит не раньше чем через After (Telegram retry_after, HTTP Retry-After)
+type RetryAfterError struct {
+	After time.Duration
+	Err   error
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.After)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter достает подсказку о задержке повтора из цепочки ошибок
+func RetryAfter(err error) (time.Duration, bool) {
+	var ra *RetryAfterError
+	if errors.As(err, &ra) && ra.After > 0 {
+		return ra.After, true
+	}
+	return 0, false
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+type ScheduleStatus int
+
+const (
+	ScheduleActive   ScheduleStatus = iota // 0 - серия активна
+	SchedulePaused                         // 1 - приостановлена пользователем
+	ScheduleFinished                       // 2 - достигнута дата окончания
+)
+
+const minScheduleInterval = time.Minute
+
+// Schedule - серия повторяющихся уведомлений.
+// Правило повторения задается либо cron-выражением (CronExpr), либо фиксированным
+// интервалом (Interval) от StartAt. Cron вычисляется в часовом поясе Timezone.
+type Schedule struct {
+	ID        string
+	TenantID  string
+	Payload   []byte
+	Target    string
+	Channel   string
+	CronExpr  string
+	Interval  time.Duration
+	Timezone  string
+	StartAt   time.Time
+	EndAt     *time.Time
+	NextRunAt *time.Time
+	Status    ScheduleStatus
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewSchedule() *Schedule {
+	return &Schedule{
+		ID:        uuid.New(),
+		Status:    ScheduleActive,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+// Validate проверяет правило повторения и часовой пояс.
+func (s *Schedule) Validate() error {
+	if (s.CronExpr == "") == (s.Interval == 0) {
+		return fmt.Errorf("%w: exactly one of cron or interval must be set", ErrInvalidSchedule)
+	}
+	if s.Interval != 0 && s.Interval < minScheduleInterval {
+		return fmt.Errorf("%w: interval must be at least %s", ErrInvalidSchedule, minScheduleInterval)
+	}
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, s.Timezone)
+	}
+	if s.CronExpr != "" {
+		if _, err := s.cronSchedule(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+	}
+	if s.EndAt != nil && !s.EndAt.After(s.StartAt) {
+		return fmt.Errorf("%w: end_at must be after start_at", ErrInvalidSchedule)
+	}
+	if err := ValidatePayload(s.Channel, s.Payload); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	return nil
+}
+
+// Next возвращает первое срабатывание строго после after, не раньше StartAt.
+// Второе значение false, если серия закончилась (следующее срабатывание позже EndAt).
+func (s *Schedule) Next(after time.Time) (time.Time, bool, error) {
+	var next time.Time
+	if s.CronExpr != "" {
+		sched, err := s.cronSchedule()
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if after.Before(s.StartAt) {
+			// cron ищет строго после переданного момента, а StartAt тоже может совпасть с правилом
+			after = s.StartAt.Add(-time.Second)
+		}
+		next = sched.Next(after)
+		if next.IsZero() {
+			return time.Time{}, false, nil
+		}
+	} else {
+		if s.Interval <= 0 {
+			return time.Time{}, false, ErrInvalidSchedule
+		}
+		next = s.StartAt
+		if !after.Before(s.StartAt) {
+			steps := after.Sub(s.StartAt)/s.Interval + 1
+			next = s.StartAt.Add(steps * s.Interval)
+		}
+	}
+
+	if s.EndAt != nil && next.After(*s.EndAt) {
+		return time.Time{}, false, nil
+	}
+	return next.UTC(), true, nil
+}
+
+func (s *Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+func (s *Schedule) cronSchedule() (cron.Schedule, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, err
+	}
+	return cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", loc.String(), s.CronExpr))
+}
+
+// SchedulePostgres, как и NotifyPostgres, ограничивает запросы арендатором из контекста
+type SchedulePostgres interface {
+	CreateSchedule(ctx context.Context, s *Schedule) error
+	GetScheduleByID(ctx context.Context, id string) (*Schedule, error)
+	ListSchedules(ctx context.Context, limit, offset int) ([]*Schedule, error)
+	UpdateScheduleState(ctx context.Context, id string, status ScheduleStatus, nextRunAt *time.Time) error
+	// DeleteSchedule удаляет серию, отменяет ее еще не отправленные notify и возвращает их ID
+	DeleteSchedule(ctx context.Context, id string) ([]string, error)
+	// MaterializeDue создает notify для наступивших срабатываний активных серий
+	// и сдвигает next_run_at на следующее срабатывание. Возвращает число созданных notify.
+	MaterializeDue(ctx context.Context, limit int) (int, error)
+}
+
+type ScheduleUsecase interface {
+	Create(ctx context.Context, s *Schedule) (*Schedule, error)
+	GetByID(ctx context.Context, id string) (*Schedule, error)
+	List(ctx context.Context, limit, offset int) ([]*Schedule, error)
+	Pause(ctx context.Context, id string) (*Schedule, error)
+	Resume(ctx context.Context, id string) (*Schedule, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+)
+
+const maxCategoryLen = 64
+
+type SuppressionReason string
+
+const (
+	ReasonManual      SuppressionReason = "manual"      // добавлено через API
+	ReasonUnsubscribe SuppressionReason = "unsubscribe" // получатель отписался по ссылке из письма
+	ReasonBounce      SuppressionReason = "bounce"      // адрес не существует или отклоняет почту
+	ReasonComplaint   SuppressionReason = "complaint"   // получатель пожаловался на рассылку
+)
+
+// Suppression - запрет отправки в канал Channel на адрес Target.
+// Пустые TenantID и Category - запрет для всех арендаторов и всех категорий
+type Suppression struct {
+	ID        string
+	TenantID  string
+	Channel   string
+	Target    string
+	Category  string
+	Reason    SuppressionReason
+	CreatedAt time.Time
+}
+
+func NewSuppression() *Suppression {
+	return &Suppression{
+		ID:        uuid.New(),
+		Reason:    ReasonManual,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Validate проверяет запись и приводит адрес к виду, в котором он хранится
+func (s *Suppression) Validate() error {
+	s.Target = NormalizeTarget(s.Channel, s.Target)
+	if s.Channel == "" || s.Target == "" {
+		return fmt.Errorf("%w: channel and target are required", ErrInvalidSuppression)
+	}
+	switch s.Reason {
+	case ReasonManual, ReasonUnsubscribe, ReasonBounce, ReasonComplaint:
+	default:
+		return fmt.Errorf("%w: unknown reason %q", ErrInvalidSuppression, s.Reason)
+	}
+	if err := ValidateCategory(s.Category); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSuppression, err)
+	}
+	return nil
+}
+
+// Matches сообщает, запрещает ли запись отправку notify
+func (s *Suppression) Matches(n *Notify) bool {
+	return s.Channel == n.Channel &&
+		s.Target == NormalizeTarget(n.Channel, n.Target) &&
+		(s.TenantID == "" || s.TenantID == tenantOf(n)) &&
+		(s.Category == "" || s.Category == n.Category)
+}
+
+// tenantOf - арендатор notify, созданного до появления арендаторов, - DefaultTenant
+func tenantOf(n *Notify) string {
+	if n.TenantID == "" {
+		return DefaultTenant
+	}
+	return n.TenantID
+}
+
+// NormalizeTarget приводит адрес к виду для сравнения: email не зависит от регистра
+func NormalizeTarget(channel, target string) string {
+	target = strings.TrimSpace(target)
+	if channel == ChannelEmail {
+		return strings.ToLower(target)
+	}
+	return target
+}
+
+// ValidateCategory - категория notify и записи подавления: до 64 символов из a-z, 0-9, '.', '_', '-'
+func ValidateCategory(category string) error {
+	if len(category) > maxCategoryLen {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidCategory, maxCategoryLen)
+	}
+	for _, r := range category {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '.' && r != '_' && r != '-' {
+			return fmt.Errorf("%w: unexpected character %q", ErrInvalidCategory, r)
+		}
+	}
+	return nil
+}
+
+// SuppressionFilter - фильтр списка записей, пустые поля не ограничивают
+type SuppressionFilter struct {
+	Channel string
+	Target  string
+	Limit   int
+	Offset  int
+}
+
+// SuppressionPostgres, как и NotifyPostgres, ограничивает запросы арендатором из контекста.
+// Записи для всех арендаторов видны каждому, а удалять их может только DefaultTenant
+type SuppressionPostgres interface {
+	// CreateSuppression сохраняет запись как есть, включая TenantID.
+	// Такая же запись (канал, адрес, арендатор, категория) уже есть - ErrSuppressionExists
+	CreateSuppression(ctx context.Context, s *Suppression) error
+	ListSuppressions(ctx context.Context, filter SuppressionFilter) ([]*Suppression, error)
+	DeleteSuppression(ctx context.Context, id string) error
+	// FindSuppression возвращает запись, запрещающую отправку n, или ErrSuppressionNotFound
+	FindSuppression(ctx context.Context, n *Notify) (*Suppression, error)
+}
+
+type SuppressionUsecase interface {
+	// Create добавляет запись для арендатора из контекста, allTenants - для всех арендаторов
+	Create(ctx context.Context, s *Suppression, allTenants bool) (*Suppression, error)
+	List(ctx context.Context, filter SuppressionFilter) ([]*Suppression, error)
+	Delete(ctx context.Context, id string) error
+	// Unsubscribe добавляет запись по токену из ссылки отписки. Повторная отписка не ошибка
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+// UnsubscribeConfig - ссылки отписки в один клик (RFC 8058) в письмах.
+// Ссылка ведет на BaseURL/unsubscribe/<token>, токен подписан Secret.
+// Без BaseURL письма уходят без ссылки
+type UnsubscribeConfig struct {
+	BaseURL string `mapstructure:"base_url"`
+	Secret  string `mapstructure:"secret"`
+}
+
+func (c UnsubscribeConfig) Enabled() bool {
+	return c.BaseURL != ""
+}
+
+func (c UnsubscribeConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	u, err := url.Parse(c.BaseURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("base_url must be an absolute http(s) url")
+	}
+	if c.Secret == "" {
+		return fmt.Errorf("secret is required")
+	}
+	return nil
+}
+
+// unsubscribeClaims - содержимое токена отписки
+type unsubscribeClaims struct {
+	Tenant   string `json:"t"`
+	Channel  string `json:"c"`
+	Target   string `json:"a"`
+	Category string `json:"k,omitempty"`
+}
+
+// URL возвращает ссылку, по которой адресат n отписывается от категории notify
+func (c UnsubscribeConfig) URL(n *Notify) string {
+	raw, _ := json.Marshal(unsubscribeClaims{
+		Tenant:   tenantOf(n),
+		Channel:  n.Channel,
+		Target:   NormalizeTarget(n.Channel, n.Target),
+		Category: n.Category,
+	})
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	token := payload + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+	return strings.TrimSuffix(c.BaseURL, "/") + "/unsubscribe/" + token
+}
+
+// ParseToken проверяет подпись токена отписки и возвращает запись подавления
+func (c UnsubscribeConfig) ParseToken(token string) (*Suppression, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok || c.Secret == "" {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, c.sign(payload)) {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	var claims unsubscribeClaims
+	if err := json.Unmarshal(raw, &claims); err != nil || claims.Tenant == "" {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+
+	s := NewSuppression()
+	s.TenantID = claims.Tenant
+	s.Channel = claims.Channel
+	s.Target = claims.Target
+	s.Category = claims.Category
+	s.Reason = ReasonUnsubscribe
+	return s, nil
+}
+
+func (c UnsubscribeConfig) sign(payload string) []byte {
+	h := hmac.New(sha256.New, []byte(c.Secret))
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
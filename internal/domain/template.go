@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/pkg/utils/uuid"
+)
+
+// Template - именованный шаблон сообщения для канала.
+// Каждое изменение создает новую версию, старые версии остаются доступны.
+// Subject и Text рендерятся через text/template, HTML - через html/template.
+type Template struct {
+	ID        string
+	TenantID  string
+	Name      string
+	Channel   string
+	Version   int
+	Subject   string
+	Text      string
+	HTML      string
+	CreatedAt time.Time
+}
+
+// Message - содержимое сообщения, отрендеренное по шаблону
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// TemplatePayload - payload notify, ссылающийся на шаблон:
+// {"template": "welcome", "version": 2, "vars": {"name": "Ivan"}}.
+// Version 0 означает последнюю версию шаблона.
+type TemplatePayload struct {
+	Template string         `json:"template"`
+	Version  int            `json:"version,omitempty"`
+	Vars     map[string]any `json:"vars,omitempty"`
+}
+
+func NewTemplate() *Template {
+	return &Template{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// ParseTemplatePayload возвращает ссылку на шаблон, если payload ее содержит.
+// Любой другой payload отправляется как есть.
+func ParseTemplatePayload(payload []byte) (*TemplatePayload, bool) {
+	var p TemplatePayload
+	if err := json.Unmarshal(payload, &p); err != nil || p.Template == "" {
+		return nil, false
+	}
+	return &p, true
+}
+
+// Validate проверяет обязательные поля и синтаксис всех частей шаблона.
+func (t *Template) Validate() error {
+	if t.Name == "" || t.Channel == "" {
+		return fmt.Errorf("%w: name and channel are required", ErrInvalidTemplate)
+	}
+	if t.Text == "" && t.HTML == "" {
+		return fmt.Errorf("%w: text or html body is required", ErrInvalidTemplate)
+	}
+	if _, err := t.parse(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return nil
+}
+
+// Render подставляет переменные в шаблон. Отсутствующая переменная - ошибка,
+// чтобы не отправлять получателю сообщение с пустыми местами.
+func (t *Template) Render(vars map[string]any) (*Message, error) {
+	p, err := t.parse()
+	if err != nil {
+		return nil, fmt.Errorf("%w %s v%d: %v", ErrTemplateRender, t.Name, t.Version, err)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := p.subject.Execute(&subject, vars); err != nil {
+		return nil, fmt.Errorf("%w %s v%d: %v", ErrTemplateRender, t.Name, t.Version, err)
+	}
+	if err := p.text.Execute(&text, vars); err != nil {
+		return nil, fmt.Errorf("%w %s v%d: %v", ErrTemplateRender, t.Name, t.Version, err)
+	}
+	if err := p.html.Execute(&html, vars); err != nil {
+		return nil, fmt.Errorf("%w %s v%d: %v", ErrTemplateRender, t.Name, t.Version, err)
+	}
+
+	return &Message{
+		Subject: subject.String(),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+type parsedTemplate struct {
+	subject *template.Template
+	text    *template.Template
+	html    *htmltemplate.Template
+}
+
+func (t *Template) parse() (*parsedTemplate, error) {
+	subject, err := template.New("subject").Option("missingkey=error").Parse(t.Subject)
+	if err != nil {
+		return nil, err
+	}
+	text, err := template.New("text").Option("missingkey=error").Parse(t.Text)
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("html").Option("missingkey=error").Parse(t.HTML)
+	if err != nil {
+		return nil, err
+	}
+	return &parsedTemplate{subject: subject, text: text, html: html}, nil
+}
+
+// TemplatePostgres, как и NotifyPostgres, ограничивает запросы арендатором из контекста:
+// у каждого арендатора свои шаблоны с теми же именами
+type TemplatePostgres interface {
+	// CreateTemplateVersion сохраняет новую версию шаблона, номер версии проставляется в t.Version
+	CreateTemplateVersion(ctx context.Context, t *Template) error
+	// GetTemplate возвращает версию шаблона, version 0 - последнюю
+	GetTemplate(ctx context.Context, name, channel string, version int) (*Template, error)
+	// ListTemplates возвращает последние версии всех шаблонов
+	ListTemplates(ctx context.Context) ([]*Template, error)
+	ListTemplateVersions(ctx context.Context, name, channel string) ([]*Template, error)
+	// DeleteTemplate удаляет все версии шаблона
+	DeleteTemplate(ctx context.Context, name, channel string) error
+}
+
+type TemplateUsecase interface {
+	Create(ctx context.Context, t *Template) (*Template, error)
+	Update(ctx context.Context, t *Template) (*Template, error)
+	Get(ctx context.Context, name, channel string, version int) (*Template, error)
+	List(ctx context.Context) ([]*Template, error)
+	Versions(ctx context.Context, name, channel string) ([]*Template, error)
+	Delete(ctx context.Context, name, channel string) error
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "context"
+
+// DefaultTenant - арендатор notify, созданных без авторизации и до ее появления
+const DefaultTenant = "default"
+
+type (
+	tenantKey     struct{}
+	allTenantsKey struct{}
+)
+
+// WithTenant ограничивает все запросы к хранилищам с этим контекстом одним арендатором
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom возвращает арендатора запроса. ok == false - системный контекст
+// (планировщик, воркер), которому видны notify всех арендаторов
+func TenantFrom(ctx context.Context) (tenant string, ok bool) {
+	tenant, ok = ctx.Value(tenantKey{}).(string)
+	return tenant, ok
+}
+
+// TenantFor - арендатор нового notify: из контекста, иначе уже заданный, иначе DefaultTenant
+func TenantFor(ctx context.Context, current string) string {
+	if tenant, ok := TenantFrom(ctx); ok {
+		return tenant
+	}
+	if current != "" {
+		return current
+	}
+	return DefaultTenant
+}
+
+// Visible сообщает, виден ли объект арендатора tenant в контексте ctx
+func Visible(ctx context.Context, tenant string) bool {
+	current, ok := TenantFrom(ctx)
+	return !ok || current == tenant
+}
+
+// WithAllTenants разрешает арендатору из контекста управлять записями для всех
+// арендаторов (список подавления). Выдается только DefaultTenant, вошедшему по
+// API-ключу: при выключенной аутентификации default - любой клиент
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsKey{}, true)
+}
+
+// ManagesAllTenants сообщает, может ли контекст управлять записями для всех арендаторов.
+// Системному контексту это разрешено
+func ManagesAllTenants(ctx context.Context) bool {
+	tenant, ok := TenantFrom(ctx)
+	if !ok {
+		return true
+	}
+	granted, _ := ctx.Value(allTenantsKey{}).(bool)
+	return granted && tenant == DefaultTenant
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidDeliveryWindow = errors.New("invalid delivery window")
+
+const minutesPerDay = 24 * 60
+
+// TimeOfDay - время суток в минутах от полуночи, 24:00 - конец суток
+type TimeOfDay int
+
+// ParseTimeOfDay разбирает время в формате HH:MM
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || len(s) != len("15:04") {
+		return 0, fmt.Errorf("%w: time %q must be HH:MM", ErrInvalidDeliveryWindow, s)
+	}
+	t := TimeOfDay(h*60 + m)
+	if h < 0 || m < 0 || m > 59 || t > minutesPerDay {
+		return 0, fmt.Errorf("%w: time %q out of range", ErrInvalidDeliveryWindow, s)
+	}
+	return t, nil
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *TimeOfDay) UnmarshalText(b []byte) error {
+	parsed, err := ParseTimeOfDay(string(b))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// DeliveryWindow - когда получателю можно доставлять уведомления: с Start до End
+// по местному времени Timezone в дни Weekdays (пусто - каждый день).
+// Start > End - окно через полночь, день недели считается по началу окна.
+type DeliveryWindow struct {
+	Timezone string         `json:"timezone"`
+	Start    TimeOfDay      `json:"start"`
+	End      TimeOfDay      `json:"end"`
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+}
+
+func (w DeliveryWindow) Validate() error {
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidDeliveryWindow, w.Timezone)
+	}
+	if w.Start < 0 || w.Start >= minutesPerDay || w.End <= 0 || w.End > minutesPerDay {
+		return fmt.Errorf("%w: time out of range", ErrInvalidDeliveryWindow)
+	}
+	if w.Start == w.End {
+		return fmt.Errorf("%w: start and end must differ", ErrInvalidDeliveryWindow)
+	}
+	for _, d := range w.Weekdays {
+		if d < time.Sunday || d > time.Saturday {
+			return fmt.Errorf("%w: unknown weekday %d", ErrInvalidDeliveryWindow, d)
+		}
+	}
+	return nil
+}
+
+// Next возвращает ближайший момент не раньше t, попадающий в окно.
+// Если t уже в окне, возвращается t
+func (w DeliveryWindow) Next(t time.Time) time.Time {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		// окно проверяется при создании notify, битое окно не должно блокировать доставку
+		return t
+	}
+
+	local := t.In(loc)
+	y, m, d := local.Date()
+	// с предыдущего дня - окно через полночь могло открыться вчера
+	for offset := -1; offset <= 7; offset++ {
+		day := time.Date(y, m, d+offset, 0, 0, 0, 0, loc)
+		if !w.allowed(day.Weekday()) {
+			continue
+		}
+
+		open := w.at(day, w.Start)
+		closeDay := day
+		if w.End <= w.Start {
+			closeDay = day.AddDate(0, 0, 1)
+		}
+		closeAt := w.at(closeDay, w.End)
+
+		if !local.Before(open) && local.Before(closeAt) {
+			return t
+		}
+		if open.After(local) {
+			return open.UTC()
+		}
+	}
+	return t
+}
+
+// Contains сообщает, попадает ли t в окно
+func (w DeliveryWindow) Contains(t time.Time) bool {
+	return w.Next(t).Equal(t)
+}
+
+func (w DeliveryWindow) allowed(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.Weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// at - момент времени tod в сутках day. Через time.Date, чтобы учесть переход на летнее время
+func (w DeliveryWindow) at(day time.Time, tod TimeOfDay) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, int(tod)/60, int(tod)%60, 0, 0, day.Location())
+}
@@ -5,3 +5,9 @@ package mocks
 //go:generate mockgen -destination=mock_queue.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain QueueProvider
 //go:generate mockgen -destination=mock_usecase.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain NotifyUsecase
 //go:generate mockgen -destination=mock_sender.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain Sender
+//go:generate mockgen -destination=mock_schedule.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain SchedulePostgres,ScheduleUsecase
+//go:generate mockgen -destination=mock_template.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain TemplatePostgres,TemplateUsecase
+//go:generate mockgen -destination=mock_metrics.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain Metrics
+//go:generate mockgen -destination=mock_ratelimit.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain RateLimiter
+//go:generate mockgen -destination=mock_recipient.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain RecipientPostgres,RecipientUsecase
+//go:generate mockgen -destination=mock_suppression.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain SuppressionPostgres,SuppressionUsecase
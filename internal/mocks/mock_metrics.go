@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adexcell/delayed-notifier/internal/domain (interfaces: Metrics)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock_metrics.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain Metrics
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMetrics is a mock of Metrics interface.
+type MockMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockMetricsMockRecorder is the mock recorder for MockMetrics.
+type MockMetricsMockRecorder struct {
+	mock *MockMetrics
+}
+
+// NewMockMetrics creates a new mock instance.
+func NewMockMetrics(ctrl *gomock.Controller) *MockMetrics {
+	mock := &MockMetrics{ctrl: ctrl}
+	mock.recorder = &MockMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetrics) EXPECT() *MockMetricsMockRecorder {
+	return m.recorder
+}
+
+// NotifyCreated mocks base method.
+func (m *MockMetrics) NotifyCreated(channel string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyCreated", channel)
+}
+
+// NotifyCreated indicates an expected call of NotifyCreated.
+func (mr *MockMetricsMockRecorder) NotifyCreated(channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyCreated", reflect.TypeOf((*MockMetrics)(nil).NotifyCreated), channel)
+}
+
+// NotifyFailed mocks base method.
+func (m *MockMetrics) NotifyFailed(channel string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyFailed", channel)
+}
+
+// NotifyFailed indicates an expected call of NotifyFailed.
+func (mr *MockMetricsMockRecorder) NotifyFailed(channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyFailed", reflect.TypeOf((*MockMetrics)(nil).NotifyFailed), channel)
+}
+
+// NotifySent mocks base method.
+func (m *MockMetrics) NotifySent(channel string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifySent", channel)
+}
+
+// NotifySent indicates an expected call of NotifySent.
+func (mr *MockMetricsMockRecorder) NotifySent(channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifySent", reflect.TypeOf((*MockMetrics)(nil).NotifySent), channel)
+}
+
+// NotifySuppressed mocks base method.
+func (m *MockMetrics) NotifySuppressed(channel string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifySuppressed", channel)
+}
+
+// NotifySuppressed indicates an expected call of NotifySuppressed.
+func (mr *MockMetricsMockRecorder) NotifySuppressed(channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifySuppressed", reflect.TypeOf((*MockMetrics)(nil).NotifySuppressed), channel)
+}
+
+// NotifyThrottled mocks base method.
+func (m *MockMetrics) NotifyThrottled(channel string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyThrottled", channel)
+}
+
+// NotifyThrottled indicates an expected call of NotifyThrottled.
+func (mr *MockMetricsMockRecorder) NotifyThrottled(channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyThrottled", reflect.TypeOf((*MockMetrics)(nil).NotifyThrottled), channel)
+}
+
+// ObserveBatch mocks base method.
+func (m *MockMetrics) ObserveBatch(size int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveBatch", size)
+}
+
+// ObserveBatch indicates an expected call of ObserveBatch.
+func (mr *MockMetricsMockRecorder) ObserveBatch(size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveBatch", reflect.TypeOf((*MockMetrics)(nil).ObserveBatch), size)
+}
+
+// ObservePublish mocks base method.
+func (m *MockMetrics) ObservePublish(channel string, latency time.Duration, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObservePublish", channel, latency, err)
+}
+
+// ObservePublish indicates an expected call of ObservePublish.
+func (mr *MockMetricsMockRecorder) ObservePublish(channel, latency, err any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObservePublish", reflect.TypeOf((*MockMetrics)(nil).ObservePublish), channel, latency, err)
+}
+
+// ObserveSchedulingLag mocks base method.
+func (m *MockMetrics) ObserveSchedulingLag(channel string, lag time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveSchedulingLag", channel, lag)
+}
+
+// ObserveSchedulingLag indicates an expected call of ObserveSchedulingLag.
+func (mr *MockMetricsMockRecorder) ObserveSchedulingLag(channel, lag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveSchedulingLag", reflect.TypeOf((*MockMetrics)(nil).ObserveSchedulingLag), channel, lag)
+}
+
+// ObserveSend mocks base method.
+func (m *MockMetrics) ObserveSend(channel string, latency time.Duration, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveSend", channel, latency, err)
+}
+
+// ObserveSend indicates an expected call of ObserveSend.
+func (mr *MockMetricsMockRecorder) ObserveSend(channel, latency, err any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveSend", reflect.TypeOf((*MockMetrics)(nil).ObserveSend), channel, latency, err)
+}
+
+// SetBacklog mocks base method.
+func (m *MockMetrics) SetBacklog(count int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBacklog", count)
+}
+
+// SetBacklog indicates an expected call of SetBacklog.
+func (mr *MockMetricsMockRecorder) SetBacklog(count any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBacklog", reflect.TypeOf((*MockMetrics)(nil).SetBacklog), count)
+}
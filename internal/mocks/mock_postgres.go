@@ -42,6 +42,21 @@ func (m *MockNotifyPostgres) EXPECT() *MockNotifyPostgresMockRecorder {
 	return m.recorder
 }
 
+// Cancel mocks base method.
+func (m *MockNotifyPostgres) Cancel(ctx context.Context, id string) (*domain.Notify, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(*domain.Notify)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockNotifyPostgresMockRecorder) Cancel(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockNotifyPostgres)(nil).Cancel), ctx, id)
+}
+
 // Close mocks base method.
 func (m *MockNotifyPostgres) Close() error {
 	m.ctrl.T.Helper()
@@ -56,6 +71,21 @@ func (mr *MockNotifyPostgresMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockNotifyPostgres)(nil).Close))
 }
 
+// CountPending mocks base method.
+func (m *MockNotifyPostgres) CountPending(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPending", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPending indicates an expected call of CountPending.
+func (mr *MockNotifyPostgresMockRecorder) CountPending(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPending", reflect.TypeOf((*MockNotifyPostgres)(nil).CountPending), ctx)
+}
+
 // Create mocks base method.
 func (m *MockNotifyPostgres) Create(ctx context.Context, n *domain.Notify) error {
 	m.ctrl.T.Helper()
@@ -70,6 +100,21 @@ func (mr *MockNotifyPostgresMockRecorder) Create(ctx, n any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotifyPostgres)(nil).Create), ctx, n)
 }
 
+// CreateBatch mocks base method.
+func (m *MockNotifyPostgres) CreateBatch(ctx context.Context, notifies []*domain.Notify, atomic bool) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, notifies, atomic)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockNotifyPostgresMockRecorder) CreateBatch(ctx, notifies, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockNotifyPostgres)(nil).CreateBatch), ctx, notifies, atomic)
+}
+
 // DeleteByID mocks base method.
 func (m *MockNotifyPostgres) DeleteByID(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -84,6 +129,36 @@ func (mr *MockNotifyPostgresMockRecorder) DeleteByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockNotifyPostgres)(nil).DeleteByID), ctx, id)
 }
 
+// Fallback mocks base method.
+func (m *MockNotifyPostgres) Fallback(ctx context.Context, id string, lastErr *string) (*domain.Notify, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fallback", ctx, id, lastErr)
+	ret0, _ := ret[0].(*domain.Notify)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fallback indicates an expected call of Fallback.
+func (mr *MockNotifyPostgresMockRecorder) Fallback(ctx, id, lastErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fallback", reflect.TypeOf((*MockNotifyPostgres)(nil).Fallback), ctx, id, lastErr)
+}
+
+// GetByIdempotencyKey mocks base method.
+func (m *MockNotifyPostgres) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Notify, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(*domain.Notify)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIdempotencyKey indicates an expected call of GetByIdempotencyKey.
+func (mr *MockNotifyPostgresMockRecorder) GetByIdempotencyKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIdempotencyKey", reflect.TypeOf((*MockNotifyPostgres)(nil).GetByIdempotencyKey), ctx, key)
+}
+
 // GetNotifyByID mocks base method.
 func (m *MockNotifyPostgres) GetNotifyByID(ctx context.Context, id string) (*domain.Notify, error) {
 	m.ctrl.T.Helper()
@@ -100,18 +175,48 @@ func (mr *MockNotifyPostgresMockRecorder) GetNotifyByID(ctx, id any) *gomock.Cal
 }
 
 // List mocks base method.
-func (m *MockNotifyPostgres) List(ctx context.Context, limit, offset int) ([]*domain.Notify, error) {
+func (m *MockNotifyPostgres) List(ctx context.Context, filter domain.NotifyFilter) ([]*domain.Notify, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret := m.ctrl.Call(m, "List", ctx, filter)
 	ret0, _ := ret[0].([]*domain.Notify)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockNotifyPostgresMockRecorder) List(ctx, limit, offset any) *gomock.Call {
+func (mr *MockNotifyPostgresMockRecorder) List(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNotifyPostgres)(nil).List), ctx, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNotifyPostgres)(nil).List), ctx, filter)
+}
+
+// ListAttempts mocks base method.
+func (m *MockNotifyPostgres) ListAttempts(ctx context.Context, notifyID string) ([]*domain.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttempts", ctx, notifyID)
+	ret0, _ := ret[0].([]*domain.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttempts indicates an expected call of ListAttempts.
+func (mr *MockNotifyPostgresMockRecorder) ListAttempts(ctx, notifyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttempts", reflect.TypeOf((*MockNotifyPostgres)(nil).ListAttempts), ctx, notifyID)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockNotifyPostgres) ListDeadLetters(ctx context.Context, filter domain.NotifyFilter) ([]*domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, filter)
+	ret0, _ := ret[0].([]*domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockNotifyPostgresMockRecorder) ListDeadLetters(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockNotifyPostgres)(nil).ListDeadLetters), ctx, filter)
 }
 
 // LockAndFetchReady mocks base method.
@@ -129,6 +234,93 @@ func (mr *MockNotifyPostgresMockRecorder) LockAndFetchReady(ctx, limit, visibili
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAndFetchReady", reflect.TypeOf((*MockNotifyPostgres)(nil).LockAndFetchReady), ctx, limit, visibilityTimeout)
 }
 
+// Ping mocks base method.
+func (m *MockNotifyPostgres) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockNotifyPostgresMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockNotifyPostgres)(nil).Ping), ctx)
+}
+
+// RecordAttempt mocks base method.
+func (m *MockNotifyPostgres) RecordAttempt(ctx context.Context, a *domain.Attempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAttempt", ctx, a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAttempt indicates an expected call of RecordAttempt.
+func (mr *MockNotifyPostgresMockRecorder) RecordAttempt(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAttempt", reflect.TypeOf((*MockNotifyPostgres)(nil).RecordAttempt), ctx, a)
+}
+
+// Requeue mocks base method.
+func (m *MockNotifyPostgres) Requeue(ctx context.Context, id string) (*domain.Notify, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", ctx, id)
+	ret0, _ := ret[0].(*domain.Notify)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockNotifyPostgresMockRecorder) Requeue(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockNotifyPostgres)(nil).Requeue), ctx, id)
+}
+
+// RequeueFailed mocks base method.
+func (m *MockNotifyPostgres) RequeueFailed(ctx context.Context, filter domain.NotifyFilter) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueFailed", ctx, filter)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueFailed indicates an expected call of RequeueFailed.
+func (mr *MockNotifyPostgresMockRecorder) RequeueFailed(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueFailed", reflect.TypeOf((*MockNotifyPostgres)(nil).RequeueFailed), ctx, filter)
+}
+
+// Reschedule mocks base method.
+func (m *MockNotifyPostgres) Reschedule(ctx context.Context, id string, scheduledAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, id, scheduledAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockNotifyPostgresMockRecorder) Reschedule(ctx, id, scheduledAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockNotifyPostgres)(nil).Reschedule), ctx, id, scheduledAt)
+}
+
+// Update mocks base method.
+func (m *MockNotifyPostgres) Update(ctx context.Context, id string, patch domain.NotifyPatch, version int) (*domain.Notify, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, patch, version)
+	ret0, _ := ret[0].(*domain.Notify)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockNotifyPostgresMockRecorder) Update(ctx, id, patch, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotifyPostgres)(nil).Update), ctx, id, patch, version)
+}
+
 // UpdateStatus mocks base method.
 func (m *MockNotifyPostgres) UpdateStatus(ctx context.Context, id string, status domain.Status, scheduledAt *time.Time, retryCount int, lastErr *string) error {
 	m.ctrl.T.Helper()
@@ -83,6 +83,20 @@ func (mr *MockQueueProviderMockRecorder) Init() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockQueueProvider)(nil).Init))
 }
 
+// Ping mocks base method.
+func (m *MockQueueProvider) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockQueueProviderMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockQueueProvider)(nil).Ping), ctx)
+}
+
 // Publish mocks base method.
 func (m *MockQueueProvider) Publish(ctx context.Context, n *domain.Notify) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,212 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adexcell/delayed-notifier/internal/domain (interfaces: RecipientPostgres,RecipientUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock_recipient.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain RecipientPostgres,RecipientUsecase
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/adexcell/delayed-notifier/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecipientPostgres is a mock of RecipientPostgres interface.
+type MockRecipientPostgres struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecipientPostgresMockRecorder
+	isgomock struct{}
+}
+
+// MockRecipientPostgresMockRecorder is the mock recorder for MockRecipientPostgres.
+type MockRecipientPostgresMockRecorder struct {
+	mock *MockRecipientPostgres
+}
+
+// NewMockRecipientPostgres creates a new mock instance.
+func NewMockRecipientPostgres(ctrl *gomock.Controller) *MockRecipientPostgres {
+	mock := &MockRecipientPostgres{ctrl: ctrl}
+	mock.recorder = &MockRecipientPostgresMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecipientPostgres) EXPECT() *MockRecipientPostgresMockRecorder {
+	return m.recorder
+}
+
+// CreateRecipient mocks base method.
+func (m *MockRecipientPostgres) CreateRecipient(ctx context.Context, r *domain.Recipient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecipient", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecipient indicates an expected call of CreateRecipient.
+func (mr *MockRecipientPostgresMockRecorder) CreateRecipient(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecipient", reflect.TypeOf((*MockRecipientPostgres)(nil).CreateRecipient), ctx, r)
+}
+
+// DeleteRecipient mocks base method.
+func (m *MockRecipientPostgres) DeleteRecipient(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecipient", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecipient indicates an expected call of DeleteRecipient.
+func (mr *MockRecipientPostgresMockRecorder) DeleteRecipient(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecipient", reflect.TypeOf((*MockRecipientPostgres)(nil).DeleteRecipient), ctx, id)
+}
+
+// GetRecipientByID mocks base method.
+func (m *MockRecipientPostgres) GetRecipientByID(ctx context.Context, id string) (*domain.Recipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipientByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Recipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipientByID indicates an expected call of GetRecipientByID.
+func (mr *MockRecipientPostgresMockRecorder) GetRecipientByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipientByID", reflect.TypeOf((*MockRecipientPostgres)(nil).GetRecipientByID), ctx, id)
+}
+
+// ListRecipients mocks base method.
+func (m *MockRecipientPostgres) ListRecipients(ctx context.Context, limit, offset int) ([]*domain.Recipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecipients", ctx, limit, offset)
+	ret0, _ := ret[0].([]*domain.Recipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecipients indicates an expected call of ListRecipients.
+func (mr *MockRecipientPostgresMockRecorder) ListRecipients(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecipients", reflect.TypeOf((*MockRecipientPostgres)(nil).ListRecipients), ctx, limit, offset)
+}
+
+// UpdateRecipient mocks base method.
+func (m *MockRecipientPostgres) UpdateRecipient(ctx context.Context, r *domain.Recipient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecipient", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecipient indicates an expected call of UpdateRecipient.
+func (mr *MockRecipientPostgresMockRecorder) UpdateRecipient(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecipient", reflect.TypeOf((*MockRecipientPostgres)(nil).UpdateRecipient), ctx, r)
+}
+
+// MockRecipientUsecase is a mock of RecipientUsecase interface.
+type MockRecipientUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecipientUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockRecipientUsecaseMockRecorder is the mock recorder for MockRecipientUsecase.
+type MockRecipientUsecaseMockRecorder struct {
+	mock *MockRecipientUsecase
+}
+
+// NewMockRecipientUsecase creates a new mock instance.
+func NewMockRecipientUsecase(ctrl *gomock.Controller) *MockRecipientUsecase {
+	mock := &MockRecipientUsecase{ctrl: ctrl}
+	mock.recorder = &MockRecipientUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecipientUsecase) EXPECT() *MockRecipientUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRecipientUsecase) Create(ctx context.Context, r *domain.Recipient) (*domain.Recipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(*domain.Recipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRecipientUsecaseMockRecorder) Create(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRecipientUsecase)(nil).Create), ctx, r)
+}
+
+// Delete mocks base method.
+func (m *MockRecipientUsecase) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRecipientUsecaseMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRecipientUsecase)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockRecipientUsecase) GetByID(ctx context.Context, id string) (*domain.Recipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Recipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRecipientUsecaseMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRecipientUsecase)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockRecipientUsecase) List(ctx context.Context, limit, offset int) ([]*domain.Recipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret0, _ := ret[0].([]*domain.Recipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRecipientUsecaseMockRecorder) List(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRecipientUsecase)(nil).List), ctx, limit, offset)
+}
+
+// Update mocks base method.
+func (m *MockRecipientUsecase) Update(ctx context.Context, r *domain.Recipient) (*domain.Recipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, r)
+	ret0, _ := ret[0].(*domain.Recipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRecipientUsecaseMockRecorder) Update(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRecipientUsecase)(nil).Update), ctx, r)
+}
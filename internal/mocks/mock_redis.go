@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/adexcell/delayed-notifier/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -55,6 +56,20 @@ func (mr *MockNotifyRedisMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockNotifyRedis)(nil).Close))
 }
 
+// Delete mocks base method.
+func (m *MockNotifyRedis) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNotifyRedisMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNotifyRedis)(nil).Delete), ctx, id)
+}
+
 // Get mocks base method.
 func (m *MockNotifyRedis) Get(ctx context.Context, id string) (*domain.Notify, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +85,20 @@ func (mr *MockNotifyRedisMockRecorder) Get(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNotifyRedis)(nil).Get), ctx, id)
 }
 
+// Ping mocks base method.
+func (m *MockNotifyRedis) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockNotifyRedisMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockNotifyRedis)(nil).Ping), ctx)
+}
+
 // SetWithExpiration mocks base method.
 func (m *MockNotifyRedis) SetWithExpiration(ctx context.Context, n *domain.Notify) error {
 	m.ctrl.T.Helper()
@@ -83,3 +112,17 @@ func (mr *MockNotifyRedisMockRecorder) SetWithExpiration(ctx, n any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithExpiration", reflect.TypeOf((*MockNotifyRedis)(nil).SetWithExpiration), ctx, n)
 }
+
+// UpdateStatus mocks base method.
+func (m *MockNotifyRedis) UpdateStatus(ctx context.Context, id string, status domain.Status, scheduledAt *time.Time, retryCount int, lastErr *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status, scheduledAt, retryCount, lastErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockNotifyRedisMockRecorder) UpdateStatus(ctx, id, status, scheduledAt, retryCount, lastErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockNotifyRedis)(nil).UpdateStatus), ctx, id, status, scheduledAt, retryCount, lastErr)
+}
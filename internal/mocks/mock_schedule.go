@@ -0,0 +1,244 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adexcell/delayed-notifier/internal/domain (interfaces: SchedulePostgres,ScheduleUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock_schedule.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain SchedulePostgres,ScheduleUsecase
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/adexcell/delayed-notifier/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSchedulePostgres is a mock of SchedulePostgres interface.
+type MockSchedulePostgres struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulePostgresMockRecorder
+	isgomock struct{}
+}
+
+// MockSchedulePostgresMockRecorder is the mock recorder for MockSchedulePostgres.
+type MockSchedulePostgresMockRecorder struct {
+	mock *MockSchedulePostgres
+}
+
+// NewMockSchedulePostgres creates a new mock instance.
+func NewMockSchedulePostgres(ctrl *gomock.Controller) *MockSchedulePostgres {
+	mock := &MockSchedulePostgres{ctrl: ctrl}
+	mock.recorder = &MockSchedulePostgresMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchedulePostgres) EXPECT() *MockSchedulePostgresMockRecorder {
+	return m.recorder
+}
+
+// CreateSchedule mocks base method.
+func (m *MockSchedulePostgres) CreateSchedule(ctx context.Context, s *domain.Schedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockSchedulePostgresMockRecorder) CreateSchedule(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockSchedulePostgres)(nil).CreateSchedule), ctx, s)
+}
+
+// DeleteSchedule mocks base method.
+func (m *MockSchedulePostgres) DeleteSchedule(ctx context.Context, id string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSchedule", ctx, id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSchedule indicates an expected call of DeleteSchedule.
+func (mr *MockSchedulePostgresMockRecorder) DeleteSchedule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSchedule", reflect.TypeOf((*MockSchedulePostgres)(nil).DeleteSchedule), ctx, id)
+}
+
+// GetScheduleByID mocks base method.
+func (m *MockSchedulePostgres) GetScheduleByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduleByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduleByID indicates an expected call of GetScheduleByID.
+func (mr *MockSchedulePostgresMockRecorder) GetScheduleByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleByID", reflect.TypeOf((*MockSchedulePostgres)(nil).GetScheduleByID), ctx, id)
+}
+
+// ListSchedules mocks base method.
+func (m *MockSchedulePostgres) ListSchedules(ctx context.Context, limit, offset int) ([]*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchedules", ctx, limit, offset)
+	ret0, _ := ret[0].([]*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchedules indicates an expected call of ListSchedules.
+func (mr *MockSchedulePostgresMockRecorder) ListSchedules(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedules", reflect.TypeOf((*MockSchedulePostgres)(nil).ListSchedules), ctx, limit, offset)
+}
+
+// MaterializeDue mocks base method.
+func (m *MockSchedulePostgres) MaterializeDue(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaterializeDue", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaterializeDue indicates an expected call of MaterializeDue.
+func (mr *MockSchedulePostgresMockRecorder) MaterializeDue(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaterializeDue", reflect.TypeOf((*MockSchedulePostgres)(nil).MaterializeDue), ctx, limit)
+}
+
+// UpdateScheduleState mocks base method.
+func (m *MockSchedulePostgres) UpdateScheduleState(ctx context.Context, id string, status domain.ScheduleStatus, nextRunAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduleState", ctx, id, status, nextRunAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScheduleState indicates an expected call of UpdateScheduleState.
+func (mr *MockSchedulePostgresMockRecorder) UpdateScheduleState(ctx, id, status, nextRunAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduleState", reflect.TypeOf((*MockSchedulePostgres)(nil).UpdateScheduleState), ctx, id, status, nextRunAt)
+}
+
+// MockScheduleUsecase is a mock of ScheduleUsecase interface.
+type MockScheduleUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockScheduleUsecaseMockRecorder is the mock recorder for MockScheduleUsecase.
+type MockScheduleUsecaseMockRecorder struct {
+	mock *MockScheduleUsecase
+}
+
+// NewMockScheduleUsecase creates a new mock instance.
+func NewMockScheduleUsecase(ctrl *gomock.Controller) *MockScheduleUsecase {
+	mock := &MockScheduleUsecase{ctrl: ctrl}
+	mock.recorder = &MockScheduleUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleUsecase) EXPECT() *MockScheduleUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockScheduleUsecase) Create(ctx context.Context, s *domain.Schedule) (*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, s)
+	ret0, _ := ret[0].(*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScheduleUsecaseMockRecorder) Create(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduleUsecase)(nil).Create), ctx, s)
+}
+
+// Delete mocks base method.
+func (m *MockScheduleUsecase) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockScheduleUsecaseMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScheduleUsecase)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockScheduleUsecase) GetByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockScheduleUsecaseMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockScheduleUsecase)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockScheduleUsecase) List(ctx context.Context, limit, offset int) ([]*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret0, _ := ret[0].([]*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockScheduleUsecaseMockRecorder) List(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScheduleUsecase)(nil).List), ctx, limit, offset)
+}
+
+// Pause mocks base method.
+func (m *MockScheduleUsecase) Pause(ctx context.Context, id string) (*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, id)
+	ret0, _ := ret[0].(*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockScheduleUsecaseMockRecorder) Pause(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockScheduleUsecase)(nil).Pause), ctx, id)
+}
+
+// Resume mocks base method.
+func (m *MockScheduleUsecase) Resume(ctx context.Context, id string) (*domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx, id)
+	ret0, _ := ret[0].(*domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockScheduleUsecaseMockRecorder) Resume(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockScheduleUsecase)(nil).Resume), ctx, id)
+}
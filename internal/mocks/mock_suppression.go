@@ -0,0 +1,182 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adexcell/delayed-notifier/internal/domain (interfaces: SuppressionPostgres,SuppressionUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock_suppression.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain SuppressionPostgres,SuppressionUsecase
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/adexcell/delayed-notifier/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSuppressionPostgres is a mock of SuppressionPostgres interface.
+type MockSuppressionPostgres struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuppressionPostgresMockRecorder
+	isgomock struct{}
+}
+
+// MockSuppressionPostgresMockRecorder is the mock recorder for MockSuppressionPostgres.
+type MockSuppressionPostgresMockRecorder struct {
+	mock *MockSuppressionPostgres
+}
+
+// NewMockSuppressionPostgres creates a new mock instance.
+func NewMockSuppressionPostgres(ctrl *gomock.Controller) *MockSuppressionPostgres {
+	mock := &MockSuppressionPostgres{ctrl: ctrl}
+	mock.recorder = &MockSuppressionPostgresMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuppressionPostgres) EXPECT() *MockSuppressionPostgresMockRecorder {
+	return m.recorder
+}
+
+// CreateSuppression mocks base method.
+func (m *MockSuppressionPostgres) CreateSuppression(ctx context.Context, s *domain.Suppression) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSuppression", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSuppression indicates an expected call of CreateSuppression.
+func (mr *MockSuppressionPostgresMockRecorder) CreateSuppression(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSuppression", reflect.TypeOf((*MockSuppressionPostgres)(nil).CreateSuppression), ctx, s)
+}
+
+// DeleteSuppression mocks base method.
+func (m *MockSuppressionPostgres) DeleteSuppression(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSuppression", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSuppression indicates an expected call of DeleteSuppression.
+func (mr *MockSuppressionPostgresMockRecorder) DeleteSuppression(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSuppression", reflect.TypeOf((*MockSuppressionPostgres)(nil).DeleteSuppression), ctx, id)
+}
+
+// FindSuppression mocks base method.
+func (m *MockSuppressionPostgres) FindSuppression(ctx context.Context, n *domain.Notify) (*domain.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSuppression", ctx, n)
+	ret0, _ := ret[0].(*domain.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSuppression indicates an expected call of FindSuppression.
+func (mr *MockSuppressionPostgresMockRecorder) FindSuppression(ctx, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSuppression", reflect.TypeOf((*MockSuppressionPostgres)(nil).FindSuppression), ctx, n)
+}
+
+// ListSuppressions mocks base method.
+func (m *MockSuppressionPostgres) ListSuppressions(ctx context.Context, filter domain.SuppressionFilter) ([]*domain.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSuppressions", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSuppressions indicates an expected call of ListSuppressions.
+func (mr *MockSuppressionPostgresMockRecorder) ListSuppressions(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSuppressions", reflect.TypeOf((*MockSuppressionPostgres)(nil).ListSuppressions), ctx, filter)
+}
+
+// MockSuppressionUsecase is a mock of SuppressionUsecase interface.
+type MockSuppressionUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuppressionUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockSuppressionUsecaseMockRecorder is the mock recorder for MockSuppressionUsecase.
+type MockSuppressionUsecaseMockRecorder struct {
+	mock *MockSuppressionUsecase
+}
+
+// NewMockSuppressionUsecase creates a new mock instance.
+func NewMockSuppressionUsecase(ctrl *gomock.Controller) *MockSuppressionUsecase {
+	mock := &MockSuppressionUsecase{ctrl: ctrl}
+	mock.recorder = &MockSuppressionUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuppressionUsecase) EXPECT() *MockSuppressionUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSuppressionUsecase) Create(ctx context.Context, s *domain.Suppression, allTenants bool) (*domain.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, s, allTenants)
+	ret0, _ := ret[0].(*domain.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSuppressionUsecaseMockRecorder) Create(ctx, s, allTenants any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSuppressionUsecase)(nil).Create), ctx, s, allTenants)
+}
+
+// Delete mocks base method.
+func (m *MockSuppressionUsecase) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSuppressionUsecaseMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSuppressionUsecase)(nil).Delete), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockSuppressionUsecase) List(ctx context.Context, filter domain.SuppressionFilter) ([]*domain.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSuppressionUsecaseMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSuppressionUsecase)(nil).List), ctx, filter)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockSuppressionUsecase) Unsubscribe(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockSuppressionUsecaseMockRecorder) Unsubscribe(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockSuppressionUsecase)(nil).Unsubscribe), ctx, token)
+}
@@ -0,0 +1,228 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/adexcell/delayed-notifier/internal/domain (interfaces: TemplatePostgres,TemplateUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock_template.go -package=mocks github.com/adexcell/delayed-notifier/internal/domain TemplatePostgres,TemplateUsecase
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/adexcell/delayed-notifier/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTemplatePostgres is a mock of TemplatePostgres interface.
+type MockTemplatePostgres struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplatePostgresMockRecorder
+	isgomock struct{}
+}
+
+// MockTemplatePostgresMockRecorder is the mock recorder for MockTemplatePostgres.
+type MockTemplatePostgresMockRecorder struct {
+	mock *MockTemplatePostgres
+}
+
+// NewMockTemplatePostgres creates a new mock instance.
+func NewMockTemplatePostgres(ctrl *gomock.Controller) *MockTemplatePostgres {
+	mock := &MockTemplatePostgres{ctrl: ctrl}
+	mock.recorder = &MockTemplatePostgresMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplatePostgres) EXPECT() *MockTemplatePostgresMockRecorder {
+	return m.recorder
+}
+
+// CreateTemplateVersion mocks base method.
+func (m *MockTemplatePostgres) CreateTemplateVersion(ctx context.Context, t *domain.Template) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplateVersion", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTemplateVersion indicates an expected call of CreateTemplateVersion.
+func (mr *MockTemplatePostgresMockRecorder) CreateTemplateVersion(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplateVersion", reflect.TypeOf((*MockTemplatePostgres)(nil).CreateTemplateVersion), ctx, t)
+}
+
+// DeleteTemplate mocks base method.
+func (m *MockTemplatePostgres) DeleteTemplate(ctx context.Context, name, channel string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplate", ctx, name, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplate indicates an expected call of DeleteTemplate.
+func (mr *MockTemplatePostgresMockRecorder) DeleteTemplate(ctx, name, channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplate", reflect.TypeOf((*MockTemplatePostgres)(nil).DeleteTemplate), ctx, name, channel)
+}
+
+// GetTemplate mocks base method.
+func (m *MockTemplatePostgres) GetTemplate(ctx context.Context, name, channel string, version int) (*domain.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", ctx, name, channel, version)
+	ret0, _ := ret[0].(*domain.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockTemplatePostgresMockRecorder) GetTemplate(ctx, name, channel, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockTemplatePostgres)(nil).GetTemplate), ctx, name, channel, version)
+}
+
+// ListTemplateVersions mocks base method.
+func (m *MockTemplatePostgres) ListTemplateVersions(ctx context.Context, name, channel string) ([]*domain.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplateVersions", ctx, name, channel)
+	ret0, _ := ret[0].([]*domain.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplateVersions indicates an expected call of ListTemplateVersions.
+func (mr *MockTemplatePostgresMockRecorder) ListTemplateVersions(ctx, name, channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplateVersions", reflect.TypeOf((*MockTemplatePostgres)(nil).ListTemplateVersions), ctx, name, channel)
+}
+
+// ListTemplates mocks base method.
+func (m *MockTemplatePostgres) ListTemplates(ctx context.Context) ([]*domain.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", ctx)
+	ret0, _ := ret[0].([]*domain.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockTemplatePostgresMockRecorder) ListTemplates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockTemplatePostgres)(nil).ListTemplates), ctx)
+}
+
+// MockTemplateUsecase is a mock of TemplateUsecase interface.
+type MockTemplateUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockTemplateUsecaseMockRecorder is the mock recorder for MockTemplateUsecase.
+type MockTemplateUsecaseMockRecorder struct {
+	mock *MockTemplateUsecase
+}
+
+// NewMockTemplateUsecase creates a new mock instance.
+func NewMockTemplateUsecase(ctrl *gomock.Controller) *MockTemplateUsecase {
+	mock := &MockTemplateUsecase{ctrl: ctrl}
+	mock.recorder = &MockTemplateUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateUsecase) EXPECT() *MockTemplateUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTemplateUsecase) Create(ctx context.Context, t *domain.Template) (*domain.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(*domain.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTemplateUsecaseMockRecorder) Create(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTemplateUsecase)(nil).Create), ctx, t)
+}
+
+// Delete mocks base method.
+func (m *MockTemplateUsecase) Delete(ctx context.Context, name, channel string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTemplateUsecaseMockRecorder) Delete(ctx, name, channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTemplateUsecase)(nil).Delete), ctx, name, channel)
+}
+
+// Get mocks base method.
+func (m *MockTemplateUsecase) Get(ctx context.Context, name, channel string, version int) (*domain.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name, channel, version)
+	ret0, _ := ret[0].(*domain.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTemplateUsecaseMockRecorder) Get(ctx, name, channel, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTemplateUsecase)(nil).Get), ctx, name, channel, version)
+}
+
+// List mocks base method.
+func (m *MockTemplateUsecase) List(ctx context.Context) ([]*domain.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*domain.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTemplateUsecaseMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTemplateUsecase)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockTemplateUsecase) Update(ctx context.Context, t *domain.Template) (*domain.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, t)
+	ret0, _ := ret[0].(*domain.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTemplateUsecaseMockRecorder) Update(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTemplateUsecase)(nil).Update), ctx, t)
+}
+
+// Versions mocks base method.
+func (m *MockTemplateUsecase) Versions(ctx context.Context, name, channel string) ([]*domain.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Versions", ctx, name, channel)
+	ret0, _ := ret[0].([]*domain.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Versions indicates an expected call of Versions.
+func (mr *MockTemplateUsecaseMockRecorder) Versions(ctx, name, channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Versions", reflect.TypeOf((*MockTemplateUsecase)(nil).Versions), ctx, name, channel)
+}
@@ -41,6 +41,36 @@ func (m *MockNotifyUsecase) EXPECT() *MockNotifyUsecaseMockRecorder {
 	return m.recorder
 }
 
+// Attempts mocks base method.
+func (m *MockNotifyUsecase) Attempts(ctx context.Context, id string) ([]*domain.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attempts", ctx, id)
+	ret0, _ := ret[0].([]*domain.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Attempts indicates an expected call of Attempts.
+func (mr *MockNotifyUsecaseMockRecorder) Attempts(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attempts", reflect.TypeOf((*MockNotifyUsecase)(nil).Attempts), ctx, id)
+}
+
+// Cancel mocks base method.
+func (m *MockNotifyUsecase) Cancel(ctx context.Context, id string) (*domain.Notify, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(*domain.Notify)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockNotifyUsecaseMockRecorder) Cancel(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockNotifyUsecase)(nil).Cancel), ctx, id)
+}
+
 // Delete mocks base method.
 func (m *MockNotifyUsecase) Delete(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -71,18 +101,63 @@ func (mr *MockNotifyUsecaseMockRecorder) GetByID(ctx, id any) *gomock.Call {
 }
 
 // List mocks base method.
-func (m *MockNotifyUsecase) List(ctx context.Context, limit, offset int) ([]*domain.Notify, error) {
+func (m *MockNotifyUsecase) List(ctx context.Context, filter domain.NotifyFilter) (*domain.NotifyPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
-	ret0, _ := ret[0].([]*domain.Notify)
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].(*domain.NotifyPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockNotifyUsecaseMockRecorder) List(ctx, limit, offset any) *gomock.Call {
+func (mr *MockNotifyUsecaseMockRecorder) List(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNotifyUsecase)(nil).List), ctx, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNotifyUsecase)(nil).List), ctx, filter)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockNotifyUsecase) ListDeadLetters(ctx context.Context, filter domain.NotifyFilter) (*domain.DeadLetterPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, filter)
+	ret0, _ := ret[0].(*domain.DeadLetterPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockNotifyUsecaseMockRecorder) ListDeadLetters(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockNotifyUsecase)(nil).ListDeadLetters), ctx, filter)
+}
+
+// Replay mocks base method.
+func (m *MockNotifyUsecase) Replay(ctx context.Context, filter domain.NotifyFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockNotifyUsecaseMockRecorder) Replay(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockNotifyUsecase)(nil).Replay), ctx, filter)
+}
+
+// Retry mocks base method.
+func (m *MockNotifyUsecase) Retry(ctx context.Context, id string) (*domain.Notify, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, id)
+	ret0, _ := ret[0].(*domain.Notify)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockNotifyUsecaseMockRecorder) Retry(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockNotifyUsecase)(nil).Retry), ctx, id)
 }
 
 // Save mocks base method.
@@ -99,3 +174,33 @@ func (mr *MockNotifyUsecaseMockRecorder) Save(ctx, n any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockNotifyUsecase)(nil).Save), ctx, n)
 }
+
+// SaveBatch mocks base method.
+func (m *MockNotifyUsecase) SaveBatch(ctx context.Context, notifies []*domain.Notify, atomic bool) ([]domain.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatch", ctx, notifies, atomic)
+	ret0, _ := ret[0].([]domain.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveBatch indicates an expected call of SaveBatch.
+func (mr *MockNotifyUsecaseMockRecorder) SaveBatch(ctx, notifies, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockNotifyUsecase)(nil).SaveBatch), ctx, notifies, atomic)
+}
+
+// Update mocks base method.
+func (m *MockNotifyUsecase) Update(ctx context.Context, id string, patch domain.NotifyPatch, version int) (*domain.Notify, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, patch, version)
+	ret0, _ := ret[0].(*domain.Notify)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockNotifyUsecaseMockRecorder) Update(ctx, id, patch, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotifyUsecase)(nil).Update), ctx, id, patch, version)
+}
@@ -10,42 +10,254 @@ import (
 )
 
 type NotifyUsecase struct {
-	log      log.Log
-	postgres domain.NotifyPostgres
-	redis    domain.NotifyRedis
-	rabbit   domain.QueueProvider
+	log        log.Log
+	postgres   domain.NotifyPostgres
+	recipients domain.RecipientPostgres
+	redis      domain.NotifyRedis
+	rabbit     domain.QueueProvider
+	metrics    domain.Metrics
+	cache      domain.CacheMode
 }
 
 func New(
 	p domain.NotifyPostgres,
+	recipients domain.RecipientPostgres,
 	redis domain.NotifyRedis,
 	rabbit domain.QueueProvider,
+	metrics domain.Metrics,
+	cache domain.CacheMode,
 	l log.Log,
 ) domain.NotifyUsecase {
 	return &NotifyUsecase{
-		log:      l,
-		postgres: p,
-		redis:    redis,
-		rabbit:   rabbit,
+		log:        l,
+		postgres:   p,
+		recipients: recipients,
+		redis:      redis,
+		rabbit:     rabbit,
+		metrics:    metrics,
+		cache:      cache,
 	}
 }
 
 func (u *NotifyUsecase) Save(ctx context.Context, n *domain.Notify) (string, error) {
+	if n.IdempotencyKey != "" {
+		n.RequestHash = n.Fingerprint()
+
+		id, err := u.checkIdempotencyKey(ctx, n)
+		if !errors.Is(err, domain.ErrNotFound) {
+			return id, err
+		}
+	}
+
+	if err := u.prepare(ctx, n); err != nil {
+		return n.ID, err
+	}
+
 	_, err := u.postgres.GetNotifyByID(ctx, n.ID)
 	if err == nil {
 		return n.ID, domain.ErrNotifyAlreadyExists
 	}
 
 	if err := u.postgres.Create(ctx, n); err != nil {
+		// конкурентный запрос с тем же ключом успел вставить запись раньше нас
+		if errors.Is(err, domain.ErrNotifyAlreadyExists) && n.IdempotencyKey != "" {
+			return u.checkIdempotencyKey(ctx, n)
+		}
 		return n.ID, fmt.Errorf("failed to create save notify in db: %w", err)
 	}
+	u.metrics.NotifyCreated(n.Channel)
 
 	return n.ID, nil
 }
 
+// SaveBatch создает пачку notify одним запросом. Пропущенные базой notify
+// разбираются по ключу идемпотентности: повтор того же запроса не считается ошибкой.
+// В атомарном режиме, если все пропуски оказались повторами, оставшиеся notify
+// вставляются повторно; любой настоящий конфликт отклоняет пачку целиком.
+func (u *NotifyUsecase) SaveBatch(ctx context.Context, notifies []*domain.Notify, atomic bool) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(notifies))
+	firstByKey := make(map[string]int)
+	var pending []int
+	for i, n := range notifies {
+		results[i] = domain.BatchResult{ID: n.ID}
+		if n.IdempotencyKey != "" {
+			n.RequestHash = n.Fingerprint()
+			if _, ok := firstByKey[n.IdempotencyKey]; ok {
+				results[i].Status = domain.BatchConflict
+				results[i].Err = fmt.Errorf("%w: duplicate key in batch", domain.ErrIdempotencyConflict)
+				continue
+			}
+			firstByKey[n.IdempotencyKey] = i
+		}
+
+		if err := u.prepare(ctx, n); err != nil {
+			results[i].Status = domain.BatchFailed
+			if isInvalidNotify(err) {
+				results[i].Status = domain.BatchInvalid
+			}
+			results[i].Err = err
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	if atomic && len(pending) < len(notifies) {
+		rejectBatch(results, pending)
+		return results, domain.ErrBatchConflict
+	}
+
+	for len(pending) > 0 {
+		batch := make([]*domain.Notify, 0, len(pending))
+		for _, i := range pending {
+			batch = append(batch, notifies[i])
+		}
+
+		inserted, err := u.postgres.CreateBatch(ctx, batch, atomic)
+		if err != nil && !errors.Is(err, domain.ErrBatchConflict) {
+			for _, i := range pending {
+				results[i].Status = domain.BatchFailed
+				results[i].Err = err
+			}
+			return results, fmt.Errorf("failed to create batch in db: %w", err)
+		}
+
+		insertedIDs := make(map[string]bool, len(inserted))
+		for _, id := range inserted {
+			insertedIDs[id] = true
+		}
+
+		var rest []int
+		conflict := false
+		for _, i := range pending {
+			n := notifies[i]
+			if insertedIDs[n.ID] {
+				results[i].Status = domain.BatchCreated
+				rest = append(rest, i)
+				continue
+			}
+			results[i] = u.resolveSkipped(ctx, n)
+			conflict = conflict || !results[i].OK()
+		}
+
+		if err == nil {
+			break
+		}
+		// атомарная пачка откачена: вставленные в этом проходе notify не сохранились
+		if conflict {
+			rejectBatch(results, rest)
+			return results, domain.ErrBatchConflict
+		}
+		pending = rest
+	}
+
+	for i, n := range notifies {
+		if results[i].Status == domain.BatchCreated {
+			u.metrics.NotifyCreated(n.Channel)
+		}
+	}
+	return results, nil
+}
+
+// prepare разрешает получателя и проверяет payload для каналов, куда notify может уйти
+func (u *NotifyUsecase) prepare(ctx context.Context, n *domain.Notify) error {
+	if err := u.resolveRecipient(ctx, n); err != nil {
+		return err
+	}
+	return n.ValidatePayload()
+}
+
+// resolveRecipient превращает адресацию получателя в маршрут: первый доступный канал
+// становится Channel/Target, остальные - запасными маршрутами. Окно доставки получателя
+// применяется, если у notify нет своего. Notify с прямым адресом не меняется
+func (u *NotifyUsecase) resolveRecipient(ctx context.Context, n *domain.Notify) error {
+	if n.RecipientID == "" {
+		return nil
+	}
+
+	r, err := u.recipients.GetRecipientByID(ctx, n.RecipientID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecipientNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to get recipient: %w", err)
+	}
+
+	routes, err := r.Routes(n.Channels)
+	if err != nil {
+		return err
+	}
+	n.Channel = routes[0].Channel
+	n.Target = routes[0].Target
+	n.Fallbacks = nil
+	if len(routes) > 1 {
+		n.Fallbacks = routes[1:]
+	}
+	if n.DeliveryWindow == nil {
+		n.DeliveryWindow = r.DeliveryWindow
+	}
+	return nil
+}
+
+// isInvalidNotify - ошибки адресации получателя и payload, которые исправляет только клиент
+func isInvalidNotify(err error) bool {
+	return errors.Is(err, domain.ErrRecipientNotFound) || errors.Is(err, domain.ErrNoRoute) ||
+		errors.Is(err, domain.ErrInvalidEmailPayload)
+}
+
+// resolveSkipped объясняет, почему notify не был вставлен пачкой
+func (u *NotifyUsecase) resolveSkipped(ctx context.Context, n *domain.Notify) domain.BatchResult {
+	if n.IdempotencyKey == "" {
+		return domain.BatchResult{ID: n.ID, Status: domain.BatchConflict, Err: domain.ErrNotifyAlreadyExists}
+	}
+
+	id, err := u.checkIdempotencyKey(ctx, n)
+	switch {
+	case errors.Is(err, domain.ErrIdempotentReplay):
+		return domain.BatchResult{ID: id, Status: domain.BatchReplayed}
+	case errors.Is(err, domain.ErrIdempotencyConflict):
+		return domain.BatchResult{ID: id, Status: domain.BatchConflict, Err: err}
+	case errors.Is(err, domain.ErrNotFound):
+		// ключ занят записью, откаченной вместе с атомарной пачкой, или notify_id уже существует
+		return domain.BatchResult{ID: n.ID, Status: domain.BatchConflict, Err: domain.ErrNotifyAlreadyExists}
+	default:
+		return domain.BatchResult{ID: n.ID, Status: domain.BatchFailed, Err: err}
+	}
+}
+
+// rejectBatch помечает отклоненными элементы, которые сами по себе были бы созданы
+func rejectBatch(results []domain.BatchResult, idx []int) {
+	for _, i := range idx {
+		if results[i].Status == "" || results[i].Status == domain.BatchCreated {
+			results[i].Status = domain.BatchRejected
+			results[i].Err = nil
+		}
+	}
+}
+
+// checkIdempotencyKey ищет ранее созданный notify по ключу идемпотентности.
+// Возвращает ErrNotFound, если ключ еще не использовался, ErrIdempotentReplay с ID
+// исходного notify при повторе того же запроса и ErrIdempotencyConflict, если
+// ключ уже использован с другим телом запроса.
+func (u *NotifyUsecase) checkIdempotencyKey(ctx context.Context, n *domain.Notify) (string, error) {
+	existing, err := u.postgres.GetByIdempotencyKey(ctx, n.IdempotencyKey)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return n.ID, domain.ErrNotFound
+		}
+		return n.ID, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+
+	if existing.RequestHash != n.RequestHash {
+		return existing.ID, domain.ErrIdempotencyConflict
+	}
+
+	return existing.ID, domain.ErrIdempotentReplay
+}
+
 func (u *NotifyUsecase) GetByID(ctx context.Context, id string) (*domain.Notify, error) {
+	// кеш общий для всех арендаторов, поэтому принадлежность проверяем сами
 	n, err := u.redis.Get(ctx, id)
-	if err == nil {
+	if err == nil && domain.Visible(ctx, n.TenantID) {
 		return n, nil
 	}
 
@@ -64,10 +276,169 @@ func (u *NotifyUsecase) GetByID(ctx context.Context, id string) (*domain.Notify,
 	return n, nil
 }
 
-func (u *NotifyUsecase) List(ctx context.Context, limit, offset int) ([]*domain.Notify, error) {
-	return u.postgres.List(ctx, limit, offset)
+func (u *NotifyUsecase) List(ctx context.Context, filter domain.NotifyFilter) (*domain.NotifyPage, error) {
+	limit := filter.Limit
+
+	// запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	filter.Limit = limit + 1
+	notifies, err := u.postgres.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.NotifyPage{Items: notifies}
+	if limit > 0 && len(notifies) > limit {
+		page.Items = notifies[:limit]
+		page.NextCursor = domain.NewCursor(page.Items[limit-1], filter.SortBy, filter.Desc).Encode()
+	}
+
+	return page, nil
+}
+
+// Update редактирует Pending notify. Кеш сбрасывается и после успеха, и при
+// несовпадении версии: закешированная версия могла отстать от БД, и клиент,
+// перечитав notify, иначе снова получил бы устаревший ETag
+func (u *NotifyUsecase) Update(ctx context.Context, id string, patch domain.NotifyPatch, version int) (*domain.Notify, error) {
+	if err := u.validatePatch(ctx, id, patch); err != nil {
+		return nil, err
+	}
+
+	n, err := u.postgres.Update(ctx, id, patch, version)
+	if err != nil && !errors.Is(err, domain.ErrVersionMismatch) {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrNotifyNotEditable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update notify in db: %w", err)
+	}
+
+	if err := u.redis.Delete(ctx, id); err != nil {
+		u.log.Error().Err(err).Str("id", id).Msg("failed to invalidate edited notify in redis")
+	}
+
+	return n, err
+}
+
+// validatePatch проверяет payload после изменения: канал и payload могут меняться по отдельности.
+// Конкурентное изменение между чтением и записью отсечет проверка версии
+func (u *NotifyUsecase) validatePatch(ctx context.Context, id string, patch domain.NotifyPatch) error {
+	if patch.Payload == nil && patch.Channel == nil {
+		return nil
+	}
+
+	n, err := u.postgres.GetNotifyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to get notify from db: %w", err)
+	}
+	if patch.Payload != nil {
+		n.Payload = patch.Payload
+	}
+	if patch.Channel != nil {
+		n.Channel = *patch.Channel
+	}
+	return n.ValidatePayload()
+}
+
+func (u *NotifyUsecase) Cancel(ctx context.Context, id string) (*domain.Notify, error) {
+	n, err := u.postgres.Cancel(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrNotifyNotCancelable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to cancel notify in db: %w", err)
+	}
+
+	// обновляем кеш, чтобы консьюмер не прочитал устаревший статус
+	// для сообщения, которое уже лежит в очереди
+	if err := u.cache.SyncStatus(ctx, u.redis, id, n.Status, nil, n.RetryCount, n.LastError); err != nil {
+		u.log.Error().Err(err).Str("id", id).Msg("failed to sync canceled notify to redis")
+	}
+
+	return n, nil
+}
+
+func (u *NotifyUsecase) ListDeadLetters(ctx context.Context, filter domain.NotifyFilter) (*domain.DeadLetterPage, error) {
+	limit := filter.Limit
+
+	filter.Limit = limit + 1
+	letters, err := u.postgres.ListDeadLetters(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.DeadLetterPage{Items: letters}
+	if limit > 0 && len(letters) > limit {
+		page.Items = letters[:limit]
+		page.NextCursor = domain.NewCursor(page.Items[limit-1].Notify, filter.SortBy, filter.Desc).Encode()
+	}
+
+	return page, nil
+}
+
+// Retry возвращает failed notify в очередь, планировщик отправит его на ближайшем тике
+func (u *NotifyUsecase) Retry(ctx context.Context, id string) (*domain.Notify, error) {
+	n, err := u.postgres.Requeue(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrNotifyNotRetryable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to requeue notify in db: %w", err)
+	}
+
+	if err := u.cache.SyncStatus(ctx, u.redis, id, n.Status, &n.ScheduledAt, n.RetryCount, n.LastError); err != nil {
+		u.log.Error().Err(err).Str("id", id).Msg("failed to sync requeued notify to redis")
+	}
+
+	return n, nil
+}
+
+// Replay возвращает в очередь все failed notify по фильтру и возвращает их количество
+func (u *NotifyUsecase) Replay(ctx context.Context, filter domain.NotifyFilter) (int, error) {
+	ids, err := u.postgres.RequeueFailed(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue failed notifies in db: %w", err)
+	}
+
+	// RequeueFailed возвращает только ID, поэтому кеш всегда инвалидируется
+	for _, id := range ids {
+		if err := u.redis.Delete(ctx, id); err != nil {
+			u.log.Error().Err(err).Str("id", id).Msg("failed to invalidate requeued notify in redis")
+		}
+	}
+
+	u.log.Info().Int("count", len(ids)).Msg("failed notifies requeued")
+	return len(ids), nil
 }
 
 func (u *NotifyUsecase) Delete(ctx context.Context, id string) error {
-	return u.postgres.DeleteByID(ctx, id)
+	if err := u.postgres.DeleteByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.redis.Delete(ctx, id); err != nil {
+		u.log.Error().Err(err).Str("id", id).Msg("failed to invalidate deleted notify in redis")
+	}
+	return nil
+}
+
+// Attempts возвращает историю попыток доставки notify в хронологическом порядке
+func (u *NotifyUsecase) Attempts(ctx context.Context, id string) ([]*domain.Attempt, error) {
+	attempts, err := u.postgres.ListAttempts(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attempts from db: %w", err)
+	}
+
+	// пустая история у существующего notify - не ошибка
+	if len(attempts) == 0 {
+		if _, err := u.postgres.GetNotifyByID(ctx, id); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to get from db: %w", err)
+		}
+	}
+
+	return attempts, nil
 }
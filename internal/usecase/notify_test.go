@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/adexcell/delayed-notifier/internal/adapter/metrics"
 	"github.com/adexcell/delayed-notifier/internal/domain"
 	"github.com/adexcell/delayed-notifier/internal/mocks"
 	"github.com/adexcell/delayed-notifier/pkg/log"
@@ -18,8 +20,9 @@ func TestNotifyUsecase_Save_Success(t *testing.T) {
 	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockMetrics := mocks.NewMockMetrics(ctrl)
 
-	usecase := New(mockPostgres, mockRedis, mockQueue, log.New())
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, mockMetrics, domain.CacheWriteThrough, log.New())
 
 	ctx := context.Background()
 	notify := &domain.Notify{
@@ -41,6 +44,11 @@ func TestNotifyUsecase_Save_Success(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	// Expect: счетчик созданных по каналу
+	mockMetrics.EXPECT().
+		NotifyCreated("email").
+		Times(1)
+
 	// Act
 	id, err := usecase.Save(ctx, notify)
 
@@ -61,7 +69,7 @@ func TestNotifyUsecase_Save_AlreadyExists(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 
-	usecase := New(mockPostgres, mockRedis, mockQueue, log.New())
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
 
 	ctx := context.Background()
 	notify := &domain.Notify{
@@ -98,7 +106,7 @@ func TestNotifyUsecase_GetByID_FromCache(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 
-	usecase := New(mockPostgres, mockRedis, mockQueue, log.New())
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
 
 	ctx := context.Background()
 	expectedNotify := &domain.Notify{
@@ -133,7 +141,7 @@ func TestNotifyUsecase_GetByID_FromDB(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 
-	usecase := New(mockPostgres, mockRedis, mockQueue, log.New())
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
 
 	ctx := context.Background()
 	expectedNotify := &domain.Notify{
@@ -172,6 +180,40 @@ func TestNotifyUsecase_GetByID_FromDB(t *testing.T) {
 	}
 }
 
+func TestNotifyUsecase_GetByID_CacheOtherTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := domain.WithTenant(context.Background(), "team-b")
+	cached := &domain.Notify{ID: "test-id-123", TenantID: "team-a"}
+
+	// Expect: в кеше notify другого арендатора
+	mockRedis.EXPECT().
+		Get(ctx, cached.ID).
+		Return(cached, nil).
+		Times(1)
+
+	// Expect: запрос в БД с арендатором из контекста
+	mockPostgres.EXPECT().
+		GetNotifyByID(ctx, cached.ID).
+		Return(nil, domain.ErrNotFound).
+		Times(1)
+
+	// Act
+	_, err := usecase.GetByID(ctx, cached.ID)
+
+	// Assert
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestNotifyUsecase_GetByID_NotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -180,7 +222,7 @@ func TestNotifyUsecase_GetByID_NotFound(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 
-	usecase := New(mockPostgres, mockRedis, mockQueue, log.New())
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
 
 	ctx := context.Background()
 	notifyID := "non-existent-id"
@@ -217,7 +259,7 @@ func TestNotifyUsecase_Delete_Success(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 
-	usecase := New(mockPostgres, mockRedis, mockQueue, log.New())
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
 
 	ctx := context.Background()
 	notifyID := "test-id-123"
@@ -228,6 +270,12 @@ func TestNotifyUsecase_Delete_Success(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	// Expect: удаление из кеша
+	mockRedis.EXPECT().
+		Delete(ctx, notifyID).
+		Return(nil).
+		Times(1)
+
 	// Act
 	err := usecase.Delete(ctx, notifyID)
 
@@ -245,31 +293,945 @@ func TestNotifyUsecase_List_Success(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 
-	usecase := New(mockPostgres, mockRedis, mockQueue, log.New())
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
 
 	ctx := context.Background()
-	limit := 10
-	offset := 0
+	filter := domain.NotifyFilter{Limit: 10, SortBy: domain.SortByCreatedAt, Desc: true}
 
 	expectedNotifies := []*domain.Notify{
 		{ID: "id-1", Target: "user1@example.com"},
 		{ID: "id-2", Target: "user2@example.com"},
 	}
 
-	// Expect: получение списка из БД
+	// Expect: получение списка из БД (limit+1 для определения следующей страницы)
+	mockPostgres.EXPECT().
+		List(ctx, domain.NotifyFilter{Limit: 11, SortBy: domain.SortByCreatedAt, Desc: true}).
+		Return(expectedNotifies, nil).
+		Times(1)
+
+	// Act
+	page, err := usecase.List(ctx, filter)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(page.Items) != len(expectedNotifies) {
+		t.Errorf("expected %d notifies, got %d", len(expectedNotifies), len(page.Items))
+	}
+	if page.NextCursor != "" {
+		t.Errorf("expected empty next cursor on last page, got %q", page.NextCursor)
+	}
+}
+
+func TestNotifyUsecase_List_NextCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	filter := domain.NotifyFilter{Limit: 2, SortBy: domain.SortByScheduledAt}
+
+	expectedNotifies := []*domain.Notify{
+		{ID: "id-1", ScheduledAt: now},
+		{ID: "id-2", ScheduledAt: now.Add(time.Minute)},
+		{ID: "id-3", ScheduledAt: now.Add(2 * time.Minute)},
+	}
+
+	// Expect: БД вернула больше, чем limit - есть следующая страница
 	mockPostgres.EXPECT().
-		List(ctx, limit, offset).
+		List(ctx, gomock.Any()).
 		Return(expectedNotifies, nil).
 		Times(1)
 
 	// Act
-	notifies, err := usecase.List(ctx, limit, offset)
+	page, err := usecase.List(ctx, filter)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(page.Items) != 2 {
+		t.Fatalf("expected 2 notifies, got %d", len(page.Items))
+	}
+
+	cursor, err := domain.DecodeCursor(page.NextCursor)
+	if err != nil {
+		t.Fatalf("expected valid cursor, got %v", err)
+	}
+	if cursor.ID != "id-2" || !cursor.Value.Equal(expectedNotifies[1].ScheduledAt) {
+		t.Errorf("expected cursor to point at id-2, got %+v", cursor)
+	}
+	if cursor.SortBy != domain.SortByScheduledAt || cursor.Desc {
+		t.Errorf("expected cursor for scheduled_at ASC, got %+v", cursor)
+	}
+}
+
+func TestNotifyUsecase_Cancel_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	canceled := &domain.Notify{ID: "test-id-123", Status: domain.StatusCanceled}
+
+	// Expect: атомарная отмена в БД
+	mockPostgres.EXPECT().
+		Cancel(ctx, canceled.ID).
+		Return(canceled, nil).
+		Times(1)
+
+	// Expect: статус Canceled записывается в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, canceled.ID, domain.StatusCanceled, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Act
+	n, err := usecase.Cancel(ctx, canceled.ID)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if n.Status != domain.StatusCanceled {
+		t.Errorf("expected status %v, got %v", domain.StatusCanceled, n.Status)
+	}
+}
+
+func TestNotifyUsecase_Cancel_InvalidateMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheInvalidate, log.New())
+
+	ctx := context.Background()
+	canceled := &domain.Notify{ID: "test-id-123", Status: domain.StatusCanceled}
+
+	mockPostgres.EXPECT().
+		Cancel(ctx, canceled.ID).
+		Return(canceled, nil).
+		Times(1)
+
+	// Expect: в режиме invalidate запись удаляется из кеша
+	mockRedis.EXPECT().
+		Delete(ctx, canceled.ID).
+		Return(nil).
+		Times(1)
+
+	// Act
+	_, err := usecase.Cancel(ctx, canceled.ID)
 
 	// Assert
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
-	if len(notifies) != len(expectedNotifies) {
-		t.Errorf("expected %d notifies, got %d", len(expectedNotifies), len(notifies))
+}
+
+func TestNotifyUsecase_Cancel_NotCancelable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	notifyID := "test-id-123"
+
+	// Expect: notify уже отправлено, кеш не трогаем
+	mockPostgres.EXPECT().
+		Cancel(ctx, notifyID).
+		Return(nil, domain.ErrNotifyNotCancelable).
+		Times(1)
+
+	// Act
+	_, err := usecase.Cancel(ctx, notifyID)
+
+	// Assert
+	if !errors.Is(err, domain.ErrNotifyNotCancelable) {
+		t.Errorf("expected ErrNotifyNotCancelable, got %v", err)
+	}
+}
+
+func TestNotifyUsecase_Save_IdempotentReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:             "new-id",
+		Target:         "test@example.com",
+		Channel:        "email",
+		Payload:        []byte(`"hello"`),
+		IdempotencyKey: "key-1",
+	}
+
+	existing := &domain.Notify{
+		ID:             "original-id",
+		IdempotencyKey: "key-1",
+		RequestHash:    notify.Fingerprint(),
+	}
+
+	// Expect: ключ уже использован с тем же телом запроса
+	mockPostgres.EXPECT().
+		GetByIdempotencyKey(ctx, "key-1").
+		Return(existing, nil).
+		Times(1)
+
+	// Act
+	id, err := usecase.Save(ctx, notify)
+
+	// Assert - повтор не создает новую запись и возвращает исходный ID
+	if !errors.Is(err, domain.ErrIdempotentReplay) {
+		t.Errorf("expected ErrIdempotentReplay, got %v", err)
+	}
+	if id != existing.ID {
+		t.Errorf("expected id %s, got %s", existing.ID, id)
+	}
+}
+
+func TestNotifyUsecase_Save_IdempotencyConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:             "new-id",
+		Target:         "test@example.com",
+		Channel:        "email",
+		Payload:        []byte(`"hello"`),
+		IdempotencyKey: "key-1",
+	}
+
+	existing := &domain.Notify{
+		ID:             "original-id",
+		IdempotencyKey: "key-1",
+		RequestHash:    "other-hash",
+	}
+
+	// Expect: ключ уже использован с другим телом запроса
+	mockPostgres.EXPECT().
+		GetByIdempotencyKey(ctx, "key-1").
+		Return(existing, nil).
+		Times(1)
+
+	// Act
+	_, err := usecase.Save(ctx, notify)
+
+	// Assert
+	if !errors.Is(err, domain.ErrIdempotencyConflict) {
+		t.Errorf("expected ErrIdempotencyConflict, got %v", err)
+	}
+}
+
+func TestNotifyUsecase_Save_IdempotencyConflict_PerField(t *testing.T) {
+	scheduledAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	base := func() *domain.Notify {
+		return &domain.Notify{
+			ID:             "new-id",
+			Target:         "test@example.com",
+			Channel:        "email",
+			Payload:        []byte(`"hello"`),
+			ScheduledAt:    scheduledAt,
+			IdempotencyKey: "key-1",
+			RetryPolicy:    &domain.RetryPolicy{Kind: domain.RetryFixed, MaxAttempts: 3, Delay: time.Minute},
+			DeliveryWindow: &domain.DeliveryWindow{Timezone: "UTC", Start: 9 * 60, End: 21 * 60},
+			Category:       "marketing",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(n *domain.Notify)
+	}{
+		{"payload", func(n *domain.Notify) { n.Payload = []byte(`"bye"`) }},
+		{"target", func(n *domain.Notify) { n.Target = "other@example.com" }},
+		{"channel", func(n *domain.Notify) { n.Channel = "telegram" }},
+		{"scheduled_at", func(n *domain.Notify) { n.ScheduledAt = scheduledAt.Add(time.Minute) }},
+		{"category", func(n *domain.Notify) { n.Category = "billing" }},
+		{"retry_policy", func(n *domain.Notify) { n.RetryPolicy.MaxAttempts = 5 }},
+		{"retry_policy_omitted", func(n *domain.Notify) { n.RetryPolicy = nil }},
+		{"delivery_window", func(n *domain.Notify) { n.DeliveryWindow.End = 20 * 60 }},
+		{"delivery_window_omitted", func(n *domain.Notify) { n.DeliveryWindow = nil }},
+		{"recipient_id", func(n *domain.Notify) { n.RecipientID = "user-1" }},
+		{"channels", func(n *domain.Notify) {
+			n.RecipientID = "user-1"
+			n.Channels = []string{"telegram"}
+		}},
+		{"fallbacks", func(n *domain.Notify) {
+			n.Fallbacks = []domain.Route{{Channel: "telegram", Target: "123"}}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+			usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl),
+				metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+			ctx := context.Background()
+			existing := &domain.Notify{
+				ID:             "original-id",
+				IdempotencyKey: "key-1",
+				RequestHash:    base().Fingerprint(),
+			}
+			notify := base()
+			tt.mutate(notify)
+
+			// Expect: ключ уже использован с запросом, отличающимся одним полем
+			mockPostgres.EXPECT().
+				GetByIdempotencyKey(ctx, "key-1").
+				Return(existing, nil).
+				Times(1)
+
+			// Act
+			_, err := usecase.Save(ctx, notify)
+
+			// Assert
+			if !errors.Is(err, domain.ErrIdempotencyConflict) {
+				t.Errorf("expected ErrIdempotencyConflict, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNotifyUsecase_Save_IdempotencyRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:             "new-id",
+		Target:         "test@example.com",
+		Channel:        "email",
+		Payload:        []byte(`"hello"`),
+		IdempotencyKey: "key-1",
+	}
+
+	existing := &domain.Notify{
+		ID:             "original-id",
+		IdempotencyKey: "key-1",
+		RequestHash:    notify.Fingerprint(),
+	}
+
+	gomock.InOrder(
+		// Expect: ключ еще не найден
+		mockPostgres.EXPECT().
+			GetByIdempotencyKey(ctx, "key-1").
+			Return(nil, domain.ErrNotFound),
+		mockPostgres.EXPECT().
+			GetNotifyByID(ctx, notify.ID).
+			Return(nil, domain.ErrNotFound),
+		// Expect: конкурентный запрос успел вставить запись - уникальный индекс
+		mockPostgres.EXPECT().
+			Create(ctx, notify).
+			Return(domain.ErrNotifyAlreadyExists),
+		mockPostgres.EXPECT().
+			GetByIdempotencyKey(ctx, "key-1").
+			Return(existing, nil),
+	)
+
+	// Act
+	id, err := usecase.Save(ctx, notify)
+
+	// Assert
+	if !errors.Is(err, domain.ErrIdempotentReplay) {
+		t.Errorf("expected ErrIdempotentReplay, got %v", err)
+	}
+	if id != existing.ID {
+		t.Errorf("expected id %s, got %s", existing.ID, id)
+	}
+}
+
+func TestNotifyUsecase_Retry_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	requeued := &domain.Notify{ID: "test-id-123", Status: domain.StatusPending, RetryCount: 0}
+
+	// Expect: возврат в очередь в БД
+	mockPostgres.EXPECT().
+		Requeue(ctx, requeued.ID).
+		Return(requeued, nil).
+		Times(1)
+
+	// Expect: кеш со статусом Failed обновляется до Pending
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, requeued.ID, domain.StatusPending, &requeued.ScheduledAt, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Act
+	n, err := usecase.Retry(ctx, requeued.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n.Status != domain.StatusPending {
+		t.Errorf("expected status %v, got %v", domain.StatusPending, n.Status)
+	}
+}
+
+func TestNotifyUsecase_Retry_NotRetryable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+
+	// Expect: notify не в Failed, кеш не трогаем
+	mockPostgres.EXPECT().
+		Requeue(ctx, "test-id-123").
+		Return(nil, domain.ErrNotifyNotRetryable).
+		Times(1)
+
+	// Act
+	_, err := usecase.Retry(ctx, "test-id-123")
+
+	// Assert
+	if !errors.Is(err, domain.ErrNotifyNotRetryable) {
+		t.Errorf("expected ErrNotifyNotRetryable, got %v", err)
+	}
+}
+
+func TestNotifyUsecase_Replay_InvalidatesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	filter := domain.NotifyFilter{Channel: "email"}
+
+	mockPostgres.EXPECT().
+		RequeueFailed(ctx, filter).
+		Return([]string{"id-1", "id-2"}, nil).
+		Times(1)
+
+	// Expect: ошибка Redis не прерывает повтор
+	mockRedis.EXPECT().Delete(ctx, "id-1").Return(errors.New("redis down")).Times(1)
+	mockRedis.EXPECT().Delete(ctx, "id-2").Return(nil).Times(1)
+
+	// Act
+	count, err := usecase.Replay(ctx, filter)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 replayed, got %d", count)
+	}
+}
+
+func TestNotifyUsecase_Attempts_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	attempts := []*domain.Attempt{
+		{ID: "a1", NotifyID: "test-id-123", Kind: domain.AttemptPublish, Outcome: domain.OutcomeSuccess},
+		{ID: "a2", NotifyID: "test-id-123", Kind: domain.AttemptSend, Outcome: domain.OutcomeRetry},
+	}
+
+	// Expect: история есть, существование notify не проверяем
+	mockPostgres.EXPECT().
+		ListAttempts(ctx, "test-id-123").
+		Return(attempts, nil).
+		Times(1)
+
+	// Act
+	result, err := usecase.Attempts(ctx, "test-id-123")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected 2 attempts, got %d", len(result))
+	}
+}
+
+func TestNotifyUsecase_Attempts_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+
+	usecase := New(mockPostgres, nil, mockRedis, mockQueue, metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+
+	// Expect: пустая история и notify не существует
+	mockPostgres.EXPECT().
+		ListAttempts(ctx, "missing").
+		Return(nil, nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		GetNotifyByID(ctx, "missing").
+		Return(nil, domain.ErrNotFound).
+		Times(1)
+
+	// Act
+	_, err := usecase.Attempts(ctx, "missing")
+
+	// Assert
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func newBatchNotify(key string) *domain.Notify {
+	n := domain.NewNotify()
+	n.Payload = []byte(`"hello"`)
+	n.Target = "test@example.com"
+	n.Channel = "email"
+	n.Status = domain.StatusPending
+	n.ScheduledAt = time.Now().Add(time.Hour)
+	n.IdempotencyKey = key
+	return n
+}
+
+func TestNotifyUsecase_SaveBatch_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockMetrics := mocks.NewMockMetrics(ctrl)
+	usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), mockMetrics, domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	first, second := newBatchNotify(""), newBatchNotify("key-2")
+
+	// Expect: одна вставка всей пачки
+	mockPostgres.EXPECT().
+		CreateBatch(ctx, []*domain.Notify{first, second}, false).
+		Return([]string{first.ID, second.ID}, nil).
+		Times(1)
+
+	// Expect: счетчик по каждому созданному
+	mockMetrics.EXPECT().
+		NotifyCreated("email").
+		Times(2)
+
+	// Act
+	results, err := usecase.SaveBatch(ctx, []*domain.Notify{first, second}, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for i, n := range []*domain.Notify{first, second} {
+		if results[i].Status != domain.BatchCreated || results[i].ID != n.ID {
+			t.Errorf("item %d: expected created %s, got %+v", i, n.ID, results[i])
+		}
+	}
+	if second.RequestHash == "" {
+		t.Errorf("expected request hash to be set for keyed notify")
+	}
+}
+
+func TestNotifyUsecase_SaveBatch_PartialReplayAndConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	fresh, replay, conflict := newBatchNotify(""), newBatchNotify("key-replay"), newBatchNotify("key-conflict")
+	batch := []*domain.Notify{fresh, replay, conflict}
+
+	// Expect: вставлен только новый notify
+	mockPostgres.EXPECT().
+		CreateBatch(ctx, batch, false).
+		Return([]string{fresh.ID}, nil).
+		Times(1)
+
+	// Expect: пропущенные разбираются по ключам идемпотентности
+	mockPostgres.EXPECT().
+		GetByIdempotencyKey(ctx, "key-replay").
+		DoAndReturn(func(context.Context, string) (*domain.Notify, error) {
+			return &domain.Notify{ID: "original-id", RequestHash: replay.RequestHash}, nil
+		}).
+		Times(1)
+	mockPostgres.EXPECT().
+		GetByIdempotencyKey(ctx, "key-conflict").
+		Return(&domain.Notify{ID: "other-id", RequestHash: "other-hash"}, nil).
+		Times(1)
+
+	// Act
+	results, err := usecase.SaveBatch(ctx, batch, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results[0].Status != domain.BatchCreated {
+		t.Errorf("expected first created, got %+v", results[0])
+	}
+	if results[1].Status != domain.BatchReplayed || results[1].ID != "original-id" {
+		t.Errorf("expected replay of original-id, got %+v", results[1])
+	}
+	if results[2].Status != domain.BatchConflict || !errors.Is(results[2].Err, domain.ErrIdempotencyConflict) {
+		t.Errorf("expected idempotency conflict, got %+v", results[2])
+	}
+}
+
+func TestNotifyUsecase_SaveBatch_AtomicRetriesWithoutReplays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	fresh, replay := newBatchNotify(""), newBatchNotify("key-replay")
+
+	// Expect: первая попытка откатывается из-за повтора, вторая вставляет только новый
+	gomock.InOrder(
+		mockPostgres.EXPECT().
+			CreateBatch(ctx, []*domain.Notify{fresh, replay}, true).
+			Return([]string{fresh.ID}, domain.ErrBatchConflict),
+		mockPostgres.EXPECT().
+			GetByIdempotencyKey(ctx, "key-replay").
+			DoAndReturn(func(context.Context, string) (*domain.Notify, error) {
+				return &domain.Notify{ID: "original-id", RequestHash: replay.RequestHash}, nil
+			}),
+		mockPostgres.EXPECT().
+			CreateBatch(ctx, []*domain.Notify{fresh}, true).
+			Return([]string{fresh.ID}, nil),
+	)
+
+	// Act
+	results, err := usecase.SaveBatch(ctx, []*domain.Notify{fresh, replay}, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results[0].Status != domain.BatchCreated || results[1].Status != domain.BatchReplayed {
+		t.Errorf("expected created and replayed, got %+v", results)
+	}
+}
+
+func TestNotifyUsecase_SaveBatch_AtomicConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	fresh, existing := newBatchNotify(""), newBatchNotify("")
+
+	// Expect: пачка откатывается, notify без ключа пропущен - конфликт по ID
+	mockPostgres.EXPECT().
+		CreateBatch(ctx, []*domain.Notify{fresh, existing}, true).
+		Return([]string{fresh.ID}, domain.ErrBatchConflict).
+		Times(1)
+
+	// Act
+	results, err := usecase.SaveBatch(ctx, []*domain.Notify{fresh, existing}, true)
+
+	// Assert
+	if !errors.Is(err, domain.ErrBatchConflict) {
+		t.Fatalf("expected ErrBatchConflict, got %v", err)
+	}
+	if results[0].Status != domain.BatchRejected {
+		t.Errorf("expected rolled back notify to be rejected, got %+v", results[0])
+	}
+	if results[1].Status != domain.BatchConflict || !errors.Is(results[1].Err, domain.ErrNotifyAlreadyExists) {
+		t.Errorf("expected conflict, got %+v", results[1])
+	}
+}
+
+func TestNotifyUsecase_SaveBatch_DuplicateKeyInAtomicBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	first, second := newBatchNotify("same-key"), newBatchNotify("same-key")
+
+	// Expect: до базы пачка не доходит
+	mockPostgres.EXPECT().CreateBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Act
+	results, err := usecase.SaveBatch(ctx, []*domain.Notify{first, second}, true)
+
+	// Assert
+	if !errors.Is(err, domain.ErrBatchConflict) {
+		t.Fatalf("expected ErrBatchConflict, got %v", err)
+	}
+	if results[0].Status != domain.BatchRejected || results[1].Status != domain.BatchConflict {
+		t.Errorf("expected rejected and conflict, got %+v", results)
+	}
+}
+
+func TestNotifyUsecase_Update_InvalidatesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	usecase := New(mockPostgres, nil, mockRedis, mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	target := "new@example.com"
+	patch := domain.NotifyPatch{Target: &target}
+
+	// Expect: условное обновление в БД и сброс кеша
+	mockPostgres.EXPECT().
+		Update(ctx, "id-1", patch, 3).
+		Return(&domain.Notify{ID: "id-1", Target: target, Version: 4}, nil).
+		Times(1)
+	mockRedis.EXPECT().
+		Delete(ctx, "id-1").
+		Return(nil).
+		Times(1)
+
+	// Act
+	n, err := usecase.Update(ctx, "id-1", patch, 3)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n.Version != 4 || n.Target != target {
+		t.Errorf("unexpected notify %+v", n)
+	}
+}
+
+func TestNotifyUsecase_Update_VersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	usecase := New(mockPostgres, nil, mockRedis, mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+
+	// Expect: версия в кеше могла отстать - кеш сбрасывается и при конфликте
+	mockPostgres.EXPECT().
+		GetNotifyByID(ctx, "id-1").
+		Return(&domain.Notify{ID: "id-1", Channel: "webhook"}, nil).
+		Times(1)
+	mockPostgres.EXPECT().
+		Update(ctx, "id-1", gomock.Any(), 1).
+		Return(nil, domain.ErrVersionMismatch).
+		Times(1)
+	mockRedis.EXPECT().
+		Delete(ctx, "id-1").
+		Return(nil).
+		Times(1)
+
+	// Act
+	_, err := usecase.Update(ctx, "id-1", domain.NotifyPatch{Payload: []byte(`{}`)}, 1)
+
+	// Assert
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch, got %v", err)
+	}
+}
+
+func TestNotifyUsecase_Update_NotEditable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	usecase := New(mockPostgres, nil, mockRedis, mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+
+	// Expect: notify уже забран в отправку, кеш не трогаем
+	mockPostgres.EXPECT().
+		GetNotifyByID(ctx, "id-1").
+		Return(&domain.Notify{ID: "id-1", Channel: "webhook"}, nil).
+		Times(1)
+	mockPostgres.EXPECT().
+		Update(ctx, "id-1", gomock.Any(), 0).
+		Return(nil, domain.ErrNotifyNotEditable).
+		Times(1)
+
+	// Act
+	_, err := usecase.Update(ctx, "id-1", domain.NotifyPatch{Payload: []byte(`{}`)}, 0)
+
+	// Assert
+	if !errors.Is(err, domain.ErrNotifyNotEditable) {
+		t.Errorf("expected ErrNotifyNotEditable, got %v", err)
+	}
+}
+
+func TestNotifyUsecase_Save_InvalidEmailPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		channel   string
+		fallbacks []domain.Route
+		payload   string
+	}{
+		{name: "empty body", channel: "email", payload: `{"subject": "hi", "text": ""}`},
+		{name: "unknown field", channel: "email", payload: `{"text": "hi", "attachment": []}`},
+		{name: "misspelled reply_to", channel: "email", payload: `{"text": "hi", "reply-to": "support@example.com"}`},
+		{name: "wrong-typed cc", channel: "email", payload: `{"text": "hi", "cc": "boss@example.com"}`},
+		{name: "invalid cc", channel: "email", payload: `{"text": "hi", "cc": ["not-an-address"]}`},
+		{name: "reserved header", channel: "email", payload: `{"text": "hi", "headers": {"bcc": "spy@example.com"}}`},
+		{name: "header injection", channel: "email", payload: `{"text": "hi", "headers": {"X-Campaign": "a\r\nBcc: spy@example.com"}}`},
+		{name: "content and url", channel: "email", payload: `{"text": "hi", "attachments": [{"filename": "a.txt", "content": "aGk=", "url": "https://example.com/a.txt"}]}`},
+		{name: "metadata url", channel: "email", payload: `{"text": "hi", "attachments": [{"filename": "a.txt", "url": "http://169.254.169.254/latest/meta-data"}]}`},
+		{name: "loopback url", channel: "email", payload: `{"text": "hi", "attachments": [{"filename": "a.txt", "url": "http://localhost:8080/admin"}]}`},
+		{name: "private url", channel: "email", payload: `{"text": "hi", "attachments": [{"filename": "a.txt", "url": "https://10.0.0.5/a.txt"}]}`},
+		{name: "invalid base64", channel: "email", payload: `{"text": "hi", "attachments": [{"filename": "a.txt", "content": "not base64"}]}`},
+		{name: "email fallback", channel: "webhook", fallbacks: []domain.Route{{Channel: "email", Target: "user@example.com"}}, payload: `{"text": "hi", "cc": ["not-an-address"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Expect: невалидный payload не доходит до БД
+			mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+			usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+			n := domain.NewNotify()
+			n.Channel = tt.channel
+			n.Target = "user@example.com"
+			n.Fallbacks = tt.fallbacks
+			n.Payload = []byte(tt.payload)
+
+			// Act
+			_, err := usecase.Save(context.Background(), n)
+
+			// Assert
+			if !errors.Is(err, domain.ErrInvalidEmailPayload) {
+				t.Errorf("expected ErrInvalidEmailPayload, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNotifyUsecase_Save_LegacyEmailPayload(t *testing.T) {
+	for _, payload := range []string{`{"message": "hi"}`, `{"subject": "hi"}`, `{"subject": "hi", "cc": ["boss@example.com"]}`} {
+		t.Run(payload, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+			mockMetrics := mocks.NewMockMetrics(ctrl)
+			usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), mockMetrics, domain.CacheWriteThrough, log.New())
+
+			ctx := context.Background()
+			n := domain.NewNotify()
+			n.Channel = "email"
+			n.Target = "user@example.com"
+			n.Payload = []byte(payload)
+
+			// Expect: объект вне схемы письма принимается, как до структурированного формата
+			mockPostgres.EXPECT().GetNotifyByID(ctx, n.ID).Return(nil, domain.ErrNotFound).Times(1)
+			mockPostgres.EXPECT().Create(ctx, n).Return(nil).Times(1)
+			mockMetrics.EXPECT().NotifyCreated("email").Times(1)
+
+			// Act
+			_, err := usecase.Save(ctx, n)
+
+			// Assert
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNotifyUsecase_Update_InvalidEmailPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	usecase := New(mockPostgres, nil, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	channel := "email"
+
+	// Expect: payload вебхука проверяется как письмо после смены канала, Update не вызывается
+	mockPostgres.EXPECT().
+		GetNotifyByID(ctx, "id-1").
+		Return(&domain.Notify{ID: "id-1", Channel: "webhook", Payload: []byte(`{"html": "<b>hi</b>", "reply_to": "bad"}`)}, nil).
+		Times(1)
+
+	// Act
+	_, err := usecase.Update(ctx, "id-1", domain.NotifyPatch{Channel: &channel}, 1)
+
+	// Assert
+	if !errors.Is(err, domain.ErrInvalidEmailPayload) {
+		t.Errorf("expected ErrInvalidEmailPayload, got %v", err)
 	}
 }
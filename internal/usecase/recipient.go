@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+type RecipientUsecase struct {
+	log      log.Log
+	postgres domain.RecipientPostgres
+}
+
+func NewRecipientUsecase(p domain.RecipientPostgres, l log.Log) domain.RecipientUsecase {
+	return &RecipientUsecase{
+		log:      l,
+		postgres: p,
+	}
+}
+
+func (u *RecipientUsecase) Create(ctx context.Context, r *domain.Recipient) (*domain.Recipient, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.postgres.CreateRecipient(ctx, r); err != nil {
+		return nil, fmt.Errorf("failed to save recipient in db: %w", err)
+	}
+	return r, nil
+}
+
+func (u *RecipientUsecase) GetByID(ctx context.Context, id string) (*domain.Recipient, error) {
+	return u.postgres.GetRecipientByID(ctx, id)
+}
+
+func (u *RecipientUsecase) List(ctx context.Context, limit, offset int) ([]*domain.Recipient, error) {
+	return u.postgres.ListRecipients(ctx, limit, offset)
+}
+
+// Update заменяет контакты и предпочтения получателя. Уже созданные notify
+// сохраняют маршруты, вычисленные при их создании
+func (u *RecipientUsecase) Update(ctx context.Context, r *domain.Recipient) (*domain.Recipient, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.postgres.UpdateRecipient(ctx, r); err != nil {
+		if errors.Is(err, domain.ErrRecipientNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update recipient in db: %w", err)
+	}
+	return r, nil
+}
+
+func (u *RecipientUsecase) Delete(ctx context.Context, id string) error {
+	return u.postgres.DeleteRecipient(ctx, id)
+}
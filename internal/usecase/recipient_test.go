@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/adapter/metrics"
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecipientUsecase_Create_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Expect: невалидный получатель не доходит до БД
+	mockPostgres := mocks.NewMockRecipientPostgres(ctrl)
+	usecase := NewRecipientUsecase(mockPostgres, log.New())
+
+	r := domain.NewRecipient()
+	r.Contacts = map[string]string{"email": "user@example.com"}
+	r.Channels = []string{"telegram"}
+
+	// Act
+	_, err := usecase.Create(context.Background(), r)
+
+	// Assert
+	if !errors.Is(err, domain.ErrInvalidRecipient) {
+		t.Errorf("expected ErrInvalidRecipient, got %v", err)
+	}
+}
+
+func TestRecipient_Routes(t *testing.T) {
+	r := &domain.Recipient{
+		ID:       "recipient-1",
+		Contacts: map[string]string{"telegram": "42", "email": "user@example.com", "webhook": "https://example.com/hook"},
+		Channels: []string{"telegram", "email"},
+		OptOuts:  []string{"webhook"},
+	}
+
+	tests := []struct {
+		name      string
+		recipient *domain.Recipient
+		channels  []string
+		want      []string
+		wantErr   error
+	}{
+		{name: "recipient preferences", recipient: r, want: []string{"telegram", "email"}},
+		{name: "requested order", recipient: r, channels: []string{"email", "telegram"}, want: []string{"email", "telegram"}},
+		{name: "skips channels without contact", recipient: r, channels: []string{"sms", "email"}, want: []string{"email"}},
+		{name: "skips opt-outs", recipient: r, channels: []string{"webhook", "telegram"}, want: []string{"telegram"}},
+		{name: "skips duplicates", recipient: r, channels: []string{"email", "email"}, want: []string{"email"}},
+		{
+			name:      "contacts in alphabetical order without preferences",
+			recipient: &domain.Recipient{Contacts: map[string]string{"webhook": "https://example.com/hook", "email": "user@example.com"}},
+			want:      []string{"email", "webhook"},
+		},
+		{name: "no route", recipient: r, channels: []string{"webhook"}, wantErr: domain.ErrNoRoute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			routes, err := tt.recipient.Routes(tt.channels)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			var got []string
+			for _, route := range routes {
+				got = append(got, route.Channel)
+				if route.Target != tt.recipient.Contacts[route.Channel] {
+					t.Errorf("unexpected target %q for %s", route.Target, route.Channel)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected routes %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNotifyUsecase_Save_Recipient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRecipients := mocks.NewMockRecipientPostgres(ctrl)
+	usecase := New(mockPostgres, mockRecipients, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	recipient := &domain.Recipient{
+		ID:             "recipient-1",
+		Contacts:       map[string]string{"telegram": "42", "email": "user@example.com"},
+		Channels:       []string{"email"},
+		DeliveryWindow: &domain.DeliveryWindow{Timezone: "UTC", Start: 9 * 60, End: 18 * 60},
+	}
+	n := domain.NewNotify()
+	n.RecipientID = recipient.ID
+	n.Channels = []string{"telegram", "email"}
+
+	mockRecipients.EXPECT().
+		GetRecipientByID(ctx, recipient.ID).
+		Return(recipient, nil).
+		Times(1)
+	mockPostgres.EXPECT().
+		GetNotifyByID(ctx, n.ID).
+		Return(nil, domain.ErrNotFound).
+		Times(1)
+
+	// Expect: первый канал запроса стал маршрутом, второй - запасным, окно взято у получателя
+	mockPostgres.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, got *domain.Notify) error {
+			if got.Channel != "telegram" || got.Target != "42" {
+				t.Errorf("expected telegram route, got %s/%s", got.Channel, got.Target)
+			}
+			if len(got.Fallbacks) != 1 || got.Fallbacks[0] != (domain.Route{Channel: "email", Target: "user@example.com"}) {
+				t.Errorf("expected email fallback, got %+v", got.Fallbacks)
+			}
+			if got.DeliveryWindow != recipient.DeliveryWindow {
+				t.Errorf("expected recipient delivery window, got %+v", got.DeliveryWindow)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// Act
+	_, err := usecase.Save(ctx, n)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestNotifyUsecase_Save_RecipientNoRoute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRecipients := mocks.NewMockRecipientPostgres(ctrl)
+	usecase := New(mockPostgres, mockRecipients, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	n := domain.NewNotify()
+	n.RecipientID = "recipient-1"
+	n.Channels = []string{"telegram"}
+
+	// Expect: получатель отказался от единственного запрошенного канала, notify не создается
+	mockRecipients.EXPECT().
+		GetRecipientByID(ctx, n.RecipientID).
+		Return(&domain.Recipient{
+			ID:       n.RecipientID,
+			Contacts: map[string]string{"telegram": "42"},
+			OptOuts:  []string{"telegram"},
+		}, nil).
+		Times(1)
+
+	// Act
+	_, err := usecase.Save(ctx, n)
+
+	// Assert
+	if !errors.Is(err, domain.ErrNoRoute) {
+		t.Errorf("expected ErrNoRoute, got %v", err)
+	}
+}
+
+func TestNotifyUsecase_SaveBatch_RecipientNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRecipients := mocks.NewMockRecipientPostgres(ctrl)
+	usecase := New(mockPostgres, mockRecipients, mocks.NewMockNotifyRedis(ctrl), mocks.NewMockQueueProvider(ctrl), metrics.NewNop(), domain.CacheWriteThrough, log.New())
+
+	ctx := context.Background()
+	direct, missing := newBatchNotify(""), newBatchNotify("")
+	missing.RecipientID = "missing"
+
+	mockRecipients.EXPECT().
+		GetRecipientByID(ctx, "missing").
+		Return(nil, domain.ErrRecipientNotFound).
+		Times(1)
+
+	// Expect: в БД уходит только notify с прямым адресом
+	mockPostgres.EXPECT().
+		CreateBatch(ctx, []*domain.Notify{direct}, false).
+		Return([]string{direct.ID}, nil).
+		Times(1)
+
+	// Act
+	results, err := usecase.SaveBatch(ctx, []*domain.Notify{direct, missing}, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results[0].Status != domain.BatchCreated {
+		t.Errorf("expected first created, got %+v", results[0])
+	}
+	if results[1].Status != domain.BatchInvalid || !errors.Is(results[1].Err, domain.ErrRecipientNotFound) {
+		t.Errorf("expected invalid item with ErrRecipientNotFound, got %+v", results[1])
+	}
+}
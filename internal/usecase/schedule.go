@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+type ScheduleUsecase struct {
+	log      log.Log
+	postgres domain.SchedulePostgres
+	redis    domain.NotifyRedis
+}
+
+func NewScheduleUsecase(p domain.SchedulePostgres, redis domain.NotifyRedis, l log.Log) domain.ScheduleUsecase {
+	return &ScheduleUsecase{
+		log:      l,
+		postgres: p,
+		redis:    redis,
+	}
+}
+
+func (u *ScheduleUsecase) Create(ctx context.Context, s *domain.Schedule) (*domain.Schedule, error) {
+	now := time.Now().UTC()
+	if s.StartAt.IsZero() {
+		s.StartAt = now
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	next, ok, err := s.Next(now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSchedule, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: no occurrences before end_at", domain.ErrInvalidSchedule)
+	}
+	s.NextRunAt = &next
+
+	if err := u.postgres.CreateSchedule(ctx, s); err != nil {
+		return nil, fmt.Errorf("failed to save schedule in db: %w", err)
+	}
+
+	return s, nil
+}
+
+func (u *ScheduleUsecase) GetByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	return u.postgres.GetScheduleByID(ctx, id)
+}
+
+func (u *ScheduleUsecase) List(ctx context.Context, limit, offset int) ([]*domain.Schedule, error) {
+	return u.postgres.ListSchedules(ctx, limit, offset)
+}
+
+func (u *ScheduleUsecase) Pause(ctx context.Context, id string) (*domain.Schedule, error) {
+	s, err := u.postgres.GetScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Status == domain.ScheduleFinished {
+		return nil, fmt.Errorf("%w: schedule already finished", domain.ErrInvalidSchedule)
+	}
+
+	if err := u.postgres.UpdateScheduleState(ctx, id, domain.SchedulePaused, s.NextRunAt); err != nil {
+		return nil, err
+	}
+	s.Status = domain.SchedulePaused
+
+	return s, nil
+}
+
+// Resume возобновляет серию с ближайшего будущего срабатывания:
+// пропущенные за время паузы срабатывания не отправляются.
+func (u *ScheduleUsecase) Resume(ctx context.Context, id string) (*domain.Schedule, error) {
+	s, err := u.postgres.GetScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Status == domain.ScheduleActive {
+		return s, nil
+	}
+
+	next, ok, err := s.Next(time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSchedule, err)
+	}
+
+	s.Status = domain.ScheduleActive
+	s.NextRunAt = &next
+	if !ok {
+		s.Status = domain.ScheduleFinished
+		s.NextRunAt = nil
+	}
+
+	if err := u.postgres.UpdateScheduleState(ctx, id, s.Status, s.NextRunAt); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (u *ScheduleUsecase) Delete(ctx context.Context, id string) error {
+	canceled, err := u.postgres.DeleteSchedule(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrScheduleNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+
+	// отмена массовая, как и replay: кеш отмененных notify всегда инвалидируется
+	for _, notifyID := range canceled {
+		if err := u.redis.Delete(ctx, notifyID); err != nil {
+			u.log.Error().Err(err).Str("id", notifyID).Msg("failed to invalidate canceled notify in redis")
+		}
+	}
+	return nil
+}
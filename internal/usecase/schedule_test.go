@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScheduleUsecase_Create_Cron(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
+	usecase := NewScheduleUsecase(mockSchedules, mocks.NewMockNotifyRedis(ctrl), log.New())
+
+	ctx := context.Background()
+	s := domain.NewSchedule()
+	s.CronExpr = "0 9 * * *"
+	s.Timezone = "Europe/Moscow"
+
+	// Expect: сохранение серии с рассчитанным первым срабатыванием
+	mockSchedules.EXPECT().
+		CreateSchedule(ctx, s).
+		Return(nil).
+		Times(1)
+
+	// Act
+	created, err := usecase.Create(ctx, s)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.NextRunAt == nil {
+		t.Fatal("expected next_run_at to be set")
+	}
+
+	loc, _ := time.LoadLocation("Europe/Moscow")
+	local := created.NextRunAt.In(loc)
+	if local.Hour() != 9 || local.Minute() != 0 {
+		t.Errorf("expected next run at 09:00 Moscow time, got %v", local)
+	}
+	if !created.NextRunAt.After(time.Now()) {
+		t.Errorf("expected next run in the future, got %v", created.NextRunAt)
+	}
+}
+
+func TestScheduleUsecase_Create_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
+	usecase := NewScheduleUsecase(mockSchedules, mocks.NewMockNotifyRedis(ctrl), log.New())
+
+	ctx := context.Background()
+
+	cases := map[string]*domain.Schedule{
+		"no rule":       {},
+		"both rules":    {CronExpr: "@daily", Interval: time.Hour},
+		"bad cron":      {CronExpr: "61 * * * *"},
+		"bad timezone":  {CronExpr: "@daily", Timezone: "Mars/Olympus"},
+		"tiny interval": {Interval: time.Second},
+	}
+
+	for name, s := range cases {
+		// Act
+		_, err := usecase.Create(ctx, s)
+
+		// Assert - в БД ничего не пишется
+		if !errors.Is(err, domain.ErrInvalidSchedule) {
+			t.Errorf("%s: expected ErrInvalidSchedule, got %v", name, err)
+		}
+	}
+}
+
+func TestScheduleUsecase_Resume_SkipsMissedRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
+	usecase := NewScheduleUsecase(mockSchedules, mocks.NewMockNotifyRedis(ctrl), log.New())
+
+	ctx := context.Background()
+	startAt := time.Now().Add(-10 * time.Hour).Truncate(time.Second)
+	staleNext := startAt.Add(time.Hour)
+
+	paused := &domain.Schedule{
+		ID:        "schedule-1",
+		Interval:  time.Hour,
+		StartAt:   startAt,
+		NextRunAt: &staleNext,
+		Status:    domain.SchedulePaused,
+	}
+
+	mockSchedules.EXPECT().
+		GetScheduleByID(ctx, paused.ID).
+		Return(paused, nil).
+		Times(1)
+
+	// Expect: серия активна, следующее срабатывание в будущем
+	mockSchedules.EXPECT().
+		UpdateScheduleState(ctx, paused.ID, domain.ScheduleActive, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ domain.ScheduleStatus, next *time.Time) error {
+			if next == nil || !next.After(time.Now()) {
+				t.Errorf("expected next run in the future, got %v", next)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// Act
+	resumed, err := usecase.Resume(ctx, paused.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resumed.Status != domain.ScheduleActive {
+		t.Errorf("expected status %v, got %v", domain.ScheduleActive, resumed.Status)
+	}
+}
+
+func TestScheduleUsecase_Delete_InvalidatesCanceledNotifies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	usecase := NewScheduleUsecase(mockSchedules, mockRedis, log.New())
+
+	ctx := context.Background()
+
+	// Expect: серия удалена, два ее notify отменены
+	mockSchedules.EXPECT().
+		DeleteSchedule(ctx, "schedule-1").
+		Return([]string{"notify-1", "notify-2"}, nil).
+		Times(1)
+
+	// Expect: отмененные notify убраны из кеша, ошибка кеша не мешает удалению
+	mockRedis.EXPECT().Delete(ctx, "notify-1").Return(errors.New("redis down")).Times(1)
+	mockRedis.EXPECT().Delete(ctx, "notify-2").Return(nil).Times(1)
+
+	// Act
+	err := usecase.Delete(ctx, "schedule-1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestSchedule_Next_Interval(t *testing.T) {
+	startAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	endAt := startAt.Add(3 * time.Hour)
+	s := &domain.Schedule{Interval: time.Hour, StartAt: startAt, EndAt: &endAt}
+
+	// до начала серии первым срабатыванием является StartAt
+	next, ok, err := s.Next(startAt.Add(-time.Minute))
+	if err != nil || !ok || !next.Equal(startAt) {
+		t.Errorf("expected %v, got %v (ok=%v, err=%v)", startAt, next, ok, err)
+	}
+
+	// срабатывание строго после переданного момента
+	next, ok, _ = s.Next(startAt.Add(time.Hour))
+	if !ok || !next.Equal(startAt.Add(2*time.Hour)) {
+		t.Errorf("expected %v, got %v", startAt.Add(2*time.Hour), next)
+	}
+
+	// после EndAt серия закончена
+	_, ok, _ = s.Next(endAt)
+	if ok {
+		t.Error("expected schedule to be finished after end_at")
+	}
+}
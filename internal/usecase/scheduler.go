@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/config"
@@ -11,27 +13,42 @@ import (
 
 type Scheduler struct {
 	postgres          domain.NotifyPostgres
+	redis             domain.NotifyRedis
+	schedules         domain.SchedulePostgres
 	rabbit            domain.QueueProvider
 	interval          time.Duration
 	batchSize         int
-	maxRetries        int
+	retries           domain.RetryPolicies
 	visibilityTimeout time.Duration
+	workerID          string
+	cacheMode         domain.CacheMode
+	metrics           domain.Metrics
 	log               log.Log
+
+	lastTick atomic.Int64 // unix nano
 }
 
 func NewScheduler(
 	postgres domain.NotifyPostgres,
+	redis domain.NotifyRedis,
+	schedules domain.SchedulePostgres,
 	rabbit domain.QueueProvider,
 	cfg config.NotifierConfig,
+	metrics domain.Metrics,
 	log log.Log,
 ) domain.Scheduler {
 	return &Scheduler{
 		postgres:          postgres,
+		redis:             redis,
+		schedules:         schedules,
 		rabbit:            rabbit,
 		interval:          cfg.Interval,
 		batchSize:         cfg.BatchSize,
-		maxRetries:        cfg.MaxRetries,
+		retries:           cfg.RetryPolicies(),
 		visibilityTimeout: cfg.VisibilityTimeout,
+		workerID:          cfg.InstanceID(),
+		cacheMode:         cfg.CacheMode,
+		metrics:           metrics,
 		log:               log,
 	}
 }
@@ -53,35 +70,144 @@ func (s *Scheduler) Run(ctx context.Context) {
 	}
 }
 
+func (s *Scheduler) LastTick() time.Time {
+	nano := s.lastTick.Load()
+	if nano == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nano)
+}
+
 func (s *Scheduler) process(ctx context.Context) {
+	defer func() { s.lastTick.Store(time.Now().UnixNano()) }()
+
+	// создаем notify для наступивших срабатываний повторяющихся серий,
+	// чтобы они попали в ту же выборку ниже
+	materialized, err := s.schedules.MaterializeDue(ctx, s.batchSize)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Scheduler: failed to materialize schedules")
+	} else if materialized > 0 {
+		s.log.Info().Int("count", materialized).Msg("Scheduler: materialized recurring notifies")
+	}
+
 	// забираем пачку уведомлений из БД (StatusPending -> StatusInProcess)
 	notifies, err := s.postgres.LockAndFetchReady(ctx, s.batchSize, s.visibilityTimeout)
 	if err != nil {
 		s.log.Error().Err(err).Msg("Scheduler: failed to fetch notifies from db")
+	} else {
+		s.metrics.ObserveBatch(len(notifies))
+	}
+	now := time.Now()
+	ready := notifies[:0]
+	for _, n := range notifies {
+		if s.deferToWindow(ctx, n, now) {
+			continue
+		}
+		s.syncCache(ctx, n.ID, domain.StatusInProcess, nil, n.RetryCount, n.LastError)
+		ready = append(ready, n)
 	}
+	notifies = ready
+	defer s.updateBacklog(ctx)
 
 	if len(notifies) == 0 {
 		return
 	}
 
 	for _, n := range notifies {
-		if err := s.rabbit.Publish(ctx, n); err != nil {
-			s.log.Error().Err(err).Msg("Scheduler: failed to publish notify")
-			errStr := err.Error()
-
-			var status domain.Status
-
-			if n.RetryCount < s.maxRetries {
-				n.RetryCount += 1
-				status = domain.StatusPending
-			} else {
-				n.RetryCount = 0
-				status = domain.StatusFailed
-			}
+		attempt := domain.NewAttempt(n, domain.AttemptPublish, s.workerID)
+		start := time.Now()
+		err := s.rabbit.Publish(ctx, n)
+		attempt.Latency = time.Since(start)
+		s.metrics.ObservePublish(n.Channel, attempt.Latency, err)
+
+		if err == nil {
+			attempt.Outcome = domain.OutcomeSuccess
+			s.recordAttempt(ctx, attempt)
+			continue
+		}
+
+		s.log.Error().Err(err).Msg("Scheduler: failed to publish notify")
+		errStr := err.Error()
+
+		// неудачная публикация считается попыткой доставки и подчиняется той же политике
+		status := domain.StatusFailed
+		attempt.Outcome = domain.OutcomeFailed
+		n.RetryCount++
+		next, ok := s.retries.For(n).Next(n.RetryCount, n.CreatedAt, time.Now())
+		if ok {
+			status = domain.StatusPending
+			attempt.Outcome = domain.OutcomeRetry
+			n.ScheduledAt = next
+		}
+		attempt.Error = errStr
+		s.recordAttempt(ctx, attempt)
+		if status == domain.StatusFailed {
+			s.metrics.NotifyFailed(n.Channel)
+		}
 
-			if err := s.postgres.UpdateStatus(ctx, n.ID, status, &n.ScheduledAt, n.RetryCount, &errStr); err != nil {
-				s.log.Error().Err(err).Msg("Scheduler: failed to update status in db")
+		if err := s.postgres.UpdateStatus(ctx, n.ID, status, &n.ScheduledAt, n.RetryCount, &errStr); err != nil {
+			if errors.Is(err, domain.ErrNotifyCanceled) {
+				s.log.Info().Str("id", n.ID).Msg("Scheduler: notify canceled while in process, dropping")
+				continue
 			}
+			s.log.Error().Err(err).Msg("Scheduler: failed to update status in db")
+			continue
 		}
+		s.syncCache(ctx, n.ID, status, &n.ScheduledAt, n.RetryCount, &errStr)
+	}
+}
+
+// deferToWindow возвращает notify, захваченный вне окна доставки, в Pending
+// на начало ближайшего окна. Попыткой доставки это не считается
+func (s *Scheduler) deferToWindow(ctx context.Context, n *domain.Notify, now time.Time) bool {
+	if n.DeliveryWindow == nil {
+		return false
+	}
+	next := n.DeliveryWindow.Next(now)
+	if !next.After(now) {
+		return false
+	}
+
+	// при ошибке notify остается InProcess и вернется в выборку после visibility timeout
+	if err := s.postgres.Reschedule(ctx, n.ID, next); err != nil {
+		if errors.Is(err, domain.ErrNotifyCanceled) {
+			return true
+		}
+		s.log.Error().Err(err).Str("id", n.ID).Msg("Scheduler: failed to defer notify to delivery window")
+		return true
+	}
+	s.syncCache(ctx, n.ID, domain.StatusPending, &next, n.RetryCount, n.LastError)
+	s.log.Info().Str("id", n.ID).Time("send_at", next).Msg("Scheduler: notify deferred to delivery window")
+	return true
+}
+
+// syncCache отражает смену статуса в кеше Redis, ошибка только логируется
+func (s *Scheduler) syncCache(
+	ctx context.Context,
+	id string,
+	status domain.Status,
+	scheduledAt *time.Time,
+	retryCount int,
+	lastErr *string,
+) {
+	if err := s.cacheMode.SyncStatus(ctx, s.redis, id, status, scheduledAt, retryCount, lastErr); err != nil {
+		s.log.Error().Err(err).Str("id", id).Msg("Scheduler: failed to sync status to redis")
+	}
+}
+
+// updateBacklog обновляет gauge очереди Pending после обработки пачки
+func (s *Scheduler) updateBacklog(ctx context.Context) {
+	count, err := s.postgres.CountPending(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Scheduler: failed to count pending notifies")
+		return
+	}
+	s.metrics.SetBacklog(count)
+}
+
+// recordAttempt пишет попытку в историю. Ошибка записи не влияет на доставку
+func (s *Scheduler) recordAttempt(ctx context.Context, a *domain.Attempt) {
+	if err := s.postgres.RecordAttempt(ctx, a); err != nil {
+		s.log.Error().Err(err).Str("id", a.NotifyID).Msg("Scheduler: failed to record attempt")
 	}
 }
@@ -3,10 +3,13 @@ package usecase
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/config"
+	"github.com/adexcell/delayed-notifier/internal/adapter/memory"
+	"github.com/adexcell/delayed-notifier/internal/adapter/metrics"
 	"github.com/adexcell/delayed-notifier/internal/domain"
 	"github.com/adexcell/delayed-notifier/internal/mocks"
 	"github.com/adexcell/delayed-notifier/pkg/log"
@@ -18,7 +21,9 @@ func TestScheduler_Process_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
 
 	cfg := config.NotifierConfig{
 		BatchSize:         10,
@@ -26,7 +31,7 @@ func TestScheduler_Process_Success(t *testing.T) {
 		MaxRetries:        3,
 	}
 
-	scheduler := NewScheduler(mockPostgres, mockQueue, cfg, log.New())
+	scheduler := NewScheduler(mockPostgres, mockRedis, mockSchedules, mockQueue, cfg, metrics.NewNop(), log.New())
 
 	// Используем приватный метод process для теста, чтобы не запускать бесконечный цикл Run
 	// Но так как process приватный, мы не можем его вызвать из update_test.go если он в другом пакете.
@@ -39,17 +44,46 @@ func TestScheduler_Process_Success(t *testing.T) {
 		{ID: "2", Status: domain.StatusPending, ScheduledAt: time.Now()},
 	}
 
+	// Expect: материализация повторяющихся серий (нет наступивших)
+	mockSchedules.EXPECT().
+		MaterializeDue(ctx, cfg.BatchSize).
+		Return(0, nil).
+		Times(1)
+
 	// Expect: LockAndFetchReady возвращает уведомления
 	mockPostgres.EXPECT().
 		LockAndFetchReady(ctx, cfg.BatchSize, cfg.VisibilityTimeout).
 		Return(notifies, nil).
 		Times(1)
 
+	// Expect: захваченные notify переходят в InProcess и в кеше
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, gomock.Any(), domain.StatusInProcess, nil, gomock.Any(), nil).
+		Return(nil).
+		Times(2)
+
+	// Expect: обновление размера очереди Pending после обработки пачки
+	mockPostgres.EXPECT().
+		CountPending(ctx).
+		Return(0, nil).
+		Times(1)
+
 	// Expect: Publish для каждого уведомления
 	// Мы не можем гарантировать порядок, если горутины, но тут синхронно.
 	mockQueue.EXPECT().Publish(ctx, notifies[0]).Return(nil).Times(1)
 	mockQueue.EXPECT().Publish(ctx, notifies[1]).Return(nil).Times(1)
 
+	// Expect: успешные публикации попадают в историю попыток
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Attempt) error {
+			if a.Kind != domain.AttemptPublish || a.Outcome != domain.OutcomeSuccess {
+				t.Errorf("unexpected attempt: %+v", a)
+			}
+			return nil
+		}).
+		Times(2)
+
 	// Для теста экспортируем метод или используем reflect/linkname, но проще просто проверить логику
 	// вызова зависимостей.
 	// В Go принято тестировать публичный API. Если process приватный, тестируем через Run?
@@ -75,32 +109,67 @@ func TestScheduler_Process_PublishError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
 
 	cfg := config.NotifierConfig{MaxRetries: 3}
-	scheduler := NewScheduler(mockPostgres, mockQueue, cfg, log.New())
+	scheduler := NewScheduler(mockPostgres, mockRedis, mockSchedules, mockQueue, cfg, metrics.NewNop(), log.New())
 	s := scheduler.(*Scheduler)
 
 	ctx := context.Background()
 	notify := &domain.Notify{ID: "1", RetryCount: 0}
 
+	// Expect: материализация повторяющихся серий (нет наступивших)
+	mockSchedules.EXPECT().
+		MaterializeDue(ctx, cfg.BatchSize).
+		Return(0, nil).
+		Times(1)
+
 	mockPostgres.EXPECT().
 		LockAndFetchReady(ctx, cfg.BatchSize, cfg.VisibilityTimeout).
 		Return([]*domain.Notify{notify}, nil).
 		Times(1)
 
+	// Expect: захваченные notify переходят в InProcess и в кеше
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, gomock.Any(), domain.StatusInProcess, nil, gomock.Any(), nil).
+		Return(nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		CountPending(ctx).
+		Return(1, nil).
+		Times(1)
+
 	// Expect: ошибка публикации
 	mockQueue.EXPECT().
 		Publish(ctx, notify).
 		Return(errors.New("queue error")).
 		Times(1)
 
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Attempt) error {
+			if a.Outcome != domain.OutcomeRetry || a.Error != "queue error" {
+				t.Errorf("unexpected attempt: %+v", a)
+			}
+			return nil
+		}).
+		Times(1)
+
 	// Expect: обновление статуса (retry count + 1)
 	mockPostgres.EXPECT().
 		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 1, gomock.Any()).
 		Return(nil).
 		Times(1)
 
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
 	s.process(ctx)
 }
 
@@ -109,33 +178,449 @@ func TestScheduler_Process_MaxRetries(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
 
 	cfg := config.NotifierConfig{MaxRetries: 3}
-	scheduler := NewScheduler(mockPostgres, mockQueue, cfg, log.New())
+	scheduler := NewScheduler(mockPostgres, mockRedis, mockSchedules, mockQueue, cfg, metrics.NewNop(), log.New())
+	s := scheduler.(*Scheduler)
+
+	ctx := context.Background()
+	notify := &domain.Notify{ID: "1", RetryCount: 3} // Уже 3 попытки
+	// Неудачная публикация - 4-я попытка, политика по умолчанию разрешает MaxRetries=3 -> Failed.
+
+	// Expect: материализация повторяющихся серий (нет наступивших)
+	mockSchedules.EXPECT().
+		MaterializeDue(ctx, cfg.BatchSize).
+		Return(0, nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		LockAndFetchReady(ctx, cfg.BatchSize, cfg.VisibilityTimeout).
+		Return([]*domain.Notify{notify}, nil).
+		Times(1)
+
+	// Expect: захваченные notify переходят в InProcess и в кеше
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, gomock.Any(), domain.StatusInProcess, nil, gomock.Any(), nil).
+		Return(nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		CountPending(ctx).
+		Return(1, nil).
+		Times(1)
+
+	mockQueue.EXPECT().
+		Publish(ctx, notify).
+		Return(errors.New("queue error")).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Attempt) error {
+			if a.Outcome != domain.OutcomeFailed || a.Error != "queue error" {
+				t.Errorf("unexpected attempt: %+v", a)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// Expect: статус Failed, число попыток сохраняется
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, gomock.Any(), 4, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, gomock.Any(), 4, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	s.process(ctx)
+}
+
+func TestScheduler_Process_PublishError_ChannelPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
+
+	cfg := config.NotifierConfig{
+		MaxRetries: 3,
+		ChannelRetry: map[string]domain.RetryPolicy{
+			"webhook": {Kind: domain.RetryCustom, Schedule: []time.Duration{30 * time.Second, 5 * time.Minute}},
+		},
+	}
+	scheduler := NewScheduler(mockPostgres, mockRedis, mockSchedules, mockQueue, cfg, metrics.NewNop(), log.New())
 	s := scheduler.(*Scheduler)
 
 	ctx := context.Background()
-	notify := &domain.Notify{ID: "1", RetryCount: 3} // Уже 3 попытки (достигнут максимум при следующей ошибке?)
-	// Логика в scheduler.go:
-	// if n.RetryCount < s.maxRetries { status = Pending } else { status = Failed }
-	// Если MaxRetries=3, и RetryCount=3. 3 < 3 is false. -> Failed.
+	notify := &domain.Notify{ID: "1", Channel: "webhook", RetryCount: 1, CreatedAt: time.Now()}
+
+	mockSchedules.EXPECT().
+		MaterializeDue(ctx, cfg.BatchSize).
+		Return(0, nil).
+		Times(1)
 
 	mockPostgres.EXPECT().
 		LockAndFetchReady(ctx, cfg.BatchSize, cfg.VisibilityTimeout).
 		Return([]*domain.Notify{notify}, nil).
 		Times(1)
 
+	// Expect: захваченные notify переходят в InProcess и в кеше
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, gomock.Any(), domain.StatusInProcess, nil, gomock.Any(), nil).
+		Return(nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		CountPending(ctx).
+		Return(1, nil).
+		Times(1)
+
 	mockQueue.EXPECT().
 		Publish(ctx, notify).
 		Return(errors.New("queue error")).
 		Times(1)
 
-	// Expect: статус Failed
 	mockPostgres.EXPECT().
-		UpdateStatus(ctx, notify.ID, domain.StatusFailed, gomock.Any(), 0, gomock.Any()).
+		RecordAttempt(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Attempt) error {
+			if a.Outcome != domain.OutcomeRetry || a.Error != "queue error" {
+				t.Errorf("unexpected attempt: %+v", a)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// Expect: вторая задержка из списка канала
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 2, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ domain.Status, scheduledAt *time.Time, _ int, _ *string) error {
+			delay := time.Until(*scheduledAt)
+			if delay < 4*time.Minute || delay > 5*time.Minute {
+				t.Errorf("expected retry in ~5m, got %v", delay)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 2, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	s.process(ctx)
+}
+
+func TestRetryPolicy_Next(t *testing.T) {
+	now := time.Now()
+
+	// фиксированная задержка до исчерпания попыток
+	fixed := domain.RetryPolicy{Kind: domain.RetryFixed, Delay: time.Minute, MaxAttempts: 3}
+	if next, ok := fixed.Next(2, now, now); !ok || !next.Equal(now.Add(time.Minute)) {
+		t.Errorf("fixed: expected retry in 1m, got %v (ok=%v)", next, ok)
+	}
+	if _, ok := fixed.Next(3, now, now); ok {
+		t.Error("fixed: expected attempts to be exhausted")
+	}
+
+	// экспонента с потолком и разбросом
+	exp := domain.RetryPolicy{
+		Kind: domain.RetryExponential, Delay: time.Minute, MaxDelay: 10 * time.Minute,
+		Multiplier: 2, Jitter: 0.2, MaxAttempts: 10,
+	}
+	for attempt, want := range map[int]time.Duration{1: time.Minute, 3: 4 * time.Minute, 8: 10 * time.Minute} {
+		next, ok := exp.Next(attempt, now, now)
+		delay := next.Sub(now)
+		if !ok || delay < time.Duration(float64(want)*0.8) || delay > time.Duration(float64(want)*1.2) {
+			t.Errorf("exponential attempt %d: expected ~%v, got %v", attempt, want, delay)
+		}
+	}
+
+	// список задержек задает и число попыток
+	custom := domain.RetryPolicy{Kind: domain.RetryCustom, Schedule: []time.Duration{time.Second, time.Hour}}
+	if next, ok := custom.Next(2, now, now); !ok || !next.Equal(now.Add(time.Hour)) {
+		t.Errorf("custom: expected retry in 1h, got %v (ok=%v)", next, ok)
+	}
+	if _, ok := custom.Next(3, now, now); ok {
+		t.Error("custom: expected schedule to be exhausted")
+	}
+
+	// попытка позже created_at + max_age не планируется
+	aged := domain.RetryPolicy{Kind: domain.RetryFixed, Delay: time.Hour, MaxAttempts: 10, MaxAge: 2 * time.Hour}
+	if _, ok := aged.Next(1, now.Add(-90*time.Minute), now); ok {
+		t.Error("max_age: expected cutoff")
+	}
+}
+
+func TestDeliveryWindow_Next(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.March, day, hour, min, 0, 0, moscow) // 2 марта 2026 - понедельник
+	}
+
+	workdays := domain.DeliveryWindow{
+		Timezone: "Europe/Moscow",
+		Start:    9 * 60,
+		End:      21 * 60,
+		Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+	night := domain.DeliveryWindow{Timezone: "Europe/Moscow", Start: 22 * 60, End: 6 * 60}
+
+	tests := []struct {
+		name   string
+		window domain.DeliveryWindow
+		t      time.Time
+		want   time.Time
+	}{
+		{name: "inside", window: workdays, t: at(2, 12, 0), want: at(2, 12, 0)},
+		{name: "before start", window: workdays, t: at(2, 7, 30), want: at(2, 9, 0)},
+		{name: "after end", window: workdays, t: at(2, 21, 0), want: at(3, 9, 0)},
+		{name: "friday evening to monday", window: workdays, t: at(6, 22, 0), want: at(9, 9, 0)},
+		{name: "weekend", window: workdays, t: at(8, 12, 0), want: at(9, 9, 0)},
+		{name: "overnight after midnight", window: night, t: at(3, 2, 0), want: at(3, 2, 0)},
+		{name: "overnight daytime", window: night, t: at(3, 12, 0), want: at(3, 22, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.window.Validate(); err != nil {
+				t.Fatalf("invalid window: %v", err)
+			}
+			if got := tt.window.Next(tt.t.UTC()); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got.In(moscow))
+			}
+		})
+	}
+
+	if err := (domain.DeliveryWindow{Timezone: "Mars/Olympus", Start: 0, End: 60}).Validate(); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+}
+
+func TestScheduler_Process_DeliveryWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
+
+	cfg := config.NotifierConfig{BatchSize: 10, VisibilityTimeout: time.Minute}
+	scheduler := NewScheduler(mockPostgres, mockRedis, mockSchedules, mockQueue, cfg, metrics.NewNop(), log.New()).(*Scheduler)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	// окно, которое только что закрылось: следующее откроется через сутки без 2 минут
+	closed := &domain.DeliveryWindow{
+		Timezone: "UTC",
+		Start:    domain.TimeOfDay((now.Hour()*60 + now.Minute() + 1438) % 1440),
+		End:      domain.TimeOfDay((now.Hour()*60 + now.Minute() + 1439) % 1440),
+	}
+	if closed.End == 0 {
+		closed.End = 1440
+	}
+	lastErr := "previous error"
+	outside := &domain.Notify{ID: "outside", Status: domain.StatusInProcess, RetryCount: 1, LastError: &lastErr, DeliveryWindow: closed}
+	always := &domain.Notify{ID: "always", Status: domain.StatusInProcess}
+
+	mockSchedules.EXPECT().MaterializeDue(ctx, cfg.BatchSize).Return(0, nil)
+	mockPostgres.EXPECT().
+		LockAndFetchReady(ctx, cfg.BatchSize, cfg.VisibilityTimeout).
+		Return([]*domain.Notify{outside, always}, nil)
+
+	// Expect: notify вне окна возвращается в Pending на начало окна без попытки доставки,
+	// прошлая ошибка не дублируется в истории
+	mockPostgres.EXPECT().
+		Reschedule(ctx, outside.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, scheduledAt time.Time) error {
+			if !closed.Contains(scheduledAt) || scheduledAt.Sub(now) < 23*time.Hour {
+				t.Errorf("expected deferral to next window, got %v", scheduledAt)
+			}
+			return nil
+		})
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, outside.ID, domain.StatusPending, gomock.Any(), 1, &lastErr).
+		Return(nil)
+
+	// Expect: остальные публикуются как обычно
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, always.ID, domain.StatusInProcess, nil, 0, nil).
+		Return(nil)
+	mockQueue.EXPECT().Publish(ctx, always).Return(nil)
+	mockPostgres.EXPECT().RecordAttempt(ctx, gomock.Any()).Return(nil).Times(1)
+	mockPostgres.EXPECT().CountPending(ctx).Return(1, nil)
+
+	// Act
+	scheduler.process(ctx)
+}
+
+func TestScheduler_Process_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
+	mockMetrics := mocks.NewMockMetrics(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 0, BatchSize: 10}
+	scheduler := NewScheduler(mockPostgres, mockRedis, mockSchedules, mockQueue, cfg, mockMetrics, log.New())
+	s := scheduler.(*Scheduler)
+
+	ctx := context.Background()
+	sent := &domain.Notify{ID: "1", Channel: "email"}
+	failed := &domain.Notify{ID: "2", Channel: "telegram"}
+
+	mockSchedules.EXPECT().
+		MaterializeDue(ctx, cfg.BatchSize).
+		Return(0, nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		LockAndFetchReady(ctx, cfg.BatchSize, cfg.VisibilityTimeout).
+		Return([]*domain.Notify{sent, failed}, nil).
+		Times(1)
+
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, gomock.Any(), domain.StatusInProcess, nil, 0, nil).
+		Return(nil).
+		Times(2)
+
+	mockQueue.EXPECT().Publish(ctx, sent).Return(nil).Times(1)
+	mockQueue.EXPECT().Publish(ctx, failed).Return(errors.New("queue error")).Times(1)
+
+	mockPostgres.EXPECT().RecordAttempt(ctx, gomock.Any()).Return(nil).Times(2)
+
+	// Expect: повторы запрещены политикой, notify сразу Failed
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, failed.ID, domain.StatusFailed, gomock.Any(), 1, gomock.Any()).
 		Return(nil).
 		Times(1)
 
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, failed.ID, domain.StatusFailed, gomock.Any(), 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		CountPending(ctx).
+		Return(42, nil).
+		Times(1)
+
+	// Expect: размер пачки, латентность каждой публикации, счетчик Failed и backlog
+	mockMetrics.EXPECT().ObserveBatch(2).Times(1)
+	mockMetrics.EXPECT().ObservePublish("email", gomock.Any(), nil).Times(1)
+	mockMetrics.EXPECT().ObservePublish("telegram", gomock.Any(), gomock.Not(nil)).Times(1)
+	mockMetrics.EXPECT().NotifyFailed("telegram").Times(1)
+	mockMetrics.EXPECT().SetBacklog(42).Times(1)
+
+	s.process(ctx)
+}
+
+func TestScheduler_LastTick(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSchedules := mocks.NewMockSchedulePostgres(ctrl)
+
+	cfg := config.NotifierConfig{BatchSize: 10}
+	scheduler := NewScheduler(mockPostgres, mockRedis, mockSchedules, mockQueue, cfg, metrics.NewNop(), log.New())
+	s := scheduler.(*Scheduler)
+
+	ctx := context.Background()
+
+	// Assert: до первого тика время нулевое
+	if !s.LastTick().IsZero() {
+		t.Fatalf("expected zero last tick, got %v", s.LastTick())
+	}
+
+	mockSchedules.EXPECT().MaterializeDue(ctx, cfg.BatchSize).Return(0, nil).Times(1)
+	mockPostgres.EXPECT().LockAndFetchReady(ctx, cfg.BatchSize, cfg.VisibilityTimeout).Return(nil, nil).Times(1)
+	mockPostgres.EXPECT().CountPending(ctx).Return(0, nil).Times(1)
+
+	// Act
+	before := time.Now()
 	s.process(ctx)
+
+	// Assert: тик отмечается даже для пустой пачки
+	if s.LastTick().Before(before) {
+		t.Errorf("expected last tick after %v, got %v", before, s.LastTick())
+	}
+}
+
+func TestScheduler_Process_ConcurrentSchedulersPublishOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := memory.NewDB()
+	notifies, schedules, cache := memory.NewNotifyPostgres(db), memory.NewSchedulePostgres(db), memory.NewRedis(time.Hour)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	ctx := context.Background()
+
+	const count = 30
+	for range count {
+		n := domain.NewNotify()
+		n.Channel = "email"
+		n.Status = domain.StatusPending
+		n.ScheduledAt = time.Now().Add(-time.Second)
+		if err := notifies.Create(ctx, n); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	// Expect: каждый notify публикуется ровно один раз, сколько бы планировщиков ни работало
+	var (
+		mu        sync.Mutex
+		published = make(map[string]int)
+	)
+	mockQueue.EXPECT().
+		Publish(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, n *domain.Notify) error {
+			mu.Lock()
+			published[n.ID]++
+			mu.Unlock()
+			return nil
+		}).
+		Times(count)
+
+	cfg := config.NotifierConfig{BatchSize: 4, VisibilityTimeout: time.Minute, MaxRetries: 3}
+
+	// Act
+	var wg sync.WaitGroup
+	for range 3 {
+		scheduler := NewScheduler(notifies, cache, schedules, mockQueue, cfg, metrics.NewNop(), log.New()).(*Scheduler)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range count / cfg.BatchSize {
+				scheduler.process(ctx)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if len(published) != count {
+		t.Errorf("expected %d notifies published, got %d", count, len(published))
+	}
+	for id, times := range published {
+		if times != 1 {
+			t.Errorf("notify %s published %d times", id, times)
+		}
+	}
 }
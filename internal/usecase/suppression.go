@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+type SuppressionUsecase struct {
+	log         log.Log
+	postgres    domain.SuppressionPostgres
+	unsubscribe domain.UnsubscribeConfig
+}
+
+func NewSuppressionUsecase(p domain.SuppressionPostgres, unsubscribe domain.UnsubscribeConfig, l log.Log) domain.SuppressionUsecase {
+	return &SuppressionUsecase{
+		log:         l,
+		postgres:    p,
+		unsubscribe: unsubscribe,
+	}
+}
+
+// Create добавляет запись. Запись для всех арендаторов затрагивает чужие рассылки,
+// поэтому ее может создать только DefaultTenant, вошедший по API-ключу
+func (u *SuppressionUsecase) Create(ctx context.Context, s *domain.Suppression, allTenants bool) (*domain.Suppression, error) {
+	s.TenantID = domain.TenantFor(ctx, "")
+	if allTenants {
+		if !domain.ManagesAllTenants(ctx) {
+			return nil, domain.ErrSuppressionScope
+		}
+		s.TenantID = ""
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.postgres.CreateSuppression(ctx, s); err != nil {
+		if errors.Is(err, domain.ErrSuppressionExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save suppression in db: %w", err)
+	}
+	return s, nil
+}
+
+func (u *SuppressionUsecase) List(ctx context.Context, filter domain.SuppressionFilter) ([]*domain.Suppression, error) {
+	filter.Target = domain.NormalizeTarget(filter.Channel, filter.Target)
+	return u.postgres.ListSuppressions(ctx, filter)
+}
+
+func (u *SuppressionUsecase) Delete(ctx context.Context, id string) error {
+	return u.postgres.DeleteSuppression(ctx, id)
+}
+
+func (u *SuppressionUsecase) Unsubscribe(ctx context.Context, token string) error {
+	s, err := u.unsubscribe.ParseToken(token)
+	if err != nil {
+		return err
+	}
+	if err := s.Validate(); err != nil {
+		return domain.ErrInvalidUnsubscribeToken
+	}
+
+	if err := u.postgres.CreateSuppression(ctx, s); err != nil {
+		if errors.Is(err, domain.ErrSuppressionExists) {
+			return nil
+		}
+		return fmt.Errorf("failed to save unsubscribe in db: %w", err)
+	}
+
+	u.log.Info().
+		Str("tenant", s.TenantID).
+		Str("channel", s.Channel).
+		Str("category", s.Category).
+		Msg("target unsubscribed")
+	return nil
+}
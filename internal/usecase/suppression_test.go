@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSuppressionUsecase_Create_Scope(t *testing.T) {
+	tests := []struct {
+		name       string
+		tenant     string
+		apiKey     bool
+		allTenants bool
+		wantTenant string
+		wantErr    error
+	}{
+		{name: "own tenant", tenant: "team-a", wantTenant: "team-a"},
+		{name: "all tenants by default tenant", tenant: domain.DefaultTenant, apiKey: true, allTenants: true, wantTenant: ""},
+		{name: "all tenants without auth", tenant: domain.DefaultTenant, allTenants: true, wantErr: domain.ErrSuppressionScope},
+		{name: "all tenants by other tenant", tenant: "team-a", allTenants: true, wantErr: domain.ErrSuppressionScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPostgres := mocks.NewMockSuppressionPostgres(ctrl)
+			usecase := NewSuppressionUsecase(mockPostgres, domain.UnsubscribeConfig{}, log.New())
+			ctx := domain.WithTenant(context.Background(), tt.tenant)
+			if tt.apiKey {
+				ctx = domain.WithAllTenants(ctx)
+			}
+
+			s := domain.NewSuppression()
+			s.Channel = "email"
+			s.Target = " User@Example.com "
+
+			// Expect: запись сохраняется с арендатором по охвату и нормализованным адресом
+			if tt.wantErr == nil {
+				mockPostgres.EXPECT().
+					CreateSuppression(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, s *domain.Suppression) error {
+						if s.TenantID != tt.wantTenant || s.Target != "user@example.com" {
+							t.Errorf("unexpected suppression: %+v", s)
+						}
+						return nil
+					}).
+					Times(1)
+			}
+
+			// Act
+			_, err := usecase.Create(ctx, s, tt.allTenants)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSuppressionUsecase_Unsubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := domain.UnsubscribeConfig{BaseURL: "https://notifier.example.com", Secret: "secret"}
+	mockPostgres := mocks.NewMockSuppressionPostgres(ctrl)
+	usecase := NewSuppressionUsecase(mockPostgres, cfg, log.New())
+	ctx := context.Background()
+
+	url := cfg.URL(&domain.Notify{TenantID: "team-a", Channel: "email", Target: "user@example.com", Category: "marketing"})
+	token := url[len("https://notifier.example.com/unsubscribe/"):]
+
+	// Expect: отписка от категории арендатора, повторная отписка не ошибка
+	mockPostgres.EXPECT().
+		CreateSuppression(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, s *domain.Suppression) error {
+			if s.TenantID != "team-a" || s.Category != "marketing" || s.Reason != domain.ReasonUnsubscribe {
+				t.Errorf("unexpected suppression: %+v", s)
+			}
+			return nil
+		}).
+		Times(1)
+	mockPostgres.EXPECT().
+		CreateSuppression(ctx, gomock.Any()).
+		Return(domain.ErrSuppressionExists).
+		Times(1)
+
+	// Act
+	first := usecase.Unsubscribe(ctx, token)
+	second := usecase.Unsubscribe(ctx, token)
+	forged := usecase.Unsubscribe(ctx, token[:len(token)-2]+"xx")
+
+	// Assert
+	if first != nil || second != nil {
+		t.Errorf("expected unsubscribe to succeed twice, got %v and %v", first, second)
+	}
+	if !errors.Is(forged, domain.ErrInvalidUnsubscribeToken) {
+		t.Errorf("expected ErrInvalidUnsubscribeToken for forged token, got %v", forged)
+	}
+}
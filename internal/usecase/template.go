@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+)
+
+type TemplateUsecase struct {
+	log      log.Log
+	postgres domain.TemplatePostgres
+}
+
+func NewTemplateUsecase(p domain.TemplatePostgres, l log.Log) domain.TemplateUsecase {
+	return &TemplateUsecase{
+		log:      l,
+		postgres: p,
+	}
+}
+
+// Create создает первую версию шаблона
+func (u *TemplateUsecase) Create(ctx context.Context, t *domain.Template) (*domain.Template, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	_, err := u.postgres.GetTemplate(ctx, t.Name, t.Channel, 0)
+	if err == nil {
+		return nil, domain.ErrTemplateAlreadyExists
+	}
+	if !errors.Is(err, domain.ErrTemplateNotFound) {
+		return nil, fmt.Errorf("failed to check template: %w", err)
+	}
+
+	return u.save(ctx, t)
+}
+
+// Update сохраняет изменения как новую версию существующего шаблона
+func (u *TemplateUsecase) Update(ctx context.Context, t *domain.Template) (*domain.Template, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.postgres.GetTemplate(ctx, t.Name, t.Channel, 0); err != nil {
+		return nil, err
+	}
+
+	return u.save(ctx, t)
+}
+
+func (u *TemplateUsecase) Get(ctx context.Context, name, channel string, version int) (*domain.Template, error) {
+	return u.postgres.GetTemplate(ctx, name, channel, version)
+}
+
+func (u *TemplateUsecase) List(ctx context.Context) ([]*domain.Template, error) {
+	return u.postgres.ListTemplates(ctx)
+}
+
+func (u *TemplateUsecase) Versions(ctx context.Context, name, channel string) ([]*domain.Template, error) {
+	return u.postgres.ListTemplateVersions(ctx, name, channel)
+}
+
+func (u *TemplateUsecase) Delete(ctx context.Context, name, channel string) error {
+	return u.postgres.DeleteTemplate(ctx, name, channel)
+}
+
+func (u *TemplateUsecase) save(ctx context.Context, t *domain.Template) (*domain.Template, error) {
+	if err := u.postgres.CreateTemplateVersion(ctx, t); err != nil {
+		if errors.Is(err, domain.ErrTemplateAlreadyExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save template in db: %w", err)
+	}
+	return t, nil
+}
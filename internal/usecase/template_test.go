@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adexcell/delayed-notifier/internal/domain"
+	"github.com/adexcell/delayed-notifier/internal/mocks"
+	"github.com/adexcell/delayed-notifier/pkg/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTemplateUsecase_Create_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	usecase := NewTemplateUsecase(mockTemplates, log.New())
+
+	ctx := context.Background()
+	tmpl := domain.NewTemplate()
+	tmpl.Name = "welcome"
+	tmpl.Channel = "email"
+	tmpl.Subject = "Hello, {{.name}}"
+	tmpl.HTML = "<p>Hi {{.name}}</p>"
+
+	mockTemplates.EXPECT().
+		GetTemplate(ctx, "welcome", "email", 0).
+		Return(nil, domain.ErrTemplateNotFound).
+		Times(1)
+
+	// Expect: сохранение первой версии
+	mockTemplates.EXPECT().
+		CreateTemplateVersion(ctx, tmpl).
+		DoAndReturn(func(_ context.Context, t *domain.Template) error {
+			t.Version = 1
+			return nil
+		}).
+		Times(1)
+
+	// Act
+	created, err := usecase.Create(ctx, tmpl)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.Version != 1 {
+		t.Errorf("expected version 1, got %d", created.Version)
+	}
+}
+
+func TestTemplateUsecase_Create_AlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	usecase := NewTemplateUsecase(mockTemplates, log.New())
+
+	ctx := context.Background()
+	tmpl := &domain.Template{Name: "welcome", Channel: "email", Text: "Hi"}
+
+	// Expect: шаблон уже есть, новая версия не создается
+	mockTemplates.EXPECT().
+		GetTemplate(ctx, "welcome", "email", 0).
+		Return(&domain.Template{Name: "welcome", Channel: "email", Version: 3}, nil).
+		Times(1)
+
+	// Act
+	_, err := usecase.Create(ctx, tmpl)
+
+	// Assert
+	if !errors.Is(err, domain.ErrTemplateAlreadyExists) {
+		t.Errorf("expected ErrTemplateAlreadyExists, got %v", err)
+	}
+}
+
+func TestTemplateUsecase_Update_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	usecase := NewTemplateUsecase(mockTemplates, log.New())
+
+	ctx := context.Background()
+	tmpl := &domain.Template{Name: "missing", Channel: "telegram", Text: "Hi"}
+
+	mockTemplates.EXPECT().
+		GetTemplate(ctx, "missing", "telegram", 0).
+		Return(nil, domain.ErrTemplateNotFound).
+		Times(1)
+
+	// Act
+	_, err := usecase.Update(ctx, tmpl)
+
+	// Assert
+	if !errors.Is(err, domain.ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, got %v", err)
+	}
+}
+
+func TestTemplateUsecase_Create_InvalidSyntax(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	usecase := NewTemplateUsecase(mockTemplates, log.New())
+
+	// Act - незакрытое действие в шаблоне, в БД ничего не пишется
+	_, err := usecase.Create(context.Background(), &domain.Template{
+		Name:    "broken",
+		Channel: "email",
+		Text:    "Hi {{.name",
+	})
+
+	// Assert
+	if !errors.Is(err, domain.ErrInvalidTemplate) {
+		t.Errorf("expected ErrInvalidTemplate, got %v", err)
+	}
+}
+
+func TestTemplate_Render_EscapesHTML(t *testing.T) {
+	tmpl := &domain.Template{
+		Name:    "welcome",
+		Subject: "Hello, {{.name}}",
+		Text:    "Hi {{.name}}",
+		HTML:    "<p>Hi {{.name}}</p>",
+	}
+
+	msg, err := tmpl.Render(map[string]any{"name": "<script>"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// text/template не экранирует, html/template экранирует
+	if msg.Text != "Hi <script>" {
+		t.Errorf("unexpected text: %q", msg.Text)
+	}
+	if msg.HTML != "<p>Hi &lt;script&gt;</p>" {
+		t.Errorf("unexpected html: %q", msg.HTML)
+	}
+
+	// отсутствующая переменная - ошибка рендера
+	if _, err := tmpl.Render(nil); !errors.Is(err, domain.ErrTemplateRender) {
+		t.Errorf("expected ErrTemplateRender, got %v", err)
+	}
+}
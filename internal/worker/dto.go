@@ -16,6 +16,7 @@ type NotifyWorkerDTO struct {
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
 	RetryCount  int           `json:"retry_count"`
+	TenantID    string        `json:"tenant_id,omitempty"`
 	LastError   *string       `json:"last_error"`
 }
 
@@ -30,6 +31,7 @@ func toWorkerDTO(n *domain.Notify) *NotifyWorkerDTO {
 		CreatedAt:   n.CreatedAt,
 		UpdatedAt:   n.UpdatedAt,
 		RetryCount:  n.RetryCount,
+		TenantID:    n.TenantID,
 		LastError:   n.LastError,
 	}
 }
@@ -45,6 +47,7 @@ func toDomain(dto *NotifyWorkerDTO) *domain.Notify {
 		CreatedAt:   dto.CreatedAt,
 		UpdatedAt:   dto.UpdatedAt,
 		RetryCount:  dto.RetryCount,
+		TenantID:    dto.TenantID,
 		LastError:   dto.LastError,
 	}
 }
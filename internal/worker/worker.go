@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/adexcell/delayed-notifier/config"
@@ -13,12 +14,20 @@ import (
 )
 
 type NotifyConsumer struct {
-	postgres   domain.NotifyPostgres
-	rabbit     domain.QueueProvider
-	redis      domain.NotifyRedis
-	senders    map[string]domain.Sender
-	maxRetries int
-	log        log.Log
+	postgres  domain.NotifyPostgres
+	rabbit    domain.QueueProvider
+	redis     domain.NotifyRedis
+	templates domain.TemplatePostgres
+	// suppressions - список подавления, nil - без проверки
+	suppressions domain.SuppressionPostgres
+	senders      map[string]domain.Sender
+	limiter      domain.RateLimiter
+	limits       domain.RateLimits
+	retries      domain.RetryPolicies
+	workerID     string
+	cacheMode    domain.CacheMode
+	metrics      domain.Metrics
+	log          log.Log
 }
 
 func NewNotifyConsumer(
@@ -26,16 +35,27 @@ func NewNotifyConsumer(
 	postgres domain.NotifyPostgres,
 	rabbit domain.QueueProvider,
 	redis domain.NotifyRedis,
+	templates domain.TemplatePostgres,
+	suppressions domain.SuppressionPostgres,
 	senders map[string]domain.Sender,
+	limiter domain.RateLimiter,
+	metrics domain.Metrics,
 	log log.Log,
 ) *NotifyConsumer {
 	return &NotifyConsumer{
-		postgres:   postgres,
-		rabbit:     rabbit,
-		redis:      redis,
-		senders:    senders,
-		maxRetries: cfg.MaxRetries,
-		log:        log,
+		postgres:     postgres,
+		rabbit:       rabbit,
+		redis:        redis,
+		templates:    templates,
+		suppressions: suppressions,
+		senders:      senders,
+		limiter:      limiter,
+		limits:       cfg.RateLimit,
+		retries:      cfg.RetryPolicies(),
+		workerID:     cfg.InstanceID(),
+		cacheMode:    cfg.CacheMode,
+		metrics:      metrics,
+		log:          log,
 	}
 }
 
@@ -64,37 +84,97 @@ func (c *NotifyConsumer) Handle(ctx context.Context, payload []byte) error {
 		}
 	}
 
-	if currentNotify.Status == domain.StatusSent || currentNotify.Status == domain.StatusCanceled {
+	if currentNotify.Status == domain.StatusSent ||
+		currentNotify.Status == domain.StatusCanceled ||
+		currentNotify.Status == domain.StatusSuppressed {
 		c.log.Info().
 			Any("id", dto.ID).
 			Msgf("Consumer: notify %s already in final status (%v), skipping", dto.ID, currentNotify.Status)
 		return nil
 	}
 
+	// категория через очередь не передается, арендатора нет в старых сообщениях
+	n := toDomain(&dto)
+	n.Category = currentNotify.Category
+	if n.TenantID == "" {
+		n.TenantID = currentNotify.TenantID
+	}
+
+	// адресат в списке подавления: отправки нет, лимиты не тратятся
+	if c.suppressed(ctx, n, currentNotify) {
+		return nil
+	}
+
+	// лимит исчерпан: notify возвращается в Pending и уходит в очередь, когда появится токен.
+	// Переносы разбрасываются по еще одному интервалу ожидания, чтобы все отложенные
+	// notify не проснулись одновременно и не уперлись в лимит снова
+	if wait := c.throttle(ctx, &dto, currentNotify); wait > 0 {
+		scheduledAt := time.Now().Add(wait + rand.N(wait))
+		c.metrics.NotifyThrottled(dto.Channel)
+		c.log.Info().
+			Any("id", dto.ID).
+			Dur("wait", wait).
+			Msgf("Consumer: notify %s throttled by rate limit", dto.ID)
+		if err := c.reschedule(ctx, dto.ID, scheduledAt, dto.RetryCount, currentNotify.LastError); err != nil &&
+			!errors.Is(err, domain.ErrNotifyCanceled) {
+			// notify остается InProcess и вернется в выборку после visibility timeout
+			c.log.Error().Err(err).Any("id", dto.ID).Msg("Consumer: failed to reschedule throttled notify")
+		}
+		return nil
+	}
+
 	c.log.Info().
 		Any("id", dto.ID).
 		Str("Target", dto.Target).
 		Msgf("Consumer: processing notify %s to %s", dto.ID, dto.Target)
 
-	if err := c.Send(ctx, dto); err != nil {
+	attempt := domain.NewAttempt(currentNotify, domain.AttemptSend, c.workerID)
+	start := time.Now()
+	delivery, err := c.send(ctx, n)
+	attempt.Latency = time.Since(start)
+	c.metrics.ObserveSend(dto.Channel, attempt.Latency, err)
+
+	if err != nil {
 		c.log.Error().
 			Err(err).
 			Any("id", dto.ID).
 			Msgf("Consumer: send failed")
 		errStr := err.Error()
 		dto.RetryCount++
-		if dto.RetryCount < c.maxRetries {
-			dto.ScheduledAt = time.Now().Add(time.Duration(dto.RetryCount * dto.RetryCount * int(time.Minute)))
-			_ = c.postgres.UpdateStatus(ctx, dto.ID, domain.StatusPending, &dto.ScheduledAt, dto.RetryCount, &errStr)
+
+		now := time.Now()
+		policy := c.retries.For(currentNotify)
+		if next, ok := policy.Next(dto.RetryCount, currentNotify.CreatedAt, now); ok && !isPermanent(err) {
+			// провайдер просит подождать дольше, чем предлагает политика
+			if after, ok := domain.RetryAfter(err); ok && now.Add(after).After(next) {
+				next = now.Add(after)
+			}
+			dto.ScheduledAt = next
+			c.recordAttempt(ctx, attempt, domain.OutcomeRetry, nil, err)
+			_ = c.updateStatus(ctx, dto.ID, domain.StatusPending, &dto.ScheduledAt, dto.RetryCount, &errStr)
 			return nil
 		}
 
-		_ = c.postgres.UpdateStatus(ctx, dto.ID, domain.StatusFailed, nil, dto.RetryCount, &errStr)
+		// у notify получателя остались запасные маршруты: пробуем следующий вместо Failed
+		if len(currentNotify.Fallbacks) > 0 && c.fallback(ctx, dto.ID, attempt, err) {
+			return nil
+		}
+
+		c.recordAttempt(ctx, attempt, domain.OutcomeFailed, nil, err)
+		c.metrics.NotifyFailed(dto.Channel)
+		_ = c.updateStatus(ctx, dto.ID, domain.StatusFailed, nil, dto.RetryCount, &errStr)
 
 		return nil
 	}
 
-	if err := c.postgres.UpdateStatus(ctx, dto.ID, domain.StatusSent, nil, dto.RetryCount, nil); err != nil {
+	c.recordAttempt(ctx, attempt, domain.OutcomeSuccess, delivery, nil)
+	c.metrics.NotifySent(dto.Channel)
+	c.metrics.ObserveSchedulingLag(dto.Channel, time.Since(currentNotify.ScheduledAt))
+
+	if err := c.updateStatus(ctx, dto.ID, domain.StatusSent, nil, dto.RetryCount, nil); err != nil {
+		if errors.Is(err, domain.ErrNotifyCanceled) {
+			return nil
+		}
 		c.log.Error().Err(err).Any("id", dto.ID).Msg("Consumer: failed to update status to Sent ")
 		return err
 	}
@@ -103,10 +183,198 @@ func (c *NotifyConsumer) Handle(ctx context.Context, payload []byte) error {
 	return nil
 }
 
-func (c *NotifyConsumer) Send(ctx context.Context, dto NotifyWorkerDTO) error {
-	sender, ok := c.senders[dto.Channel]
+func (c *NotifyConsumer) Send(ctx context.Context, dto NotifyWorkerDTO) (*domain.Delivery, error) {
+	return c.send(ctx, toDomain(&dto))
+}
+
+func (c *NotifyConsumer) send(ctx context.Context, n *domain.Notify) (*domain.Delivery, error) {
+	sender, ok := c.senders[n.Channel]
+	if !ok {
+		return nil, fmt.Errorf("unsupported channel: %s", n.Channel)
+	}
+
+	msg, err := c.render(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	n.Message = msg
+
+	return sender.Send(ctx, n)
+}
+
+// fallback переключает notify на следующий маршрут. false - переключаться некуда
+// (маршруты в кеше устарели), notify завершается как обычно
+func (c *NotifyConsumer) fallback(ctx context.Context, id string, attempt *domain.Attempt, sendErr error) bool {
+	errStr := sendErr.Error()
+	n, err := c.postgres.Fallback(ctx, id, &errStr)
+	if errors.Is(err, domain.ErrNoRoute) {
+		return false
+	}
+	if err != nil {
+		// notify остается InProcess и вернется в очередь по visibility timeout
+		c.log.Error().Err(err).Any("id", id).Msg("Consumer: failed to switch notify to fallback route")
+		return true
+	}
+
+	c.recordAttempt(ctx, attempt, domain.OutcomeFallback, nil, sendErr)
+	// канал и адрес сменились целиком, следующее чтение должно прийти в БД
+	if err := c.redis.Delete(ctx, id); err != nil {
+		c.log.Error().Err(err).Any("id", id).Msg("Consumer: failed to drop notify from redis")
+	}
+	c.log.Info().
+		Any("id", id).
+		Str("channel", n.Channel).
+		Msgf("Consumer: notify %s switched from %s to fallback route", id, attempt.Channel)
+	return true
+}
+
+// suppressed проверяет адресат notify по списку подавления. true - notify обработан без
+// отправки: переключен на запасной маршрут, переведен в StatusSuppressed или, если проверить
+// не удалось, оставлен InProcess до возврата в очередь по visibility timeout
+func (c *NotifyConsumer) suppressed(ctx context.Context, n *domain.Notify, current *domain.Notify) bool {
+	if c.suppressions == nil {
+		return false
+	}
+
+	s, err := c.suppressions.FindSuppression(ctx, n)
+	if errors.Is(err, domain.ErrSuppressionNotFound) {
+		return false
+	}
+	if err != nil {
+		c.log.Error().Err(err).Any("id", n.ID).Msg("Consumer: failed to check suppression list")
+		return true
+	}
+
+	c.metrics.NotifySuppressed(n.Channel)
+	c.log.Info().
+		Any("id", n.ID).
+		Str("reason", string(s.Reason)).
+		Msgf("Consumer: notify %s target is suppressed", n.ID)
+
+	suppressErr := fmt.Errorf("%w (%s)", domain.ErrSuppressed, s.Reason)
+	attempt := domain.NewAttempt(current, domain.AttemptSend, c.workerID)
+	if len(current.Fallbacks) > 0 && c.fallback(ctx, n.ID, attempt, suppressErr) {
+		return true
+	}
+
+	errStr := suppressErr.Error()
+	c.recordAttempt(ctx, attempt, domain.OutcomeSuppressed, nil, suppressErr)
+	_ = c.updateStatus(ctx, n.ID, domain.StatusSuppressed, nil, n.RetryCount, &errStr)
+	return true
+}
+
+// throttle берет токены лимитов доставки notify и возвращает, на сколько отложить отправку.
+// При недоступном хранилище лимитов отправка не откладывается
+func (c *NotifyConsumer) throttle(ctx context.Context, dto *NotifyWorkerDTO, current *domain.Notify) time.Duration {
+	n := toDomain(dto)
+	if n.TenantID == "" {
+		// сообщение опубликовано до появления арендатора в очереди
+		n.TenantID = current.TenantID
+	}
+
+	buckets := c.limits.Buckets(n)
+	if len(buckets) == 0 || c.limiter == nil {
+		return 0
+	}
+
+	wait, err := c.limiter.Take(ctx, buckets)
+	if err != nil {
+		c.log.Error().Err(err).Any("id", dto.ID).Msg("Consumer: rate limiter unavailable, sending without limit")
+		return 0
+	}
+	return wait
+}
+
+// updateStatus меняет статус в БД и отражает его в кеше, чтобы API и повторная
+// доставка того же сообщения не прочитали устаревший статус. Для отмененного notify
+// возвращает ErrNotifyCanceled и кеш не трогает
+func (c *NotifyConsumer) updateStatus(
+	ctx context.Context,
+	id string,
+	status domain.Status,
+	scheduledAt *time.Time,
+	retryCount int,
+	lastErr *string,
+) error {
+	if err := c.postgres.UpdateStatus(ctx, id, status, scheduledAt, retryCount, lastErr); err != nil {
+		// notify отменили, пока он был у воркера: сообщение отбрасывается, отмена остается
+		if errors.Is(err, domain.ErrNotifyCanceled) {
+			c.log.Info().Any("id", id).Msgf("Consumer: notify %s canceled while in process, dropping", id)
+		}
+		return err
+	}
+
+	if err := c.cacheMode.SyncStatus(ctx, c.redis, id, status, scheduledAt, retryCount, lastErr); err != nil {
+		c.log.Error().Err(err).Any("id", id).Msg("Consumer: failed to sync status to redis")
+	}
+	return nil
+}
+
+// reschedule откладывает notify без попытки доставки: повторы и история ошибок не меняются,
+// в кеш попадает только новое время
+func (c *NotifyConsumer) reschedule(
+	ctx context.Context,
+	id string,
+	scheduledAt time.Time,
+	retryCount int,
+	lastErr *string,
+) error {
+	if err := c.postgres.Reschedule(ctx, id, scheduledAt); err != nil {
+		if errors.Is(err, domain.ErrNotifyCanceled) {
+			c.log.Info().Any("id", id).Msgf("Consumer: notify %s canceled while in process, dropping", id)
+		}
+		return err
+	}
+
+	if err := c.cacheMode.SyncStatus(ctx, c.redis, id, domain.StatusPending, &scheduledAt, retryCount, lastErr); err != nil {
+		c.log.Error().Err(err).Any("id", id).Msg("Consumer: failed to sync status to redis")
+	}
+	return nil
+}
+
+// recordAttempt пишет попытку в историю. Ошибка записи не влияет на доставку
+func (c *NotifyConsumer) recordAttempt(
+	ctx context.Context,
+	a *domain.Attempt,
+	outcome domain.AttemptOutcome,
+	delivery *domain.Delivery,
+	sendErr error,
+) {
+	a.Outcome = outcome
+	if sendErr != nil {
+		a.Error = sendErr.Error()
+	}
+	if delivery != nil {
+		a.ProviderMessageID = delivery.MessageID
+		a.ProviderResponse = delivery.Response
+	}
+
+	if err := c.postgres.RecordAttempt(ctx, a); err != nil {
+		c.log.Error().Err(err).Any("id", a.NotifyID).Msg("Consumer: failed to record attempt")
+	}
+}
+
+// render рендерит шаблон, на который ссылается payload. Шаблон ищется среди шаблонов
+// арендатора notify. Для payload без ссылки на шаблон возвращает nil.
+func (c *NotifyConsumer) render(ctx context.Context, n *domain.Notify) (*domain.Message, error) {
+	ref, ok := domain.ParseTemplatePayload(n.Payload)
 	if !ok {
-		return fmt.Errorf("unsupported channel: %s", dto.Channel)
+		return nil, nil
 	}
-	return sender.Send(ctx, toDomain(&dto))
+
+	tenantCtx := domain.WithTenant(ctx, domain.TenantFor(ctx, n.TenantID))
+	t, err := c.templates.GetTemplate(tenantCtx, ref.Template, n.Channel, ref.Version)
+	if err != nil {
+		if errors.Is(err, domain.ErrTemplateNotFound) {
+			return nil, fmt.Errorf("%w: template %q for channel %s not found", domain.ErrTemplateRender, ref.Template, n.Channel)
+		}
+		return nil, err
+	}
+
+	return t.Render(ref.Vars)
+}
+
+// isPermanent - ошибки, которые повтор отправки не исправит
+func isPermanent(err error) bool {
+	return errors.Is(err, domain.ErrPermanent) || errors.Is(err, domain.ErrTemplateRender)
 }
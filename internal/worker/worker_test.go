@@ -4,12 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/adexcell/delayed-notifier/config"
+	"github.com/adexcell/delayed-notifier/internal/adapter/metrics"
+	redisadapter "github.com/adexcell/delayed-notifier/internal/adapter/redis"
 	"github.com/adexcell/delayed-notifier/internal/domain"
 	"github.com/adexcell/delayed-notifier/internal/mocks"
 	"github.com/adexcell/delayed-notifier/pkg/log"
+	"github.com/adexcell/delayed-notifier/pkg/redis"
+	"github.com/alicebob/miniredis/v2"
 	"go.uber.org/mock/gomock"
 )
 
@@ -21,6 +28,7 @@ func TestNotifyConsumer_Handle_Success(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
 
 	cfg := config.NotifierConfig{
 		MaxRetries: 3,
@@ -30,7 +38,7 @@ func TestNotifyConsumer_Handle_Success(t *testing.T) {
 		"email": mockSender,
 	}
 
-	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, senders, log.New())
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
 
 	ctx := context.Background()
 	notify := &domain.Notify{
@@ -65,6 +73,12 @@ func TestNotifyConsumer_Handle_Success(t *testing.T) {
 	// Expect: успешная отправка
 	mockSender.EXPECT().
 		Send(ctx, gomock.Any()).
+		Return(nil, nil).
+		Times(1)
+
+	// Expect: запись попытки в историю
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
 		Return(nil).
 		Times(1)
 
@@ -74,6 +88,12 @@ func TestNotifyConsumer_Handle_Success(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
 	// Act
 	err := consumer.Handle(ctx, payload)
 
@@ -90,11 +110,12 @@ func TestNotifyConsumer_Handle_InvalidJSON(t *testing.T) {
 	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
 
 	cfg := config.NotifierConfig{MaxRetries: 3}
 	senders := map[string]domain.Sender{}
 
-	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, senders, log.New())
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
 
 	ctx := context.Background()
 	invalidPayload := []byte("invalid json")
@@ -115,11 +136,12 @@ func TestNotifyConsumer_Handle_NotFound(t *testing.T) {
 	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
 
 	cfg := config.NotifierConfig{MaxRetries: 3}
 	senders := map[string]domain.Sender{}
 
-	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, senders, log.New())
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
 
 	ctx := context.Background()
 	notifyID := "non-existent-id"
@@ -158,11 +180,12 @@ func TestNotifyConsumer_Handle_AlreadySent(t *testing.T) {
 	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
 
 	cfg := config.NotifierConfig{MaxRetries: 3}
 	senders := map[string]domain.Sender{}
 
-	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, senders, log.New())
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
 
 	ctx := context.Background()
 	notify := &domain.Notify{
@@ -201,6 +224,7 @@ func TestNotifyConsumer_Handle_SendFailure_Retry(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
 
 	cfg := config.NotifierConfig{
 		MaxRetries: 3,
@@ -210,7 +234,7 @@ func TestNotifyConsumer_Handle_SendFailure_Retry(t *testing.T) {
 		"email": mockSender,
 	}
 
-	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, senders, log.New())
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
 
 	ctx := context.Background()
 	notify := &domain.Notify{
@@ -239,7 +263,13 @@ func TestNotifyConsumer_Handle_SendFailure_Retry(t *testing.T) {
 	// Expect: ошибка при отправке
 	mockSender.EXPECT().
 		Send(ctx, gomock.Any()).
-		Return(errors.New("send failed")).
+		Return(nil, errors.New("send failed")).
+		Times(1)
+
+	// Expect: запись попытки в историю
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
 		Times(1)
 
 	// Expect: обновление статуса на Pending с увеличением retry count
@@ -248,6 +278,12 @@ func TestNotifyConsumer_Handle_SendFailure_Retry(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
 	// Act
 	err := consumer.Handle(ctx, payload)
 
@@ -257,6 +293,331 @@ func TestNotifyConsumer_Handle_SendFailure_Retry(t *testing.T) {
 	}
 }
 
+func TestNotifyConsumer_Handle_SendFailure_Permanent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{
+		MaxRetries: 3,
+	}
+
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:         "test-id-123",
+		Target:     "test@example.com",
+		Channel:    "email",
+		Payload:    []byte("Test message"),
+		Status:     domain.StatusPending,
+		RetryCount: 0,
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:         notify.ID,
+		Target:     notify.Target,
+		Channel:    notify.Channel,
+		Payload:    notify.Payload,
+		RetryCount: 0,
+	})
+
+	// Expect: получение из кеша
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Expect: постоянная ошибка при отправке (например, 4xx от webhook)
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(nil, fmt.Errorf("%w: webhook returned status 404", domain.ErrPermanent)).
+		Times(1)
+
+	// Expect: запись попытки в историю
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: сразу Failed, несмотря на оставшиеся попытки
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, nil, 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, nil, 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_SendFailure_Fallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{
+		MaxRetries: 3,
+	}
+
+	senders := map[string]domain.Sender{
+		"telegram": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:          "test-id-123",
+		Target:      "42",
+		Channel:     "telegram",
+		Payload:     []byte("Test message"),
+		Status:      domain.StatusInProcess,
+		RecipientID: "recipient-1",
+		Fallbacks:   []domain.Route{{Channel: "email", Target: "test@example.com"}},
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Expect: постоянная ошибка в первом канале
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(nil, fmt.Errorf("%w: chat not found", domain.ErrPermanent)).
+		Times(1)
+
+	// Expect: notify переключается на email вместо Failed
+	mockPostgres.EXPECT().
+		Fallback(ctx, notify.ID, gomock.Any()).
+		Return(&domain.Notify{ID: notify.ID, Channel: "email", Target: "test@example.com", Status: domain.StatusPending}, nil).
+		Times(1)
+
+	// Expect: попытка записана с исходом fallback по каналу telegram
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Attempt) error {
+			if a.Outcome != domain.OutcomeFallback || a.Channel != "telegram" {
+				t.Errorf("expected fallback attempt on telegram, got %s on %s", a.Outcome, a.Channel)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// Expect: закешированный маршрут устарел
+	mockRedis.EXPECT().
+		Delete(ctx, notify.ID).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_Suppressed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	mockSuppressions := mocks.NewMockSuppressionPostgres(ctrl)
+	mockMetrics := mocks.NewMockMetrics(ctrl)
+
+	cfg := config.NotifierConfig{
+		MaxRetries: 3,
+	}
+
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, mockSuppressions, senders, nil, mockMetrics, log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:         "test-id-123",
+		Target:     "user@example.com",
+		Channel:    "email",
+		Payload:    []byte("Test message"),
+		Status:     domain.StatusInProcess,
+		RetryCount: 1,
+		TenantID:   "team-a",
+		Category:   "marketing",
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:         notify.ID,
+		Target:     notify.Target,
+		Channel:    notify.Channel,
+		Payload:    notify.Payload,
+		RetryCount: notify.RetryCount,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Expect: проверка идет с арендатором и категорией notify, которых нет в сообщении
+	mockSuppressions.EXPECT().
+		FindSuppression(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, n *domain.Notify) (*domain.Suppression, error) {
+			if n.TenantID != "team-a" || n.Category != "marketing" || n.Target != notify.Target {
+				t.Errorf("unexpected notify checked: %+v", n)
+			}
+			return &domain.Suppression{ID: "s-1", Reason: domain.ReasonUnsubscribe}, nil
+		}).
+		Times(1)
+
+	// Expect: без отправки и без запасных маршрутов - StatusSuppressed
+	mockSender.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+	mockMetrics.EXPECT().NotifySuppressed("email").Times(1)
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Attempt) error {
+			if a.Outcome != domain.OutcomeSuppressed || !strings.Contains(a.Error, "unsubscribe") {
+				t.Errorf("expected suppressed attempt with reason, got %s: %s", a.Outcome, a.Error)
+			}
+			return nil
+		}).
+		Times(1)
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSuppressed, nil, 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSuppressed, nil, 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_Suppressed_Fallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	mockSuppressions := mocks.NewMockSuppressionPostgres(ctrl)
+
+	cfg := config.NotifierConfig{
+		MaxRetries: 3,
+	}
+
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, mockSuppressions, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:          "test-id-123",
+		Target:      "user@example.com",
+		Channel:     "email",
+		Payload:     []byte("Test message"),
+		Status:      domain.StatusInProcess,
+		RecipientID: "recipient-1",
+		Fallbacks:   []domain.Route{{Channel: "telegram", Target: "42"}},
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	mockSuppressions.EXPECT().
+		FindSuppression(ctx, gomock.Any()).
+		Return(&domain.Suppression{ID: "s-1", Reason: domain.ReasonBounce}, nil).
+		Times(1)
+
+	// Expect: получатель доступен в другом канале - notify переключается, а не подавляется
+	mockSender.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+	mockPostgres.EXPECT().
+		Fallback(ctx, notify.ID, gomock.Any()).
+		Return(&domain.Notify{ID: notify.ID, Channel: "telegram", Target: "42", Status: domain.StatusPending}, nil).
+		Times(1)
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Attempt) error {
+			if a.Outcome != domain.OutcomeFallback || !strings.Contains(a.Error, "suppressed") {
+				t.Errorf("expected fallback attempt after suppression, got %s: %s", a.Outcome, a.Error)
+			}
+			return nil
+		}).
+		Times(1)
+	mockRedis.EXPECT().
+		Delete(ctx, notify.ID).
+		Return(nil).
+		Times(1)
+	mockPostgres.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
 func TestNotifyConsumer_Handle_SendFailure_MaxRetries(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -265,6 +626,7 @@ func TestNotifyConsumer_Handle_SendFailure_MaxRetries(t *testing.T) {
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
 	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
 
 	cfg := config.NotifierConfig{
 		MaxRetries: 3,
@@ -274,7 +636,7 @@ func TestNotifyConsumer_Handle_SendFailure_MaxRetries(t *testing.T) {
 		"email": mockSender,
 	}
 
-	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, senders, log.New())
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
 
 	ctx := context.Background()
 	notify := &domain.Notify{
@@ -303,7 +665,13 @@ func TestNotifyConsumer_Handle_SendFailure_MaxRetries(t *testing.T) {
 	// Expect: ошибка при отправке
 	mockSender.EXPECT().
 		Send(ctx, gomock.Any()).
-		Return(errors.New("send failed")).
+		Return(nil, errors.New("send failed")).
+		Times(1)
+
+	// Expect: запись попытки в историю
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
 		Times(1)
 
 	// Expect: обновление статуса на Failed (достигнут лимит)
@@ -312,6 +680,12 @@ func TestNotifyConsumer_Handle_SendFailure_MaxRetries(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, nil, 3, gomock.Any()).
+		Return(nil).
+		Times(1)
+
 	// Act
 	err := consumer.Handle(ctx, payload)
 
@@ -328,13 +702,14 @@ func TestNotifyConsumer_Send_UnsupportedChannel(t *testing.T) {
 	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
 	mockRedis := mocks.NewMockNotifyRedis(ctrl)
 	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
 
 	cfg := config.NotifierConfig{MaxRetries: 3}
 	senders := map[string]domain.Sender{
 		"email": mocks.NewMockSender(ctrl),
 	}
 
-	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, senders, log.New())
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
 
 	ctx := context.Background()
 	dto := NotifyWorkerDTO{
@@ -344,7 +719,7 @@ func TestNotifyConsumer_Send_UnsupportedChannel(t *testing.T) {
 	}
 
 	// Act
-	err := consumer.Send(ctx, dto)
+	_, err := consumer.Send(ctx, dto)
 
 	// Assert
 	if err == nil {
@@ -357,3 +732,985 @@ func TestNotifyConsumer_Send_UnsupportedChannel(t *testing.T) {
 		}
 	}
 }
+
+func TestNotifyConsumer_Handle_Canceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 3}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:      "test-id-123",
+		Status:  domain.StatusCanceled, // Отменено, пока сообщение лежало в очереди
+		Target:  "test@example.com",
+		Channel: "email",
+	}
+
+	// В сообщении из очереди статус еще InProcess
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Status:  domain.StatusInProcess,
+	})
+
+	// Expect: кеш инвалидирован при отмене
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(nil, domain.ErrNotFound).
+		Times(1)
+
+	// Expect: актуальный статус из БД
+	mockPostgres.EXPECT().
+		GetNotifyByID(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert - отправка и обновление статуса не вызываются
+	if err != nil {
+		t.Errorf("expected nil error for canceled notify, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_CanceledWhileSending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 3}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:      "test-id-123",
+		Status:  domain.StatusInProcess,
+		Target:  "test@example.com",
+		Channel: "email",
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Status:  domain.StatusInProcess,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(nil, errors.New("send failed")).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: notify отменили во время отправки, повтор не записывается
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 1, gomock.Any()).
+		Return(domain.ErrNotifyCanceled).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert - сообщение отброшено, кеш не обновляется
+	if err != nil {
+		t.Errorf("expected nil error for notify canceled while sending, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_Template_Rendered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 3}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:       "test-id-123",
+		Target:   "test@example.com",
+		Channel:  "email",
+		Payload:  []byte(`{"template": "welcome", "vars": {"name": "Ivan"}}`),
+		Status:   domain.StatusPending,
+		TenantID: "team-a",
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Expect: последняя версия шаблона арендатора notify для его канала
+	mockTemplates.EXPECT().
+		GetTemplate(gomock.Any(), "welcome", "email", 0).
+		DoAndReturn(func(ctx context.Context, _, _ string, _ int) (*domain.Template, error) {
+			if tenant, ok := domain.TenantFrom(ctx); !ok || tenant != "team-a" {
+				t.Errorf("expected template lookup scoped to team-a, got %q", tenant)
+			}
+			return &domain.Template{
+				Name:    "welcome",
+				Channel: "email",
+				Version: 2,
+				Subject: "Hello, {{.name}}",
+				HTML:    "<p>Hi {{.name}}</p>",
+			}, nil
+		}).
+		Times(1)
+
+	// Expect: отправитель получает отрендеренное сообщение
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, n *domain.Notify) (*domain.Delivery, error) {
+			if n.Message == nil {
+				t.Fatal("expected rendered message")
+			}
+			if n.Message.Subject != "Hello, Ivan" || n.Message.HTML != "<p>Hi Ivan</p>" {
+				t.Errorf("unexpected rendered message: %+v", n.Message)
+			}
+			return nil, nil
+		}).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_Template_RenderErrorIsPermanent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 3}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:      "test-id-123",
+		Target:  "test@example.com",
+		Channel: "email",
+		Payload: []byte(`{"template": "welcome", "version": 1}`),
+		Status:  domain.StatusPending,
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Expect: в payload нет переменной name
+	mockTemplates.EXPECT().
+		GetTemplate(gomock.Any(), "welcome", "email", 1).
+		Return(&domain.Template{Name: "welcome", Channel: "email", Version: 1, Text: "Hi {{.name}}"}, nil).
+		Times(1)
+
+	// Expect: неудачная попытка попадает в историю
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: сразу Failed без повторов, причина в last_error; отправитель не вызывается
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, nil, 1, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ domain.Status, _ *time.Time, _ int, lastErr *string) error {
+			if lastErr == nil || !strings.Contains(*lastErr, domain.ErrTemplateRender.Error()) {
+				t.Errorf("expected render error in last_error, got %v", lastErr)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, nil, 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_SendFailure_NotifyPolicyMaxAge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 10}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	// собственная политика notify: попытки остались, но notify слишком старый
+	notify := &domain.Notify{
+		ID:        "test-id-123",
+		Target:    "test@example.com",
+		Channel:   "email",
+		Payload:   []byte("Test message"),
+		Status:    domain.StatusPending,
+		CreatedAt: time.Now().Add(-50 * time.Minute),
+		RetryPolicy: &domain.RetryPolicy{
+			Kind:        domain.RetryFixed,
+			Delay:       15 * time.Minute,
+			MaxAttempts: 10,
+			MaxAge:      time.Hour,
+		},
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(nil, errors.New("send failed")).
+		Times(1)
+
+	// Expect: запись попытки в историю
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: следующая попытка вышла бы за max_age -> Failed
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, nil, 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusFailed, nil, 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_SendFailure_NotifyPolicyWithoutMaxAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 3}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	// собственная политика notify без max_attempts: число попыток берется из notifier.max_retries
+	notify := &domain.Notify{
+		ID:          "test-id-123",
+		Target:      "test@example.com",
+		Channel:     "email",
+		Payload:     []byte("Test message"),
+		Status:      domain.StatusPending,
+		RetryPolicy: &domain.RetryPolicy{Kind: domain.RetryFixed, Delay: 10 * time.Minute},
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(nil, errors.New("send failed")).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: повтор через задержку из политики notify, а не Failed
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 1, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ domain.Status, scheduledAt *time.Time, _ int, _ *string) error {
+			delay := time.Until(*scheduledAt)
+			if delay < 9*time.Minute || delay > 10*time.Minute {
+				t.Errorf("expected retry in ~10m, got %v", delay)
+			}
+			return nil
+		}).
+		Times(1)
+
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_SendFailure_RetryAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{
+		MaxRetries: 5,
+		Retry:      domain.RetryPolicy{Kind: domain.RetryFixed, Delay: time.Second},
+	}
+	senders := map[string]domain.Sender{
+		"telegram": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:      "test-id-123",
+		Target:  "42",
+		Channel: "telegram",
+		Payload: []byte("Test message"),
+		Status:  domain.StatusPending,
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Expect: Telegram просит подождать 30 секунд
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(nil, &domain.RetryAfterError{After: 30 * time.Second, Err: errors.New("too many requests")}).
+		Times(1)
+
+	// Expect: запись попытки в историю
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Expect: повтор не раньше retry_after, хотя политика предлагает через секунду
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 1, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ domain.Status, scheduledAt *time.Time, _ int, _ *string) error {
+			if delay := time.Until(*scheduledAt); delay < 29*time.Second {
+				t.Errorf("expected retry after ~30s, got %v", delay)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 1, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_Throttled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	mockLimiter := mocks.NewMockRateLimiter(ctrl)
+	mockMetrics := mocks.NewMockMetrics(ctrl)
+
+	cfg := config.NotifierConfig{
+		MaxRetries: 5,
+		RateLimit: domain.RateLimits{
+			Channels: map[string]domain.RateLimit{"telegram": {Limit: 30, Period: time.Second}},
+			Tenant:   domain.RateLimit{Limit: 100, Period: time.Minute},
+		},
+	}
+	senders := map[string]domain.Sender{
+		"telegram": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, mockLimiter, mockMetrics, log.New())
+
+	ctx := context.Background()
+	lastErr := "previous error"
+	notify := &domain.Notify{
+		ID:         "test-id-123",
+		Target:     "42",
+		Channel:    "telegram",
+		Payload:    []byte("Test message"),
+		Status:     domain.StatusPending,
+		RetryCount: 2,
+		LastError:  &lastErr,
+		TenantID:   "team-a",
+	}
+
+	// сообщение опубликовано без арендатора, он берется из notify
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:         notify.ID,
+		Target:     notify.Target,
+		Channel:    notify.Channel,
+		Payload:    notify.Payload,
+		RetryCount: notify.RetryCount,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Expect: токен берется сразу из бакетов канала и арендатора, лимит исчерпан
+	mockLimiter.EXPECT().
+		Take(ctx, []domain.RateBucket{
+			{Key: "channel:telegram", Limit: cfg.RateLimit.Channels["telegram"]},
+			{Key: "tenant:team-a", Limit: cfg.RateLimit.Tenant},
+		}).
+		Return(2*time.Second, nil).
+		Times(1)
+
+	// Expect: отправка отложена, попытка не тратится и не пишется в историю
+	mockMetrics.EXPECT().NotifyThrottled("telegram").Times(1)
+	mockSender.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+	mockPostgres.EXPECT().RecordAttempt(gomock.Any(), gomock.Any()).Times(0)
+
+	// Expect: перенос без записи прошлой ошибки в историю повторно
+	mockPostgres.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockPostgres.EXPECT().
+		Reschedule(ctx, notify.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, scheduledAt time.Time) error {
+			// ожидание 2s плюс разброс до еще одного ожидания
+			if delay := time.Until(scheduledAt); delay < time.Second || delay > 4*time.Second {
+				t.Errorf("expected deferral by 2-4s, got %v", delay)
+			}
+			return nil
+		}).
+		Times(1)
+
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusPending, gomock.Any(), 2, &lastErr).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_LimiterUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	mockLimiter := mocks.NewMockRateLimiter(ctrl)
+
+	cfg := config.NotifierConfig{
+		MaxRetries: 5,
+		RateLimit: domain.RateLimits{
+			Target: domain.RateLimit{Limit: 1, Period: time.Second},
+		},
+	}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, mockLimiter, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:      "test-id-123",
+		Target:  "test@example.com",
+		Channel: "email",
+		Payload: []byte("Test message"),
+		Status:  domain.StatusPending,
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	// Expect: Redis недоступен
+	mockLimiter.EXPECT().
+		Take(ctx, gomock.Any()).
+		Return(time.Duration(0), errors.New("connection refused")).
+		Times(1)
+
+	// Expect: отправка все равно выполняется
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(nil, nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_RecordsAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 3, WorkerID: "worker-1"}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:      "test-id-123",
+		Target:  "test@example.com",
+		Channel: "email",
+		Payload: []byte("Test message"),
+		Status:  domain.StatusPending,
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(&domain.Delivery{MessageID: "<msg-1@example.com>", Response: "250 OK"}, nil).
+		Times(1)
+
+	// Expect: в историю пишется успешная попытка с ответом провайдера
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *domain.Attempt) error {
+			if a.NotifyID != notify.ID || a.Kind != domain.AttemptSend || a.Channel != "email" {
+				t.Errorf("unexpected attempt: %+v", a)
+			}
+			if a.WorkerID != "worker-1" {
+				t.Errorf("expected worker-1, got %q", a.WorkerID)
+			}
+			if a.Outcome != domain.OutcomeSuccess || a.Error != "" {
+				t.Errorf("expected success outcome, got %s (%q)", a.Outcome, a.Error)
+			}
+			if a.ProviderMessageID != "<msg-1@example.com>" || a.ProviderResponse != "250 OK" {
+				t.Errorf("unexpected provider fields: %+v", a)
+			}
+			return nil
+		}).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_RecordAttemptErrorIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 3}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:      "test-id-123",
+		Target:  "test@example.com",
+		Channel: "email",
+		Payload: []byte("Test message"),
+		Status:  domain.StatusPending,
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().
+		Get(ctx, notify.ID).
+		Return(notify, nil).
+		Times(1)
+
+	mockSender.EXPECT().
+		Send(ctx, gomock.Any()).
+		Return(nil, nil).
+		Times(1)
+
+	// Expect: ошибка записи истории не мешает перевести notify в Sent
+	mockPostgres.EXPECT().
+		RecordAttempt(ctx, gomock.Any()).
+		Return(errors.New("db error")).
+		Times(1)
+
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockRedis := mocks.NewMockNotifyRedis(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+	mockMetrics := mocks.NewMockMetrics(ctrl)
+
+	cfg := config.NotifierConfig{MaxRetries: 3}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, mockRedis, mockTemplates, nil, senders, nil, mockMetrics, log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:          "test-id-123",
+		Target:      "test@example.com",
+		Channel:     "email",
+		Payload:     []byte("Test message"),
+		Status:      domain.StatusInProcess,
+		ScheduledAt: time.Now().Add(-time.Minute),
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	mockRedis.EXPECT().Get(ctx, notify.ID).Return(notify, nil).Times(1)
+	mockSender.EXPECT().Send(ctx, gomock.Any()).Return(nil, nil).Times(1)
+	mockPostgres.EXPECT().RecordAttempt(ctx, gomock.Any()).Return(nil).Times(1)
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Expect: новый статус записан в кеш
+	mockRedis.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Expect: латентность отправки, счетчик Sent и опоздание относительно scheduled_at
+	mockMetrics.EXPECT().ObserveSend("email", gomock.Any(), nil).Times(1)
+	mockMetrics.EXPECT().NotifySent("email").Times(1)
+	mockMetrics.EXPECT().
+		ObserveSchedulingLag("email", gomock.Any()).
+		Do(func(_ string, lag time.Duration) {
+			if lag < time.Minute {
+				t.Errorf("expected lag of at least 1m, got %v", lag)
+			}
+		}).
+		Times(1)
+
+	// Act
+	err := consumer.Handle(ctx, payload)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestNotifyConsumer_Handle_NoStaleCacheAfterSent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPostgres := mocks.NewMockNotifyPostgres(ctrl)
+	mockQueue := mocks.NewMockQueueProvider(ctrl)
+	mockSender := mocks.NewMockSender(ctrl)
+	mockTemplates := mocks.NewMockTemplatePostgres(ctrl)
+
+	// настоящий адаптер поверх miniredis
+	srv := miniredis.RunT(t)
+	cache := redisadapter.New(redis.Config{Addr: srv.Addr(), TTL: time.Hour})
+	defer cache.Close()
+
+	cfg := config.NotifierConfig{MaxRetries: 3, CacheMode: domain.CacheWriteThrough}
+	senders := map[string]domain.Sender{
+		"email": mockSender,
+	}
+
+	consumer := NewNotifyConsumer(cfg, mockPostgres, mockQueue, cache, mockTemplates, nil, senders, nil, metrics.NewNop(), log.New())
+
+	ctx := context.Background()
+	notify := &domain.Notify{
+		ID:      "test-id-123",
+		Target:  "test@example.com",
+		Channel: "email",
+		Payload: []byte("Test message"),
+		Status:  domain.StatusInProcess,
+	}
+	if err := cache.SetWithExpiration(ctx, notify); err != nil {
+		t.Fatalf("failed to cache notify: %v", err)
+	}
+
+	payload, _ := json.Marshal(NotifyWorkerDTO{
+		ID:      notify.ID,
+		Target:  notify.Target,
+		Channel: notify.Channel,
+		Payload: notify.Payload,
+	})
+
+	// Expect: отправка ровно один раз, хотя сообщение доставлено дважды
+	mockSender.EXPECT().Send(ctx, gomock.Any()).Return(nil, nil).Times(1)
+	mockPostgres.EXPECT().RecordAttempt(ctx, gomock.Any()).Return(nil).Times(1)
+	mockPostgres.EXPECT().
+		UpdateStatus(ctx, notify.ID, domain.StatusSent, nil, 0, nil).
+		Return(nil).
+		Times(1)
+
+	// Act
+	if err := consumer.Handle(ctx, payload); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// повторная доставка того же сообщения
+	if err := consumer.Handle(ctx, payload); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Assert: кеш отдает актуальный статус
+	cached, err := cache.Get(ctx, notify.ID)
+	if err != nil {
+		t.Fatalf("expected cached notify, got %v", err)
+	}
+	if cached.Status != domain.StatusSent {
+		t.Errorf("expected cached status %v, got %v", domain.StatusSent, cached.Status)
+	}
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notify_idempotency_key;
+
+ALTER TABLE notify
+    DROP COLUMN IF EXISTS request_hash,
+    DROP COLUMN IF EXISTS idempotency_key;
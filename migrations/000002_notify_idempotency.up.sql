@@ -0,0 +1,6 @@
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS idempotency_key varchar(255),
+    ADD COLUMN IF NOT EXISTS request_hash varchar(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notify_idempotency_key ON notify(idempotency_key)
+where idempotency_key IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_notify_target_created_at;
+DROP INDEX IF EXISTS idx_notify_channel_created_at;
+DROP INDEX IF EXISTS idx_notify_status_created_at;
+DROP INDEX IF EXISTS idx_notify_scheduled_at_id;
+DROP INDEX IF EXISTS idx_notify_created_at_id;
+
+CREATE INDEX IF NOT EXISTS idx_notify_created_at ON notify(created_at DESC);
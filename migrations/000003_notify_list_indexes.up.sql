@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_notify_created_at;
+
+-- keyset-пагинация по (sort_column, notify_id)
+CREATE INDEX IF NOT EXISTS idx_notify_created_at_id ON notify(created_at DESC, notify_id DESC);
+CREATE INDEX IF NOT EXISTS idx_notify_scheduled_at_id ON notify(scheduled_at, notify_id);
+
+-- фильтры списка
+CREATE INDEX IF NOT EXISTS idx_notify_status_created_at ON notify(status, created_at DESC, notify_id DESC);
+CREATE INDEX IF NOT EXISTS idx_notify_channel_created_at ON notify(channel, created_at DESC, notify_id DESC);
+CREATE INDEX IF NOT EXISTS idx_notify_target_created_at ON notify(target, created_at DESC, notify_id DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notify_schedule_id;
+
+ALTER TABLE notify DROP COLUMN IF EXISTS schedule_id;
+
+DROP TABLE IF EXISTS schedule;
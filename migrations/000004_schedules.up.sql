@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS schedule (
+    schedule_id UUID primary key,
+    payload JSONB not null,
+    target varchar(255) not null,
+    channel varchar(100) not null,
+    cron_expr varchar(255),
+    interval_seconds bigint,
+    timezone varchar(64) not null default 'UTC',
+    start_at timestamp with time zone not null,
+    end_at timestamp with time zone,
+    next_run_at timestamp with time zone,
+    status int not null default 0,
+    created_at timestamp with time zone default now(),
+    updated_at timestamp with time zone,
+    CHECK ((cron_expr IS NULL) <> (interval_seconds IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_status_next_run ON schedule(status, next_run_at)
+where status = 0;
+
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS schedule_id UUID REFERENCES schedule(schedule_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notify_schedule_id ON notify(schedule_id)
+where schedule_id IS NOT NULL;
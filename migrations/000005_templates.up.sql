@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS template (
+    template_id UUID primary key,
+    name varchar(255) not null,
+    channel varchar(100) not null,
+    version int not null,
+    subject text not null default '',
+    body_text text not null default '',
+    body_html text not null default '',
+    created_at timestamp with time zone default now(),
+    UNIQUE (name, channel, version)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notify_failed_created;
+
+ALTER TABLE notify DROP COLUMN IF EXISTS error_history;
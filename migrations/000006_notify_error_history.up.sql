@@ -0,0 +1,5 @@
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS error_history JSONB not null default '[]'::jsonb;
+
+CREATE INDEX IF NOT EXISTS idx_notify_failed_created ON notify(created_at DESC, notify_id DESC)
+where status = 3;
@@ -0,0 +1,2 @@
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS retry_policy JSONB;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS notify_attempts (
+    attempt_id UUID primary key,
+    notify_id UUID not null REFERENCES notify(notify_id) ON DELETE CASCADE,
+    kind varchar(16) not null,
+    worker_id varchar(255) not null,
+    channel varchar(100) not null,
+    outcome varchar(16) not null,
+    error text,
+    provider_message_id varchar(255),
+    provider_response text,
+    latency_ms bigint not null default 0,
+    created_at timestamp with time zone default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notify_attempts_notify ON notify_attempts(notify_id, created_at);
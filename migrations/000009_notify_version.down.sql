@@ -0,0 +1 @@
+ALTER TABLE notify DROP COLUMN IF EXISTS version;
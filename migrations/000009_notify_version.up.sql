@@ -0,0 +1,3 @@
+-- версия строки для оптимистичной блокировки: растет при каждом изменении notify
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
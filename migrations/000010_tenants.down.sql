@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_schedule_tenant_created_at;
+DROP INDEX IF EXISTS idx_notify_tenant_created_at;
+DROP INDEX IF EXISTS idx_notify_tenant_idempotency_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notify_idempotency_key ON notify(idempotency_key)
+where idempotency_key IS NOT NULL;
+
+ALTER TABLE schedule DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE notify DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,13 @@
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS tenant_id varchar(64) NOT NULL DEFAULT 'default';
+ALTER TABLE schedule
+    ADD COLUMN IF NOT EXISTS tenant_id varchar(64) NOT NULL DEFAULT 'default';
+
+-- ключи идемпотентности уникальны в пределах арендатора
+DROP INDEX IF EXISTS idx_notify_idempotency_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notify_tenant_idempotency_key ON notify(tenant_id, idempotency_key)
+where idempotency_key IS NOT NULL;
+
+-- список notify арендатора
+CREATE INDEX IF NOT EXISTS idx_notify_tenant_created_at ON notify(tenant_id, created_at DESC, notify_id DESC);
+CREATE INDEX IF NOT EXISTS idx_schedule_tenant_created_at ON schedule(tenant_id, created_at DESC);
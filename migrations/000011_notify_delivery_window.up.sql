@@ -0,0 +1,2 @@
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS delivery_window JSONB;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_notify_recipient_id;
+
+ALTER TABLE notify
+    DROP COLUMN IF EXISTS fallbacks,
+    DROP COLUMN IF EXISTS recipient_id;
+
+DROP TABLE IF EXISTS recipient;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS recipient (
+    recipient_id UUID primary key,
+    tenant_id varchar(64) not null default 'default',
+    name varchar(255) not null default '',
+    contacts JSONB not null,
+    channels JSONB not null default '[]'::jsonb,
+    opt_outs JSONB not null default '[]'::jsonb,
+    delivery_window JSONB,
+    created_at timestamp with time zone default now(),
+    updated_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipient_tenant_created_at ON recipient(tenant_id, created_at DESC);
+
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS recipient_id UUID REFERENCES recipient(recipient_id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS fallbacks JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_notify_recipient_id ON notify(recipient_id)
+where recipient_id IS NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE notify
+    DROP COLUMN IF EXISTS category;
+
+DROP TABLE IF EXISTS suppression;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS suppression (
+    suppression_id UUID primary key,
+    -- NULL - запись действует для всех арендаторов
+    tenant_id varchar(64),
+    channel varchar(100) not null,
+    target varchar(255) not null,
+    -- пустая строка - все категории
+    category varchar(64) not null default '',
+    reason varchar(32) not null,
+    created_at timestamp with time zone default now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_suppression_scope
+ON suppression(channel, target, COALESCE(tenant_id, ''), category);
+
+CREATE INDEX IF NOT EXISTS idx_suppression_tenant_created_at ON suppression(tenant_id, created_at DESC);
+
+ALTER TABLE notify
+    ADD COLUMN IF NOT EXISTS category varchar(64) not null default '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS ux_template_tenant_name_channel_version;
+ALTER TABLE template ADD CONSTRAINT template_name_channel_version_key UNIQUE (name, channel, version);
+
+ALTER TABLE template DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,7 @@
+ALTER TABLE template
+    ADD COLUMN IF NOT EXISTS tenant_id varchar(64) NOT NULL DEFAULT 'default';
+
+-- имена и версии шаблонов уникальны в пределах арендатора
+ALTER TABLE template DROP CONSTRAINT IF EXISTS template_name_channel_version_key;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_template_tenant_name_channel_version
+ON template(tenant_id, name, channel, version);
@@ -0,0 +1,15 @@
+// Package kafka содержит настройки Kafka для обертки над wbf/kafka.
+package kafka
+
+import "github.com/wb-go/wbf/retry"
+
+type Config struct {
+	Brokers []string `mapstructure:"brokers"`
+	Topic   string   `mapstructure:"topic"`
+	GroupID string   `mapstructure:"group_id"`
+	// Partitions и ReplicationFactor используются, если топик создается при Init
+	Partitions        int            `mapstructure:"partitions"`
+	ReplicationFactor int            `mapstructure:"replication_factor"`
+	ProducingStrat    retry.Strategy `mapstructure:"producing_strat"`
+	ConsumingStrat    retry.Strategy `mapstructure:"consuming_strat"`
+}
@@ -0,0 +1,34 @@
+// Package nats подключается к NATS с JetStream.
+package nats
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type Config struct {
+	URL            string        `mapstructure:"url"`
+	ConnectionName string        `mapstructure:"connection_name"`
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+	Stream         string        `mapstructure:"stream"`
+	Subject        string        `mapstructure:"subject"`
+	Durable        string        `mapstructure:"durable"`
+	// AckWait - сколько сервер ждет подтверждения, прежде чем доставить сообщение повторно
+	AckWait time.Duration `mapstructure:"ack_wait"`
+	// MaxDeliver - предел доставок одного сообщения, включая повторы после Nak
+	MaxDeliver int `mapstructure:"max_deliver"`
+	Workers    int `mapstructure:"workers"`
+}
+
+func Connect(cfg Config) (*nats.Conn, error) {
+	opts := []nats.Option{
+		nats.Name(cfg.ConnectionName),
+		nats.MaxReconnects(-1),
+	}
+	if cfg.ConnectTimeout > 0 {
+		opts = append(opts, nats.Timeout(cfg.ConnectTimeout))
+	}
+
+	return nats.Connect(cfg.URL, opts...)
+}
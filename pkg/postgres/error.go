@@ -6,9 +6,17 @@ import (
 	"github.com/lib/pq"
 )
 
+const uniqueViolation = "23505"
+
 func PostgresErr(err error) error {
-	if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+	if IsUniqueViolation(err) {
 		return errors.New("this alias is already taken")
 	}
 	return err
 }
+
+// IsUniqueViolation проверяет, что ошибка вызвана нарушением уникального ограничения.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pq.Error
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -7,6 +7,16 @@ import (
 	"github.com/wb-go/wbf/retry"
 )
 
+// Способы отложенной доставки сообщений
+const (
+	// DelayExchange - exchange типа x-delayed-message, задержка в заголовке x-delay.
+	// Требует плагин rabbitmq_delayed_message_exchange
+	DelayExchange = "delayed_exchange"
+	// DelayWaitQueue - очереди ожидания с TTL, из которых истекшие сообщения
+	// через dead-letter exchange попадают в рабочую очередь
+	DelayWaitQueue = "wait_queue"
+)
+
 type Config struct {
 	URL            string         `mapstructure:"url"`
 	ConnectionName string         `mapstructure:"connection_name"`
@@ -15,6 +25,11 @@ type Config struct {
 	ReconnectStrat retry.Strategy `mapstructure:"reconnect_strat"`
 	ProducingStrat retry.Strategy `mapstructure:"producing_strat"`
 	ConsumingStrat retry.Strategy `mapstructure:"consuming_strat"`
+	DelayStrategy  string         `mapstructure:"delay_strategy"`
+	// Workers - число обработчиков, PrefetchCount - сколько неподтвержденных сообщений
+	// брокер отдает консьюмеру
+	Workers       int `mapstructure:"workers"`
+	PrefetchCount int `mapstructure:"prefetch_count"`
 }
 
 func NewClient(cfg Config) (*rabbitmq.RabbitClient, error) {
@@ -22,3 +22,16 @@ type Config struct {
 func New(cfg Config) *RDB {
 	return redis.New(cfg.Addr, cfg.Password, cfg.DB)
 }
+
+// KeepTTL - expiration для SET, сохраняющий текущий TTL ключа
+const KeepTTL = originalRedis.KeepTTL
+
+const TxFailedErr = originalRedis.TxFailedErr
+
+type Tx = originalRedis.Tx
+type Pipeliner = originalRedis.Pipeliner
+
+type Script = originalRedis.Script
+
+// NewScript - Lua-скрипт, выполняемый через EVALSHA с откатом на EVAL
+var NewScript = originalRedis.NewScript
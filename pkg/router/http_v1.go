@@ -18,5 +18,9 @@ type Config struct {
 }
 
 func New(cfg Config) *Router {
-	return ginext.New(cfg.GinMode)
+	r := ginext.New(cfg.GinMode)
+	// Значения, которые middleware кладут в контекст запроса (например, арендатор),
+	// должны быть видны хендлерам, передающим *Context как context.Context
+	r.ContextWithFallback = true
+	return r
 }
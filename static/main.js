@@ -3,7 +3,8 @@ const statusMap = {
     1: 'В обработке',
     2: 'Отправлено',
     3: 'Ошибка',
-    4: 'Отменено'
+    4: 'Отменено',
+    5: 'Подавлено'
 };
 
 document.addEventListener('DOMContentLoaded', () => {
@@ -49,7 +50,7 @@ async function fetchNotifications() {
         const data = await resp.json();
 
         list.innerHTML = '';
-        data.forEach(n => {
+        data.items.forEach(n => {
             const row = `
                 <tr>
                     <td>${n.id.substring(0, 8)}...</td>